@@ -0,0 +1,207 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/asdine/storm/v3/q"
+	"github.com/simplechain-org/crosshub/core"
+	db "github.com/simplechain-org/crosshub/database"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+// CourierSource reports the queues of a fabric courier.
+type CourierSource interface {
+	QueueDepths() (pending, executed int)
+}
+
+// RPCCourierQueues are the queue depths of the courier of a fabric chain,
+// Pending txs are to be sent to the target chain and Executed receipts are to
+// be committed on fabric.
+type RPCCourierQueues struct {
+	Chain    hexutil.Uint `json:"chain"`
+	Pending  hexutil.Uint `json:"pending"`
+	Executed hexutil.Uint `json:"executed"`
+}
+
+// RPCStoreCounts are the order counts by status of a store of a chain served
+// by the hub, Origin is the chain the orders are made on.
+type RPCStoreCounts struct {
+	Chain    hexutil.Uint            `json:"chain"`
+	Origin   hexutil.Uint            `json:"origin"`
+	Remote   bool                    `json:"remote"`
+	Total    hexutil.Uint            `json:"total"`
+	Statuses map[string]hexutil.Uint `json:"statuses"`
+}
+
+// RPCNodeInfo is the state of a running hub.
+type RPCNodeInfo struct {
+	Chains   []*RPCChainStatus   `json:"chains"`
+	Anchors  []*RPCAnchorSet     `json:"anchors"`
+	Peers    []*RPCPeer          `json:"peers"`
+	Couriers []*RPCCourierQueues `json:"couriers"`
+	Stores   []*RPCStoreCounts   `json:"stores"`
+}
+
+// AdminApi inspects the hub and operates its chain adapters, it is served as
+// the admin namespace to authenticated clients only.
+type AdminApi struct {
+	api      *CrossQueryApi
+	couriers map[uint8]CourierSource
+}
+
+// NewAdminApi returns the admin api of the chains served by api and the
+// couriers of the fabric chains, keyed by their purpose. Every exported method
+// is served, the sources are set here only.
+func NewAdminApi(api *CrossQueryApi, couriers map[uint8]CourierSource) *AdminApi {
+	return &AdminApi{api: api, couriers: couriers}
+}
+
+// NodeInfo returns the scan progress, the anchor sets, the peers, the courier
+// queues and the store counts of the hub.
+func (s *AdminApi) NodeInfo() *RPCNodeInfo {
+	return &RPCNodeInfo{
+		Chains:   s.api.Status(),
+		Anchors:  s.api.Anchors(),
+		Peers:    s.api.Peers(),
+		Couriers: s.Queues(),
+		Stores:   s.Stores(),
+	}
+}
+
+// PeerEvents returns the latest changes of the states of the other hubs of
+// the swarm, oldest first.
+func (s *AdminApi) PeerEvents() []*RPCPeerEvent {
+	return s.api.peerEvents()
+}
+
+// Queues returns the queue depths of the couriers of the fabric chains.
+func (s *AdminApi) Queues() []*RPCCourierQueues {
+	purposes := make([]int, 0, len(s.couriers))
+	for purpose := range s.couriers {
+		purposes = append(purposes, int(purpose))
+	}
+	sort.Ints(purposes)
+	var queues []*RPCCourierQueues
+	for _, purpose := range purposes {
+		pending, executed := s.couriers[uint8(purpose)].QueueDepths()
+		queues = append(queues, &RPCCourierQueues{
+			Chain:    hexutil.Uint(purpose),
+			Pending:  hexutil.Uint(pending),
+			Executed: hexutil.Uint(executed),
+		})
+	}
+	return queues
+}
+
+// Stores returns the order counts by status of the local and remote stores of
+// the chains served by the hub.
+func (s *AdminApi) Stores() []*RPCStoreCounts {
+	var stores []*RPCStoreCounts
+	for _, purpose := range s.api.purposes() {
+		chain := s.api.chains[purpose]
+		stores = append(stores, storeCounts(purpose, purpose, false, chain.localDb))
+		origins := make([]int, 0, len(chain.remoteDbs))
+		for origin := range chain.remoteDbs {
+			origins = append(origins, int(origin))
+		}
+		sort.Ints(origins)
+		for _, origin := range origins {
+			stores = append(stores, storeCounts(purpose, uint8(origin), true, chain.remoteDbs[uint8(origin)]))
+		}
+	}
+	return stores
+}
+
+func storeCounts(chain, origin uint8, remote bool, store *db.IndexDB) *RPCStoreCounts {
+	counts := &RPCStoreCounts{
+		Chain:    hexutil.Uint(chain),
+		Origin:   hexutil.Uint(origin),
+		Remote:   remote,
+		Statuses: make(map[string]hexutil.Uint),
+	}
+	for status := core.CtxStatusPending; status <= core.CtxStatusFinished; status++ {
+		n := store.Count(q.Eq(db.StatusField, uint8(status)))
+		counts.Statuses[status.String()] = hexutil.Uint(n)
+		counts.Total += hexutil.Uint(n)
+	}
+	return counts
+}
+
+// Rescan restarts the scan of the chain from block number from.
+func (s *AdminApi) Rescan(chain hexutil.Uint, from hexutil.Uint64) (bool, error) {
+	admin, err := s.admin(chain)
+	if err != nil {
+		return false, err
+	}
+	if err := admin.Rescan(uint64(from)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Rebroadcast sends the local order id of the chain to the other hubs again.
+func (s *AdminApi) Rebroadcast(chain hexutil.Uint, id common.Hash) (bool, error) {
+	admin, err := s.admin(chain)
+	if err != nil {
+		return false, err
+	}
+	if err := admin.Rebroadcast(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DropRemoteOrder removes the order id made on the chain origin from the
+// remote orders of the chain, the order must not be taken yet.
+func (s *AdminApi) DropRemoteOrder(chain, origin hexutil.Uint, id common.Hash) (bool, error) {
+	admin, err := s.admin(chain)
+	if err != nil {
+		return false, err
+	}
+	if origin > 255 {
+		return false, fmt.Errorf("invalid origin %d", origin)
+	}
+	if err := admin.DropRemote(uint8(origin), id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReloadAnchors reads the anchor sets of the chain from its contract.
+func (s *AdminApi) ReloadAnchors(chain hexutil.Uint) (bool, error) {
+	admin, err := s.admin(chain)
+	if err != nil {
+		return false, err
+	}
+	if err := admin.ReloadAnchors(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// admin returns the operations of the chain served by the hub.
+func (s *AdminApi) admin(purpose hexutil.Uint) (AdminSource, error) {
+	if purpose > 255 {
+		return nil, fmt.Errorf("invalid chain %d", purpose)
+	}
+	chain, ok := s.api.chains[uint8(purpose)]
+	if !ok || chain.admin == nil {
+		return nil, fmt.Errorf("chain %d not served", purpose)
+	}
+	return chain.admin, nil
+}
+
+// NewAdminHandler serves the admin namespace of admin over http, requests are
+// authenticated by Auth.Handler.
+func NewAdminHandler(admin *AdminApi) (http.Handler, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("admin", admin); err != nil {
+		return nil, err
+	}
+	return server, nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+type testCourier struct{}
+
+func (testCourier) QueueDepths() (int, int) { return 3, 1 }
+
+// bearerTransport adds the bearer token to the requests.
+type bearerTransport string
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.Header.Set("Authorization", "Bearer "+string(t))
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestAdminApi(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	admin := new(testAdmin)
+	api.SetAdminSource(2, admin)
+	writeCtx(t, local, 1, owner, "", 2, 5, 100, core.CtxStatusWaiting)
+	writeCtx(t, local, 2, owner, "", 2, 5, 100, core.CtxStatusFinished)
+	writeCtx(t, remote, 3, owner, taker, 5, 2, 100, core.CtxStatusPending)
+
+	handler, err := NewAdminHandler(NewAdminApi(api, map[uint8]CourierSource{6: testCourier{}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := NewAuth(repo.Auth{}, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(auth.Handler(RoleAdmin, handler))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"admin_nodeInfo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unauthenticated request: status %d", resp.StatusCode)
+	}
+
+	client, err := rpc.DialHTTPWithClient(server.URL, &http.Client{Transport: bearerTransport("secret")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var info RPCNodeInfo
+	if err := client.Call(&info, "admin_nodeInfo"); err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Stores) != 2 || len(info.Couriers) != 1 || len(info.Chains) != 1 {
+		t.Fatalf("unexpected node info: %+v", info)
+	}
+	if s := info.Stores[0]; s.Remote || s.Total != 2 || s.Statuses["waiting"] != 1 || s.Statuses["finished"] != 1 {
+		t.Fatalf("unexpected local counts: %+v", s)
+	}
+	if s := info.Stores[1]; !s.Remote || s.Origin != 5 || s.Total != 1 || s.Statuses["pending"] != 1 {
+		t.Fatalf("unexpected remote counts: %+v", s)
+	}
+	if q := info.Couriers[0]; q.Chain != 6 || q.Pending != 3 || q.Executed != 1 {
+		t.Fatalf("unexpected courier queues: %+v", q)
+	}
+
+	var ok bool
+	if err := client.Call(&ok, "admin_rescan", hexutil.Uint(2), hexutil.Uint64(42)); err != nil || !ok || admin.rescanned != 42 {
+		t.Fatalf("rescan: %v, %v, from %d", ok, err, admin.rescanned)
+	}
+	if err := client.Call(&ok, "admin_rebroadcast", hexutil.Uint(7), common.Hash{}); err == nil {
+		t.Fatal("rebroadcast on an unserved chain")
+	}
+	if err := client.Call(&ok, "admin_dropRemoteOrder", hexutil.Uint(2), hexutil.Uint(300), common.Hash{}); err == nil {
+		t.Fatal("drop with an invalid origin")
+	}
+}
@@ -1,14 +1,159 @@
 package api
 
 import (
+	"sort"
+	"strings"
+
+	"github.com/asdine/storm/v3/q"
 	"github.com/simplechain-org/crosshub/core"
 	db "github.com/simplechain-org/crosshub/database"
+	"github.com/simplechain-org/go-simplechain/common"
+)
+
+// ctxOrders are the sort orders of the orders paged by CtxContentByPage.
+var ctxOrders = map[string]db.FieldName{
+	"price":     db.PriceIndex,
+	"value":     db.ValueField,
+	"insertion": db.PK,
+}
+
+var (
+	// unfinished matches the local orders not finished yet
+	unfinished = q.Not(q.Eq(db.StatusField, uint8(core.CtxStatusFinished)))
+	// waiting matches the remote orders that can be taken
+	waiting = q.Eq(db.StatusField, uint8(core.CtxStatusWaiting))
 )
 
-func (s *CrossQueryApi)QueryByPage(localSize, localPage, remoteSize, remotePage int) (
-	locals map[uint8][]*core.CrossTransaction, remotes map[uint8][]*core.CrossTransaction) {
-	orderBy := []db.FieldName{db.PriceIndex}
-	locals = map[uint8][]*core.CrossTransaction{2:s.localDb.Query(localSize,localPage,orderBy,false)}
-	remotes = map[uint8][]*core.CrossTransaction{5:s.remoteDb.Query(localSize,localPage,orderBy,false)}
+// QueryByPage returns a page of the unfinished orders of the local stores and a
+// page of the orders waiting to be taken of the remote stores, sorted by orderBy
+// over all the stores, with the count of such orders over all pages.
+func (s *CrossQueryApi) QueryByPage(localSize, localPage, remoteSize, remotePage int, orderBy db.FieldName, reverse bool) (
+	locals, remotes []*core.CrossTransactionWithSignatures, localTotal, remoteTotal int) {
+	locals, localTotal = s.queryPage(false, localSize, localPage, orderBy, reverse, unfinished)
+	remotes, remoteTotal = s.queryPage(true, remoteSize, remotePage, orderBy, reverse, waiting)
 	return
-}
\ No newline at end of file
+}
+
+// queryPage returns the page startPage of the ctxs matching filter in the local
+// or the remote stores, and their count. A page of the merged stores is among
+// the first pageSize*startPage ctxs of each store, so only those are read and
+// sorted together.
+func (s *CrossQueryApi) queryPage(remote bool, pageSize, startPage int, orderBy db.FieldName, reverse bool, filter q.Matcher) (
+	[]*core.CrossTransactionWithSignatures, int) {
+	var (
+		ctxs  []*core.CrossTransactionWithSignatures
+		total int
+	)
+	for _, store := range s.stores() {
+		if store.remote != remote {
+			continue
+		}
+		total += store.db.Count(filter)
+		if pageSize > 0 && startPage <= 0 {
+			continue
+		}
+		ctxs = append(ctxs, store.db.Query(pageSize*startPage, 1, []db.FieldName{orderBy}, reverse, filter)...)
+	}
+	db.SortCtxs(ctxs, []db.FieldName{orderBy}, reverse)
+	return page(ctxs, pageSize, startPage), total
+}
+
+// storeRef is a store queried by the api, ctxs of remote stores still
+// collecting their signature quorum are hidden.
+type storeRef struct {
+	db     *db.IndexDB
+	remote bool
+}
+
+func (r storeRef) visible(ctx *core.CrossTransactionWithSignatures) bool {
+	return !r.remote || ctx.Status != core.CtxStatusPending
+}
+
+// stores returns the local stores, then the remote stores of the chains in
+// purpose order.
+func (s *CrossQueryApi) stores() []storeRef {
+	var locals, remotes []storeRef
+	for _, purpose := range s.purposes() {
+		chain := s.chains[purpose]
+		locals = append(locals, storeRef{db: chain.localDb})
+		origins := make([]int, 0, len(chain.remoteDbs))
+		for origin := range chain.remoteDbs {
+			origins = append(origins, int(origin))
+		}
+		sort.Ints(origins)
+		for _, origin := range origins {
+			remotes = append(remotes, storeRef{db: chain.remoteDbs[uint8(origin)], remote: true})
+		}
+	}
+	return append(locals, remotes...)
+}
+
+// findCtx returns the ctx with the id or the maker tx hash, and whether it is
+// made on a chain served by the hub.
+func (s *CrossQueryApi) findCtx(hash common.Hash) (*core.CrossTransactionWithSignatures, bool) {
+	for _, field := range []db.FieldName{db.CtxIdIndex, db.TxHashIndex} {
+		for _, store := range s.stores() {
+			if ctx := store.db.One(field, hash); ctx != nil && store.visible(ctx) {
+				return ctx, !store.remote
+			}
+		}
+	}
+	return nil, false
+}
+
+// findBy returns the ctxs whose indexed field equals one of keys, ctxs made on
+// the chains served by the hub first. A ctx stored by several chains is
+// returned once.
+func (s *CrossQueryApi) findBy(field db.FieldName, keys ...interface{}) (locals, remotes []*core.CrossTransactionWithSignatures) {
+	seen := make(map[common.Hash]bool)
+	for _, store := range s.stores() {
+		for _, key := range keys {
+			for _, ctx := range store.db.Find(field, key) {
+				if seen[ctx.ID()] || !store.visible(ctx) {
+					continue
+				}
+				seen[ctx.ID()] = true
+				if store.remote {
+					remotes = append(remotes, ctx)
+				} else {
+					locals = append(locals, ctx)
+				}
+			}
+		}
+	}
+	return
+}
+
+// addressKeys returns the forms an owner or taker address may be stored in,
+// makers pass them as free strings to the contract.
+func addressKeys(addr string) []interface{} {
+	if !common.IsHexAddress(addr) {
+		return []interface{}{addr}
+	}
+	keys := []interface{}{addr}
+	for _, key := range []string{common.HexToAddress(addr).String(), strings.ToLower(common.HexToAddress(addr).String())} {
+		if key != addr {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// page returns the page startPage of ctxs, all of them if pageSize is 0.
+func page(ctxs []*core.CrossTransactionWithSignatures, pageSize, startPage int) []*core.CrossTransactionWithSignatures {
+	if pageSize <= 0 {
+		return ctxs
+	}
+	if startPage <= 0 {
+		return nil
+	}
+	begin := pageSize * (startPage - 1)
+	if begin >= len(ctxs) {
+		return nil
+	}
+	end := begin + pageSize
+	if end > len(ctxs) {
+		end = len(ctxs)
+	}
+	return ctxs[begin:end]
+}
@@ -0,0 +1,418 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+// Role is the access level of a credential, a role grants the roles below it.
+type Role uint8
+
+const (
+	RoleRead  Role = iota + 1 // the cross namespace and the gateway
+	RoleAdmin                 // the admin namespace too
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleRead:
+		return "read"
+	case RoleAdmin:
+		return "admin"
+	}
+	return "unknown"
+}
+
+// ParseRole returns the role named s.
+func ParseRole(s string) (Role, error) {
+	switch strings.ToLower(s) {
+	case "read":
+		return RoleRead, nil
+	case "admin":
+		return RoleAdmin, nil
+	}
+	return 0, fmt.Errorf("unknown role %q", s)
+}
+
+// maxAuditBody is the size of the request bodies read for the audit log, like
+// the request size limit of the rpc server.
+const maxAuditBody = 5 * 1024 * 1024
+
+var (
+	errNoCredential      = errors.New("missing credential")
+	errInvalidCredential = errors.New("invalid credential")
+
+	audit = log.New("module", "audit")
+)
+
+// roleError denies a credential the role of a request.
+type roleError struct {
+	role Role
+}
+
+func (e *roleError) Error() string {
+	return "role " + e.role.String() + " required"
+}
+
+// limitError denies a request over the rate limit of its credential.
+type limitError struct {
+	wait time.Duration
+}
+
+func (e *limitError) Error() string {
+	return "rate limit exceeded"
+}
+
+// credential is an authenticated client.
+type credential struct {
+	name    string
+	role    Role
+	limiter *limiter
+}
+
+// Auth authenticates the requests of the rpc endpoints by api key or jwt,
+// limits their rate by credential and writes them to the audit log.
+type Auth struct {
+	keys     map[string]*credential
+	secret   []byte
+	required bool
+
+	rate  float64
+	burst int
+	// anonymous is shared by the read requests without credential while no
+	// credential is required
+	anonymous *credential
+	// subjects keeps the jwt credentials by subject and role, their limits
+	// are kept across tokens
+	subjects map[string]*credential
+	mu       sync.Mutex
+}
+
+// NewAuth returns the authentication of cfg. adminToken, if set, is an admin
+// key of the legacy [admin] section.
+func NewAuth(cfg repo.Auth, adminToken string) (*Auth, error) {
+	a := &Auth{
+		keys:     make(map[string]*credential),
+		secret:   []byte(cfg.JwtSecret),
+		required: len(cfg.Keys) > 0 || cfg.JwtSecret != "",
+		rate:     cfg.Rate,
+		burst:    cfg.Burst,
+		subjects: make(map[string]*credential),
+	}
+	a.anonymous = &credential{name: "anonymous", role: RoleRead, limiter: newLimiter(cfg.Rate, cfg.Burst)}
+	for _, key := range cfg.Keys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("auth key %q: empty key", key.Name)
+		}
+		if _, ok := a.keys[key.Key]; ok {
+			return nil, fmt.Errorf("auth key %q: duplicate key", key.Name)
+		}
+		role, err := ParseRole(key.Role)
+		if err != nil {
+			return nil, fmt.Errorf("auth key %q: %w", key.Name, err)
+		}
+		rate, burst := key.Rate, key.Burst
+		if rate == 0 {
+			rate = cfg.Rate
+		}
+		if burst == 0 {
+			burst = cfg.Burst
+		}
+		a.keys[key.Key] = &credential{name: key.Name, role: role, limiter: newLimiter(rate, burst)}
+	}
+	// the legacy admin token does not close the read endpoints
+	if adminToken != "" {
+		a.keys[adminToken] = &credential{name: "admin", role: RoleAdmin, limiter: newLimiter(cfg.Rate, cfg.Burst)}
+	}
+	return a, nil
+}
+
+// Grants reports whether a credential of role can be presented, endpoints of
+// roles without credentials are not served.
+func (a *Auth) Grants(role Role) bool {
+	if a == nil {
+		return role == RoleRead
+	}
+	if role == RoleRead && !a.required || len(a.secret) > 0 {
+		return true
+	}
+	for _, cred := range a.keys {
+		if cred.role >= role {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler serves the requests of the credentials granted role by next. Read
+// requests are open while no credential is required, they share the default
+// rate limit. Websocket connections are checked on the upgrade only, see
+// WebsocketHandler.
+func (a *Auth) Handler(role Role, next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// cors preflight requests carry no credentials
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cred, err := a.authenticate(r)
+		err = a.admit("rpc", role, cred, err, "remote", r.RemoteAddr, "path", r.URL.Path,
+			"methods", strings.Join(rpcMethods(r), ","))
+		var (
+			denied  *roleError
+			limited *limitError
+		)
+		switch {
+		case err == nil:
+			next.ServeHTTP(w, r)
+		case errors.As(err, &denied):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.As(err, &limited):
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limited.wait.Seconds()))))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			w.Header().Set("WWW-Authenticate", `Bearer realm="crosshub"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		}
+	})
+}
+
+// admit admits a request of role by cred, the credential looked up for it or
+// the error of the lookup, and writes it to the audit log of kind requests.
+// fields describe the request in the log.
+func (a *Auth) admit(kind string, role Role, cred *credential, err error, fields ...interface{}) error {
+	if err == errNoCredential && role == RoleRead && !a.required {
+		cred, err = a.anonymous, nil
+	}
+	if err != nil {
+		audit.Warn(kind+" request denied", append(fields, "err", err)...)
+		return err
+	}
+	fields = append([]interface{}{"credential", cred.name, "role", cred.role}, fields...)
+	if cred.role < role {
+		err = &roleError{role: role}
+		audit.Warn(kind+" request denied", append(fields, "err", err)...)
+		return err
+	}
+	if wait := cred.limiter.take(time.Now()); wait > 0 {
+		audit.Warn(kind+" request limited", fields...)
+		return &limitError{wait: wait}
+	}
+	audit.Info(kind+" request", fields...)
+	return nil
+}
+
+// authenticate returns the credential of the api key or the jwt of r.
+func (a *Auth) authenticate(r *http.Request) (*credential, error) {
+	token, err := requestToken(r)
+	if err != nil {
+		return nil, err
+	}
+	return a.lookup(token)
+}
+
+// requestToken returns the api key or the jwt of r, taken from the
+// Authorization or X-Api-Key header. Websocket upgrades of browsers can't set
+// headers, they may pass it as the access_token query parameter.
+func requestToken(r *http.Request) (string, error) {
+	token, err := bearerToken(r.Header.Get("X-Api-Key"), r.Header.Get("Authorization"))
+	if err != nil {
+		return "", err
+	}
+	if token == "" && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		token = r.URL.Query().Get("access_token")
+	}
+	return token, nil
+}
+
+// bearerToken returns the api key, or else the bearer token of authorization.
+func bearerToken(apiKey, authorization string) (string, error) {
+	if apiKey != "" || authorization == "" {
+		return apiKey, nil
+	}
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return "", errInvalidCredential
+	}
+	return strings.TrimPrefix(authorization, "Bearer "), nil
+}
+
+// lookup returns the credential of an api key or a jwt.
+func (a *Auth) lookup(token string) (*credential, error) {
+	if token == "" {
+		return nil, errNoCredential
+	}
+	for key, cred := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(token)) == 1 {
+			return cred, nil
+		}
+	}
+	if len(a.secret) > 0 && strings.Count(token, ".") == 2 {
+		return a.verifyJWT(token, time.Now())
+	}
+	return nil, errInvalidCredential
+}
+
+// jwtClaims are the claims of the jwt credentials, times are unix seconds.
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+}
+
+// verifyJWT returns the credential of an HS256 jwt signed by the secret.
+func (a *Auth) verifyJWT(token string, now time.Time) (*credential, error) {
+	parts := strings.Split(token, ".")
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return nil, errInvalidCredential
+	}
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, errInvalidCredential
+	}
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" {
+		return nil, errInvalidCredential
+	}
+	if claims.ExpiresAt != 0 && now.Unix() >= claims.ExpiresAt || claims.NotBefore != 0 && now.Unix() < claims.NotBefore {
+		return nil, fmt.Errorf("%w: token expired or not valid yet", errInvalidCredential)
+	}
+	role, err := ParseRole(claims.Role)
+	if err != nil {
+		return nil, errInvalidCredential
+	}
+	return a.subject(claims.Subject, role), nil
+}
+
+// subject returns the credential of a jwt subject, one per subject and role.
+func (a *Auth) subject(name string, role Role) *credential {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := role.String() + ":" + name
+	cred, ok := a.subjects[key]
+	if !ok {
+		cred = &credential{name: "jwt:" + name, role: role, limiter: newLimiter(a.rate, a.burst)}
+		a.subjects[key] = cred
+	}
+	return cred
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// rpcMethods returns the json-rpc methods called by r, the body is kept for
+// the handler.
+func rpcMethods(r *http.Request) []string {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return nil
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxAuditBody+1))
+	r.Body = readCloser{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) > maxAuditBody {
+		return nil
+	}
+	calls, _ := parseCalls(body)
+	return callMethods(calls)
+}
+
+// rpcCall is a json-rpc call of a request or a websocket message.
+type rpcCall struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+// parseCalls returns the calls of a json-rpc message, a call or a batch.
+func parseCalls(msg []byte) (calls []rpcCall, batch bool) {
+	var call rpcCall
+	if err := json.Unmarshal(msg, &call); err == nil {
+		return []rpcCall{call}, false
+	}
+	if err := json.Unmarshal(msg, &calls); err != nil {
+		return nil, false
+	}
+	return calls, true
+}
+
+func callMethods(calls []rpcCall) []string {
+	if calls == nil {
+		return nil
+	}
+	methods := make([]string, len(calls))
+	for i, call := range calls {
+		methods[i] = call.Method
+	}
+	return methods
+}
+
+// readCloser reads the body read for the audit log again.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// limiter is a token bucket of rate tokens per second holding burst tokens,
+// a zero rate is unlimited.
+type limiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	mu     sync.Mutex
+}
+
+func newLimiter(rate float64, burst int) *limiter {
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(rate)))
+	}
+	return &limiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// take takes a token at now, it returns the time to wait for the next token
+// if there is none.
+func (l *limiter) take(now time.Time) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	}
+	l.tokens--
+	return 0
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+func testJWT(t *testing.T, secret string, claims jwtClaims) string {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func serveAuth(handler http.Handler, header, value string) int {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"cross_status"}`))
+	if header != "" {
+		r.Header.Set(header, value)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	// without credentials reads are open and only the admin token is admin
+	open, err := NewAuth(repo.Auth{}, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := serveAuth(open.Handler(RoleRead, ok), "", ""); code != http.StatusOK {
+		t.Fatalf("open read: %d", code)
+	}
+	if code := serveAuth(open.Handler(RoleAdmin, ok), "", ""); code != http.StatusUnauthorized {
+		t.Fatalf("open admin: %d", code)
+	}
+	if code := serveAuth(open.Handler(RoleAdmin, ok), "Authorization", "Bearer token"); code != http.StatusOK {
+		t.Fatalf("admin token: %d", code)
+	}
+
+	auth, err := NewAuth(repo.Auth{
+		JwtSecret: "jwt secret",
+		Keys: []repo.ApiKey{
+			{Name: "partner", Key: "partner-key", Role: "read", Rate: 1, Burst: 2},
+			{Name: "ops", Key: "ops-key", Role: "admin"},
+		},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, admin := auth.Handler(RoleRead, ok), auth.Handler(RoleAdmin, ok)
+	for _, test := range []struct {
+		name          string
+		handler       http.Handler
+		header, value string
+		code          int
+	}{
+		{"no credential", read, "", "", http.StatusUnauthorized},
+		{"unknown key", read, "X-Api-Key", "other", http.StatusUnauthorized},
+		{"read key", read, "X-Api-Key", "partner-key", http.StatusOK},
+		{"read key on admin", admin, "X-Api-Key", "partner-key", http.StatusForbidden},
+		{"admin key", admin, "Authorization", "Bearer ops-key", http.StatusOK},
+		{"admin key on read", read, "Authorization", "Bearer ops-key", http.StatusOK},
+		{"admin jwt", admin, "Authorization", "Bearer " + testJWT(t, "jwt secret", jwtClaims{Subject: "dashboard", Role: "admin"}), http.StatusOK},
+		{"read jwt on admin", admin, "Authorization", "Bearer " + testJWT(t, "jwt secret", jwtClaims{Subject: "dashboard", Role: "read"}), http.StatusForbidden},
+		{"expired jwt", read, "Authorization", "Bearer " + testJWT(t, "jwt secret", jwtClaims{Subject: "dashboard", Role: "read", ExpiresAt: time.Now().Unix() - 1}), http.StatusUnauthorized},
+		{"forged jwt", read, "Authorization", "Bearer " + testJWT(t, "other secret", jwtClaims{Subject: "dashboard", Role: "admin"}), http.StatusUnauthorized},
+	} {
+		if code := serveAuth(test.handler, test.header, test.value); code != test.code {
+			t.Errorf("%s: status %d, want %d", test.name, code, test.code)
+		}
+	}
+
+	// the burst of the partner key is taken by one request above and one here
+	if code := serveAuth(read, "X-Api-Key", "partner-key"); code != http.StatusOK {
+		t.Fatalf("within burst: %d", code)
+	}
+	if code := serveAuth(read, "X-Api-Key", "partner-key"); code != http.StatusTooManyRequests {
+		t.Fatalf("over the rate limit: %d", code)
+	}
+	if !auth.Grants(RoleAdmin) || !open.Grants(RoleAdmin) {
+		t.Fatal("admin credentials not granted")
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(2, 1)
+	now := time.Now()
+	if wait := l.take(now); wait != 0 {
+		t.Fatalf("first token: wait %v", wait)
+	}
+	if wait := l.take(now); wait != 500*time.Millisecond {
+		t.Fatalf("empty bucket: wait %v", wait)
+	}
+	if wait := l.take(now.Add(500 * time.Millisecond)); wait != 0 {
+		t.Fatalf("refilled token: wait %v", wait)
+	}
+}
+
+func TestRPCMethods(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"method":"cross_status"},{"method":"admin_rescan"}]`))
+	if methods := rpcMethods(r); strings.Join(methods, ",") != "cross_status,admin_rescan" {
+		t.Fatalf("methods %v", methods)
+	}
+	var batch []map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil || len(batch) != 2 {
+		t.Fatalf("body not kept: %v, %v", batch, err)
+	}
+}
+
+func TestAuth_Anonymous(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	open, err := NewAuth(repo.Auth{Rate: 0.001, Burst: 1}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := open.Handler(RoleRead, ok)
+	if code := serveAuth(read, "", ""); code != http.StatusOK {
+		t.Fatalf("open read: %d", code)
+	}
+	// anonymous clients share the default limit
+	if code := serveAuth(read, "", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("over the anonymous limit: %d", code)
+	}
+}
+
+type echoService struct{}
+
+func (echoService) Echo(s string) string { return s }
+
+func TestAuth_Websocket(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("test", echoService{}); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := NewAuth(repo.Auth{Keys: []repo.ApiKey{
+		{Name: "partner", Key: "partner-key", Role: "read", Rate: 0.001, Burst: 2},
+	}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(auth.WebsocketHandler(RoleRead, server, nil))
+	defer httpServer.Close()
+	endpoint := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := rpc.DialWebsocket(ctx, endpoint, ""); err == nil {
+		t.Fatal("websocket upgraded without credential")
+	}
+	client, err := rpc.DialWebsocket(ctx, endpoint+"?access_token=partner-key", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	// the upgrade takes a token of the burst and the first call the other one
+	var echo string
+	if err := client.CallContext(ctx, &echo, "test_echo", "hub"); err != nil || echo != "hub" {
+		t.Fatalf("echo %q: %v", echo, err)
+	}
+	for i := 0; i < 2; i++ {
+		err := client.CallContext(ctx, &echo, "test_echo", "hub")
+		if rpcErr, ok := err.(rpc.Error); !ok || rpcErr.ErrorCode() != errLimitedCode {
+			t.Fatalf("call over the limit: %v", err)
+		}
+	}
+}
@@ -1,10 +1,18 @@
 package api
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/simplechain-org/crosshub/core"
 	db "github.com/simplechain-org/crosshub/database"
 	"github.com/simplechain-org/go-simplechain/common"
 	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/rpc"
 )
 
 type RPCCrossTransaction struct {
@@ -18,16 +26,17 @@ type RPCCrossTransaction struct {
 	Origin    hexutil.Uint  `json:"origin"`
 	Purpose   hexutil.Uint  `json:"purpose"`
 	Payload   hexutil.Bytes	`json:"payload"`
+	Status    string        `json:"status"`
 
 	// Signature values
-	V *hexutil.Big 		`json:"v"`
-	R *hexutil.Big 		`json:"r"`
-	S *hexutil.Big 		`json:"s"`
+	V []*hexutil.Big 	`json:"v"`
+	R []*hexutil.Big 	`json:"r"`
+	S []*hexutil.Big 	`json:"s"`
 }
 
 // newRPCCrossTransaction returns a transaction that will serialize to the RPC
 // representation, with the given location metadata set (if available).
-func newRPCCrossTransaction(tx *core.CrossTransaction) *RPCCrossTransaction {
+func newRPCCrossTransaction(tx *core.CrossTransactionWithSignatures) *RPCCrossTransaction {
 	if tx == nil {
 		return nil
 	}
@@ -42,64 +51,440 @@ func newRPCCrossTransaction(tx *core.CrossTransaction) *RPCCrossTransaction {
 		Origin:           hexutil.Uint(tx.Data.Origin),
 		Purpose:          hexutil.Uint(tx.Data.Purpose),
 		Payload:          tx.Data.Payload,
-
-		V:    			  (*hexutil.Big)(tx.Data.V),
-		R: 				  (*hexutil.Big)(tx.Data.R),
-		S:                (*hexutil.Big)(tx.Data.S),
+		Status:           tx.Status.String(),
+	}
+	for _, v := range tx.Data.V {
+		result.V = append(result.V, (*hexutil.Big)(v))
+	}
+	for _, r := range tx.Data.R {
+		result.R = append(result.R, (*hexutil.Big)(r))
+	}
+	for _, s := range tx.Data.S {
+		result.S = append(result.S, (*hexutil.Big)(s))
 	}
 
 	return result
 }
 
 type RPCPageCrossTransactions struct {
-	Data map[uint8][]*RPCCrossTransaction `json:"data"`
-	//Total int                               `json:"total"`
+	Data  map[uint8][]*RPCCrossTransaction `json:"data"`
+	Total int                              `json:"total"`
+}
+
+// newRPCPage groups the page of ctxs by the chain they are made on, total is
+// the count of ctxs over all pages.
+func newRPCPage(ctxs []*core.CrossTransactionWithSignatures, total int) RPCPageCrossTransactions {
+	page := RPCPageCrossTransactions{Data: make(map[uint8][]*RPCCrossTransaction), Total: total}
+	for _, ctx := range ctxs {
+		page.Data[ctx.Data.Origin] = append(page.Data[ctx.Data.Origin], newRPCCrossTransaction(ctx))
+	}
+	return page
+}
+
+// RPCAnchorSet is the anchor set trusted by a chain for the ctxs of a remote chain.
+type RPCAnchorSet struct {
+	Chain            hexutil.Uint     `json:"chain"`
+	Purpose          hexutil.Uint     `json:"purpose"`
+	Anchors          []common.Address `json:"anchors"`
+	SignConfirmCount hexutil.Uint     `json:"signConfirmCount"`
+	Number           hexutil.Uint64   `json:"number"`
+}
+
+// RPCAnchorChange is a change of the anchor set trusted by a chain.
+type RPCAnchorChange struct {
+	Chain            hexutil.Uint     `json:"chain"`
+	Purpose          hexutil.Uint     `json:"purpose"`
+	Number           hexutil.Uint64   `json:"number"`
+	Added            []common.Address `json:"added"`
+	Removed          []common.Address `json:"removed"`
+	SignConfirmCount hexutil.Uint     `json:"signConfirmCount"`
+}
+
+// AnchorSource reports the anchor sets trusted by a chain adapter.
+type AnchorSource interface {
+	AnchorSets() []core.AnchorSet
+	AnchorChanges() []core.AnchorChange
+}
+
+// RPCSubmitElection is a makerFinish submitter election of the local anchor.
+type RPCSubmitElection struct {
+	Chain      hexutil.Uint     `json:"chain"`
+	CtxId      common.Hash      `json:"ctxId"`
+	Purpose    hexutil.Uint     `json:"purpose"`
+	Submitter  common.Address   `json:"submitter"`
+	Candidates []common.Address `json:"candidates"`
+	Rank       hexutil.Uint     `json:"rank"`
+	Required   hexutil.Uint     `json:"required"`
+	Deadline   hexutil.Uint64   `json:"deadline"`
+	State      string           `json:"state"`
+	TxHash     common.Hash      `json:"txHash"`
+}
+
+// ElectionSource reports the submitter elections of a chain adapter.
+type ElectionSource interface {
+	Elections() []core.SubmitElection
+}
+
+// RPCPeer is a hub of the swarm. State is its liveness since Since, LastSeen
+// is the time of its last heartbeat and Rtt the round trip of it in
+// milliseconds, times are unix seconds.
+type RPCPeer struct {
+	Id        hexutil.Uint64 `json:"id"`
+	PeerId    string         `json:"peerId"`
+	Addrs     []string       `json:"addrs"`
+	Connected bool           `json:"connected"`
+	State     string         `json:"state"`
+	Since     hexutil.Uint64 `json:"since"`
+	LastSeen  hexutil.Uint64 `json:"lastSeen"`
+	Rtt       hexutil.Uint64 `json:"rtt"`
+}
+
+// RPCPeerEvent is a change of the state of a hub of the swarm at Time, in unix
+// seconds.
+type RPCPeerEvent struct {
+	Id     hexutil.Uint64 `json:"id"`
+	PeerId string         `json:"peerId"`
+	State  string         `json:"state"`
+	Time   hexutil.Uint64 `json:"time"`
+	Reason string         `json:"reason"`
+}
+
+// PeerSource reports the other hubs of the swarm.
+type PeerSource interface {
+	OtherPeers() map[uint64]*peer.AddrInfo
+	Connected(id uint64) bool
+	PeerStatus(id uint64) core.PeerStatus
+	PeerEvents() []core.PeerEvent
+}
+
+// RPCChainStatus is the scan progress and the order counts of a chain served
+// by the hub.
+type RPCChainStatus struct {
+	Chain        hexutil.Uint   `json:"chain"`
+	Height       hexutil.Uint64 `json:"height"`
+	Pending      hexutil.Uint   `json:"pending"`
+	LocalOrders  hexutil.Uint   `json:"localOrders"`
+	RemoteOrders hexutil.Uint   `json:"remoteOrders"`
+}
+
+// StatusSource reports the scan progress of a chain adapter.
+type StatusSource interface {
+	Height() uint64
+	PendingCount() int
+}
+
+// AdminSource operates a chain adapter.
+type AdminSource interface {
+	Rescan(from uint64) error
+	ReloadAnchors() error
+	DropRemote(origin uint8, id common.Hash) error
+	Rebroadcast(id common.Hash) error
 }
 
 type CrossApi interface {
-	CtxContentByPage(int, int, int, int) map[string]RPCPageCrossTransactions
-	//CtxQuery(hash common.Hash) *RPCCrossTransaction
-	//CtxQueryDestValue(value *hexutil.Big, pageSize, startPage int) *RPCPageCrossTransactions
-	//CtxOwner(from common.Address) map[string]map[uint8][]*RPCCrossTransaction
-	//CtxOwnerByPage(from common.Address, pageSize, startPage int) RPCPageCrossTransactions
-	//CtxTakerByPage(to common.Address, pageSize, startPage int) RPCPageCrossTransactions
+	CtxContentByPage(localSize, localPage, remoteSize, remotePage int, order *string) (map[string]RPCPageCrossTransactions, error)
+	Anchors() []*RPCAnchorSet
+	AnchorChanges() []*RPCAnchorChange
+	Elections() []*RPCSubmitElection
+	Peers() []*RPCPeer
+	Status() []*RPCChainStatus
+	CtxQuery(hash common.Hash) *RPCCrossTransaction
+	CtxQueryDestValue(value *hexutil.Big, pageSize, startPage int) *RPCPageCrossTransactions
+	CtxOwner(from string) map[string]map[uint8][]*RPCCrossTransaction
+	CtxOwnerByPage(from string, pageSize, startPage int) RPCPageCrossTransactions
+	CtxTakerByPage(to string, pageSize, startPage int) RPCPageCrossTransactions
+	Orders(ctx context.Context, filter *OrderFilter) (*rpc.Subscription, error)
+}
+
+// chainStores keeps the stores of a chain served by the hub.
+type chainStores struct {
+	localDb   *db.IndexDB
+	remoteDbs map[uint8]*db.IndexDB
+	anchors   AnchorSource
+	elections ElectionSource
+	status    StatusSource
+	admin     AdminSource
 }
 
 type CrossQueryApi struct {
-	remoteDb *db.IndexDB
-	localDb  *db.IndexDB
+	chains map[uint8]*chainStores
+	orders orderSubs
+	peers  PeerSource
+}
+
+func NewPublicCrossQueryApi() *CrossQueryApi {
+	return &CrossQueryApi{
+		chains: make(map[uint8]*chainStores),
+		orders: orderSubs{subs: make(map[rpc.ID]*orderSub)},
+	}
+}
+
+// AddChain adds the stores of the chain purpose, remotes are the stores of the
+// orders made on other chains, keyed by their purpose. Chains must be added
+// before the api is served and the stores are written, their changes are
+// pushed to the order subscribers.
+func (s *CrossQueryApi) AddChain(purpose uint8, local *db.IndexDB, remotes map[uint8]*db.IndexDB) {
+	s.chains[purpose] = &chainStores{localDb: local, remoteDbs: remotes}
+	local.Watch(s.publish(purpose, false))
+	for _, remote := range remotes {
+		remote.Watch(s.publish(purpose, true))
+	}
+}
+
+// SetAnchorSource sets the anchor sets of the chain purpose, the chain must be
+// added first.
+func (s *CrossQueryApi) SetAnchorSource(purpose uint8, anchors AnchorSource) {
+	if chain, ok := s.chains[purpose]; ok {
+		chain.anchors = anchors
+	}
+}
+
+// SetElectionSource sets the submitter elections of the chain purpose, the
+// chain must be added first.
+func (s *CrossQueryApi) SetElectionSource(purpose uint8, elections ElectionSource) {
+	if chain, ok := s.chains[purpose]; ok {
+		chain.elections = elections
+	}
+}
+
+// SetStatusSource sets the scan progress of the chain purpose, the chain must
+// be added first.
+func (s *CrossQueryApi) SetStatusSource(purpose uint8, status StatusSource) {
+	if chain, ok := s.chains[purpose]; ok {
+		chain.status = status
+	}
 }
 
-func NewPublicCrossQueryApi(db,idb *db.IndexDB) *CrossQueryApi {
-	return &CrossQueryApi{remoteDb: db,localDb: idb}
+// SetAdminSource sets the operations of the chain purpose, the chain must be
+// added first. They are served by the admin services only.
+func (s *CrossQueryApi) SetAdminSource(purpose uint8, admin AdminSource) {
+	if chain, ok := s.chains[purpose]; ok {
+		chain.admin = admin
+	}
+}
+
+// SetPeerSource sets the swarm of the hub.
+func (s *CrossQueryApi) SetPeerSource(peers PeerSource) {
+	s.peers = peers
+}
+
+// CtxContentByPage returns a page of the unfinished orders made on the chains
+// served by the hub and a page of the remote orders waiting to be taken, the
+// pages are taken from every store and grouped by the chain the orders are made
+// on. order is "price" (default), "value" or "insertion", ascending unless it
+// is prefixed by "-".
+func (s *CrossQueryApi) CtxContentByPage(localSize, localPage, remoteSize, remotePage int, order *string) (map[string]RPCPageCrossTransactions, error) {
+	var (
+		orderBy = db.PriceIndex
+		reverse bool
+	)
+	if order != nil && *order != "" {
+		name := strings.TrimPrefix(*order, "-")
+		field, ok := ctxOrders[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown order %q", *order)
+		}
+		orderBy, reverse = field, name != *order
+	}
+	locals, remotes, localTotal, remoteTotal := s.QueryByPage(localSize, localPage, remoteSize, remotePage, orderBy, reverse)
+	return map[string]RPCPageCrossTransactions{
+		"local":  newRPCPage(locals, localTotal),
+		"remote": newRPCPage(remotes, remoteTotal),
+	}, nil
+}
+
+// CtxQuery returns the ctx with the id or the hash of its maker tx.
+func (s *CrossQueryApi) CtxQuery(hash common.Hash) *RPCCrossTransaction {
+	ctx, _ := s.findCtx(hash)
+	return newRPCCrossTransaction(ctx)
+}
+
+// CtxQueryDestValue returns the ctxs asking value on the destination chain.
+func (s *CrossQueryApi) CtxQueryDestValue(value *hexutil.Big, pageSize, startPage int) *RPCPageCrossTransactions {
+	if value == nil {
+		return nil
+	}
+	locals, remotes := s.findBy(db.DestinationValue, value.ToInt())
+	ctxs := append(locals, remotes...)
+	result := newRPCPage(page(ctxs, pageSize, startPage), len(ctxs))
+	return &result
 }
 
-func (s *CrossQueryApi) CtxContentByPage(localSize, localPage, remoteSize, remotePage int) map[string]RPCPageCrossTransactions {
-	locals, remotes := s.QueryByPage(localSize, localPage, remoteSize, remotePage)
-	content := map[string]RPCPageCrossTransactions{
-		"local": {
-			Data: make(map[uint8][]*RPCCrossTransaction),
-			//Total: localTotal,
-		},
-		"remote": {
-			Data: make(map[uint8][]*RPCCrossTransaction),
-			//Total: remoteTotal,
-		},
+// CtxOwner returns the ctxs made by from, split into the ctxs made on the
+// chains served by the hub and the remote ones.
+func (s *CrossQueryApi) CtxOwner(from string) map[string]map[uint8][]*RPCCrossTransaction {
+	locals, remotes := s.findBy(db.FromField, addressKeys(from)...)
+	return map[string]map[uint8][]*RPCCrossTransaction{
+		"local":  newRPCPage(locals, len(locals)).Data,
+		"remote": newRPCPage(remotes, len(remotes)).Data,
 	}
-	for s, txs := range locals {
-		for _, tx := range txs {
-			content["local"].Data[s] = append(content["local"].Data[s], newRPCCrossTransaction(tx))
+}
+
+// CtxOwnerByPage returns a page of the ctxs made by from.
+func (s *CrossQueryApi) CtxOwnerByPage(from string, pageSize, startPage int) RPCPageCrossTransactions {
+	locals, remotes := s.findBy(db.FromField, addressKeys(from)...)
+	ctxs := append(locals, remotes...)
+	return newRPCPage(page(ctxs, pageSize, startPage), len(ctxs))
+}
+
+// CtxTakerByPage returns a page of the ctxs designated to be taken by to.
+func (s *CrossQueryApi) CtxTakerByPage(to string, pageSize, startPage int) RPCPageCrossTransactions {
+	locals, remotes := s.findBy(db.ToField, addressKeys(to)...)
+	ctxs := append(locals, remotes...)
+	return newRPCPage(page(ctxs, pageSize, startPage), len(ctxs))
+}
+
+// Anchors returns the anchor sets trusted by the chains served by the hub.
+func (s *CrossQueryApi) Anchors() []*RPCAnchorSet {
+	var sets []*RPCAnchorSet
+	for _, purpose := range s.purposes() {
+		anchors := s.chains[purpose].anchors
+		if anchors == nil {
+			continue
+		}
+		for _, set := range anchors.AnchorSets() {
+			sets = append(sets, &RPCAnchorSet{
+				Chain:            hexutil.Uint(purpose),
+				Purpose:          hexutil.Uint(set.Purpose),
+				Anchors:          set.Anchors,
+				SignConfirmCount: hexutil.Uint(set.SignConfirmCount),
+				Number:           hexutil.Uint64(set.Number),
+			})
 		}
 	}
-	for k, txs := range remotes {
-		for _, tx := range txs {
-			content["remote"].Data[k] = append(content["remote"].Data[k], newRPCCrossTransaction(tx))
+	return sets
+}
+
+// AnchorChanges returns the latest anchor set changes of the chains served by the hub.
+func (s *CrossQueryApi) AnchorChanges() []*RPCAnchorChange {
+	var changes []*RPCAnchorChange
+	for _, purpose := range s.purposes() {
+		anchors := s.chains[purpose].anchors
+		if anchors == nil {
+			continue
+		}
+		for _, change := range anchors.AnchorChanges() {
+			changes = append(changes, &RPCAnchorChange{
+				Chain:            hexutil.Uint(purpose),
+				Purpose:          hexutil.Uint(change.Purpose),
+				Number:           hexutil.Uint64(change.Number),
+				Added:            change.Added,
+				Removed:          change.Removed,
+				SignConfirmCount: hexutil.Uint(change.SignConfirmCount),
+			})
 		}
 	}
-	return content
+	return changes
 }
 
+// Elections returns the latest makerFinish submitter elections of the local
+// anchors, deadlines are unix seconds.
+func (s *CrossQueryApi) Elections() []*RPCSubmitElection {
+	var elections []*RPCSubmitElection
+	for _, purpose := range s.purposes() {
+		source := s.chains[purpose].elections
+		if source == nil {
+			continue
+		}
+		for _, e := range source.Elections() {
+			elections = append(elections, &RPCSubmitElection{
+				Chain:      hexutil.Uint(purpose),
+				CtxId:      e.CtxId,
+				Purpose:    hexutil.Uint(e.Purpose),
+				Submitter:  e.Submitter,
+				Candidates: e.Candidates,
+				Rank:       hexutil.Uint(e.Rank),
+				Required:   hexutil.Uint(e.Required),
+				Deadline:   hexutil.Uint64(e.Deadline.Unix()),
+				State:      string(e.State),
+				TxHash:     e.TxHash,
+			})
+		}
+	}
+	return elections
+}
 
+// Peers returns the other hubs of the swarm in id order.
+func (s *CrossQueryApi) Peers() []*RPCPeer {
+	if s.peers == nil {
+		return nil
+	}
+	var peers []*RPCPeer
+	for id, addr := range s.peers.OtherPeers() {
+		status := s.peers.PeerStatus(id)
+		p := &RPCPeer{
+			Id:        hexutil.Uint64(id),
+			PeerId:    addr.ID.String(),
+			Connected: s.peers.Connected(id),
+			State:     string(status.State),
+			Since:     unixTime(status.Since),
+			LastSeen:  unixTime(status.LastSeen),
+			Rtt:       hexutil.Uint64(status.RTT / time.Millisecond),
+		}
+		for _, a := range addr.Addrs {
+			p.Addrs = append(p.Addrs, a.String())
+		}
+		peers = append(peers, p)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Id < peers[j].Id })
+	return peers
+}
 
+// peerEvents returns the latest changes of the states of the other hubs,
+// oldest first.
+func (s *CrossQueryApi) peerEvents() []*RPCPeerEvent {
+	if s.peers == nil {
+		return nil
+	}
+	var events []*RPCPeerEvent
+	for _, e := range s.peers.PeerEvents() {
+		events = append(events, &RPCPeerEvent{
+			Id:     hexutil.Uint64(e.Id),
+			PeerId: e.PeerId,
+			State:  string(e.State),
+			Time:   unixTime(e.Time),
+			Reason: e.Reason,
+		})
+	}
+	return events
+}
 
+// unixTime returns t in unix seconds, zero if t is not set.
+func unixTime(t time.Time) hexutil.Uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return hexutil.Uint64(t.Unix())
+}
 
+// Status returns the scan progress and the counts of the unfinished local
+// orders and the remote orders waiting to be taken of the chains served by the hub.
+func (s *CrossQueryApi) Status() []*RPCChainStatus {
+	var statuses []*RPCChainStatus
+	for _, purpose := range s.purposes() {
+		chain := s.chains[purpose]
+		status := &RPCChainStatus{
+			Chain:       hexutil.Uint(purpose),
+			LocalOrders: hexutil.Uint(chain.localDb.Count(unfinished)),
+		}
+		for _, remote := range chain.remoteDbs {
+			status.RemoteOrders += hexutil.Uint(remote.Count(waiting))
+		}
+		if chain.status != nil {
+			status.Height = hexutil.Uint64(chain.status.Height())
+			status.Pending = hexutil.Uint(chain.status.PendingCount())
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// purposes returns the purposes of the chains served by the hub in order.
+func (s *CrossQueryApi) purposes() []uint8 {
+	purposes := make([]uint8, 0, len(s.chains))
+	for purpose := range s.chains {
+		purposes = append(purposes, purpose)
+	}
+	sort.Slice(purposes, func(i, j int) bool { return purposes[i] < purposes[j] })
+	return purposes
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/asdine/storm/v3"
+	"github.com/simplechain-org/crosshub/core"
+	db "github.com/simplechain-org/crosshub/database"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+)
+
+const (
+	owner = "0x7964576407c299EC0e65991BA4B2d4E2DCDc6C4B"
+	taker = "0x1a3cdc0b9ee9a1d9a5a1ff9c4b0d3e3a12345678"
+)
+
+// newTestApi serves chain 2 with the remote chain 5, like a hub of one side.
+func newTestApi(t *testing.T) (*CrossQueryApi, *db.IndexDB, *db.IndexDB) {
+	rootDB := openTestDB(t)
+	local := db.NewIndexDB(big.NewInt(2), rootDB, 16)
+	remote := db.NewIndexDB(big.NewInt(5), rootDB, 16)
+	api := NewPublicCrossQueryApi()
+	api.AddChain(2, local, map[uint8]*db.IndexDB{5: remote})
+	return api, local, remote
+}
+
+func openTestDB(t *testing.T) *storm.DB {
+	dir, err := ioutil.TempDir("", "crossapi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootDB, err := storm.Open(filepath.Join(dir, "crossData"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		rootDB.Close()
+		os.RemoveAll(dir)
+	})
+	return rootDB
+}
+
+func writeCtx(t *testing.T, store *db.IndexDB, id byte, from, to string, origin, purpose uint8, charge int64, status core.CtxStatus) *core.CrossTransactionWithSignatures {
+	return writeValuedCtx(t, store, id, from, to, origin, purpose, 1e18, charge, status)
+}
+
+func writeValuedCtx(t *testing.T, store *db.IndexDB, id byte, from, to string, origin, purpose uint8, value, charge int64, status core.CtxStatus) *core.CrossTransactionWithSignatures {
+	ctx := core.NewCrossTransaction(big.NewInt(value), big.NewInt(charge), from, to, origin, purpose,
+		common.BytesToHash([]byte{id}), common.BytesToHash([]byte{id, 1}), common.BytesToHash([]byte{id, 2}), nil)
+	cws := core.NewCrossTransactionWithSignatures(ctx, uint64(id))
+	cws.SetStatus(status)
+	if err := store.Write(cws); err != nil {
+		t.Fatal(err)
+	}
+	return cws
+}
+
+func TestCrossQueryApi_CtxQuery(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	made := writeCtx(t, local, 1, owner, "", 2, 5, 100, core.CtxStatusWaiting)
+	pending := writeCtx(t, remote, 2, owner, "", 5, 2, 100, core.CtxStatusPending)
+
+	if ctx := api.CtxQuery(made.ID()); ctx == nil || ctx.CTxId != made.ID() || ctx.Status != "waiting" {
+		t.Fatalf("by id: %+v", ctx)
+	}
+	if ctx := api.CtxQuery(made.Data.TxHash); ctx == nil || ctx.CTxId != made.ID() {
+		t.Fatalf("by tx hash: %+v", ctx)
+	}
+	// remote ctxs collecting signatures are hidden
+	if ctx := api.CtxQuery(pending.ID()); ctx != nil {
+		t.Fatalf("pending remote ctx returned: %+v", ctx)
+	}
+}
+
+func TestCrossQueryApi_CtxOwner(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	for i := byte(1); i <= 5; i++ {
+		writeCtx(t, local, i, owner, "", 2, 5, 100, core.CtxStatusWaiting)
+	}
+	// makers pass free strings, the lower case form is found too
+	writeCtx(t, remote, 6, "0x7964576407c299ec0e65991ba4b2d4e2dcdc6c4b", taker, 5, 2, 200, core.CtxStatusWaiting)
+	writeCtx(t, remote, 7, owner, "", 5, 2, 200, core.CtxStatusPending)
+	writeCtx(t, local, 8, "0x01", taker, 2, 5, 200, core.CtxStatusWaiting)
+
+	all := api.CtxOwner(owner)
+	if len(all["local"][2]) != 5 || len(all["remote"][5]) != 1 {
+		t.Fatalf("owner ctxs: %d local, %d remote", len(all["local"][2]), len(all["remote"][5]))
+	}
+
+	first := api.CtxOwnerByPage(owner, 4, 1)
+	second := api.CtxOwnerByPage(owner, 4, 2)
+	if first.Total != 6 || second.Total != 6 {
+		t.Fatalf("totals = %d, %d, want 6", first.Total, second.Total)
+	}
+	if len(first.Data[2]) != 4 || len(second.Data[2]) != 1 || len(second.Data[5]) != 1 {
+		t.Fatalf("unexpected pages: %v, %v", first.Data, second.Data)
+	}
+	if past := api.CtxOwnerByPage(owner, 4, 3); len(past.Data) != 0 || past.Total != 6 {
+		t.Fatalf("page past the end: %+v", past)
+	}
+
+	taken := api.CtxTakerByPage(taker, 10, 1)
+	if taken.Total != 2 || len(taken.Data[2]) != 1 || len(taken.Data[5]) != 1 {
+		t.Fatalf("taker ctxs: %+v", taken)
+	}
+}
+
+func TestCrossQueryApi_CtxQueryDestValue(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	writeCtx(t, local, 1, owner, "", 2, 5, 100, core.CtxStatusWaiting)
+	writeCtx(t, remote, 2, owner, "", 5, 2, 100, core.CtxStatusWaiting)
+	writeCtx(t, remote, 3, owner, "", 5, 2, 300, core.CtxStatusWaiting)
+
+	result := api.CtxQueryDestValue((*hexutil.Big)(big.NewInt(100)), 0, 0)
+	if result.Total != 2 || len(result.Data[2]) != 1 || len(result.Data[5]) != 1 {
+		t.Fatalf("dest value ctxs: %+v", result)
+	}
+	if result := api.CtxQueryDestValue((*hexutil.Big)(big.NewInt(300)), 1, 1); result.Total != 1 {
+		t.Fatalf("total = %d, want 1", result.Total)
+	}
+}
+
+func TestCrossQueryApi_CtxContentByPage(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	// values and prices increase with the insertion order, values of different
+	// lengths are ordered by number
+	for i, value := range []int64{9, 80, 700} {
+		writeValuedCtx(t, local, byte(i+1), owner, "", 2, 5, value, value*int64(i+1), core.CtxStatusWaiting)
+	}
+	writeValuedCtx(t, local, 4, owner, "", 2, 5, 1e4, 1e6, core.CtxStatusFinished)
+	// charges decrease with the insertion order, prices follow the charges
+	for i := byte(5); i <= 9; i++ {
+		writeCtx(t, remote, i, owner, "", 5, 2, int64(20-i)*100, core.CtxStatusWaiting)
+	}
+	writeCtx(t, remote, 10, owner, "", 5, 2, 100, core.CtxStatusPending)
+	writeCtx(t, remote, 11, owner, "", 5, 2, 100, core.CtxStatusExecuted)
+
+	insertion := "insertion"
+	content, err := api.CtxContentByPage(2, 2, 2, 3, &insertion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local1, remote1 := content["local"], content["remote"]
+	if local1.Total != 3 || remote1.Total != 5 {
+		t.Fatalf("totals = %d, %d, want 3, 5", local1.Total, remote1.Total)
+	}
+	// local and remote pages are independent
+	if len(local1.Data[2]) != 1 || len(remote1.Data[5]) != 1 {
+		t.Fatalf("unexpected pages: %v, %v", local1.Data, remote1.Data)
+	}
+	if remote1.Data[5][0].CTxId != common.BytesToHash([]byte{9}) {
+		t.Errorf("last remote by insertion = %s", remote1.Data[5][0].CTxId.String())
+	}
+
+	// default order is the price
+	content, err = api.CtxContentByPage(1, 1, 1, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := content["remote"].Data[5][0].CTxId; id != common.BytesToHash([]byte{9}) {
+		t.Errorf("cheapest remote = %s", id.String())
+	}
+	for _, order := range []string{"-value", "-price"} {
+		content, err = api.CtxContentByPage(1, 1, 1, 1, &order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id := content["local"].Data[2][0].CTxId; id != common.BytesToHash([]byte{3}) {
+			t.Errorf("first local by %s = %s", order, id.String())
+		}
+	}
+
+	unknown := "gas"
+	if _, err := api.CtxContentByPage(1, 1, 1, 1, &unknown); err == nil {
+		t.Error("unknown order accepted")
+	}
+}
+
+func TestCrossQueryApi_CtxContentByPageOverStores(t *testing.T) {
+	// a hub of both sides, orders of each chain are local to one store and
+	// remote to the other
+	rootDB := openTestDB(t)
+	local2, local5 := db.NewIndexDB(big.NewInt(2), rootDB, 16), db.NewIndexDB(big.NewInt(5), rootDB, 16)
+	api := NewPublicCrossQueryApi()
+	api.AddChain(2, local2, map[uint8]*db.IndexDB{})
+	api.AddChain(5, local5, map[uint8]*db.IndexDB{})
+	for i, value := range []int64{1, 4, 5} {
+		writeValuedCtx(t, local2, byte(i+1), owner, "", 2, 5, value, 1, core.CtxStatusWaiting)
+	}
+	for i, value := range []int64{2, 3, 6} {
+		writeValuedCtx(t, local5, byte(i+4), owner, "", 5, 2, value, 1, core.CtxStatusWaiting)
+	}
+
+	value := "value"
+	for page := 1; page <= 4; page++ {
+		content, err := api.CtxContentByPage(2, page, 2, 1, &value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total := content["local"].Total; total != 6 {
+			t.Fatalf("total = %d, want 6", total)
+		}
+		// pages are grouped by chain, a page has the orders in between its
+		// neighbour pages over all stores
+		var values []int64
+		for _, ctxs := range content["local"].Data {
+			for _, ctx := range ctxs {
+				values = append(values, ctx.Value.ToInt().Int64())
+			}
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		want := []int64{int64(2*page - 1), int64(2 * page)}
+		if page == 4 {
+			want = nil
+		}
+		if !reflect.DeepEqual(values, want) {
+			t.Errorf("page %d values %v, want %v", page, values, want)
+		}
+	}
+}
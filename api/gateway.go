@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+// gateway serves the api as REST/JSON, the resources are described by the
+// OpenAPI document at /v1/openapi.json.
+type gateway struct {
+	api     *CrossQueryApi
+	origins map[string]bool
+}
+
+// NewGateway returns the REST gateway of api, cross-origin requests are
+// allowed from allowedOrigins, "*" allows any origin. Requests are served to
+// the read credentials of auth, a nil auth serves any request.
+func NewGateway(api *CrossQueryApi, allowedOrigins []string, auth *Auth) http.Handler {
+	g := &gateway{api: api, origins: make(map[string]bool)}
+	for _, origin := range allowedOrigins {
+		g.origins[strings.ToLower(origin)] = true
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/orders", g.orders)
+	mux.HandleFunc("/v1/ctxs", g.ctxs)
+	mux.HandleFunc("/v1/ctxs/", g.ctx)
+	mux.HandleFunc("/v1/anchors", g.anchors)
+	mux.HandleFunc("/v1/anchors/changes", g.anchorChanges)
+	mux.HandleFunc("/v1/peers", g.peers)
+	mux.HandleFunc("/v1/status", g.status)
+	mux.HandleFunc("/v1/openapi.json", g.openapi)
+	return g.cors(auth.Handler(RoleRead, mux))
+}
+
+// cors answers the preflight requests and only lets GET requests through.
+func (g *gateway) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && (g.origins["*"] || g.origins[strings.ToLower(origin)]) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Vary", "Origin")
+		}
+		switch r.Method {
+		case http.MethodOptions:
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Api-Key")
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			next.ServeHTTP(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		}
+	})
+}
+
+// orders serves the order book, see CtxContentByPage.
+func (g *gateway) orders(w http.ResponseWriter, r *http.Request) {
+	// pages are the whole order book unless a size is set
+	params := []struct {
+		name string
+		def  int
+	}{{"localSize", 0}, {"localPage", 1}, {"remoteSize", 0}, {"remotePage", 1}}
+	values := make([]int, len(params))
+	for i, param := range params {
+		value, err := intParam(r, param.name, param.def)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		values[i] = value
+	}
+	order := r.URL.Query().Get("order")
+	content, err := g.api.CtxContentByPage(values[0], values[1], values[2], values[3], &order)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, content)
+}
+
+// ctxs serves a page of the ctxs of an owner, a designated taker or a
+// destination value.
+func (g *gateway) ctxs(w http.ResponseWriter, r *http.Request) {
+	size, err := intParam(r, "size", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	page, err := intParam(r, "page", 1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	query := r.URL.Query()
+	switch {
+	case query.Get("owner") != "":
+		writeJSON(w, http.StatusOK, g.api.CtxOwnerByPage(query.Get("owner"), size, page))
+	case query.Get("taker") != "":
+		writeJSON(w, http.StatusOK, g.api.CtxTakerByPage(query.Get("taker"), size, page))
+	case query.Get("value") != "":
+		value, ok := new(big.Int).SetString(query.Get("value"), 0)
+		if !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid value %q", query.Get("value")))
+			return
+		}
+		writeJSON(w, http.StatusOK, g.api.CtxQueryDestValue((*hexutil.Big)(value), size, page))
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("one of owner, taker or value is required"))
+	}
+}
+
+// ctx serves the ctx with the id or the maker tx hash of the path.
+func (g *gateway) ctx(w http.ResponseWriter, r *http.Request) {
+	raw := strings.TrimPrefix(r.URL.Path, "/v1/ctxs/")
+	hash, err := hexutil.Decode(raw)
+	if err != nil || len(hash) != common.HashLength {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid hash %q", raw))
+		return
+	}
+	ctx := g.api.CtxQuery(common.BytesToHash(hash))
+	if ctx == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("ctx %s not found", raw))
+		return
+	}
+	writeJSON(w, http.StatusOK, ctx)
+}
+
+func (g *gateway) anchors(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, g.api.Anchors())
+}
+
+func (g *gateway) anchorChanges(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, g.api.AnchorChanges())
+}
+
+func (g *gateway) peers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, g.api.Peers())
+}
+
+func (g *gateway) status(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, g.api.Status())
+}
+
+func (g *gateway) openapi(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(openAPIDoc))
+}
+
+// intParam returns the int query parameter name, def if it is not set.
+func intParam(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	return value, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debug("write gateway response", "err", err)
+	}
+}
+
+// writeError writes err as {"error": "..."}.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
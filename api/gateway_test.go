@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/simplechain-org/crosshub/core"
+)
+
+type testHeights struct{}
+
+func (testHeights) Height() uint64    { return 100 }
+func (testHeights) PendingCount() int { return 1 }
+
+func getJSON(t *testing.T, handler http.Handler, path string, status int, v interface{}) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	r.Header.Set("Origin", "http://dashboard")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != status {
+		t.Fatalf("GET %s: status = %d, want %d: %s", path, w.Code, status, w.Body.String())
+	}
+	if v != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+	}
+	return w
+}
+
+func TestGateway(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	api.SetStatusSource(2, testHeights{})
+	made := writeCtx(t, local, 1, owner, "", 2, 5, 100, core.CtxStatusWaiting)
+	writeCtx(t, remote, 2, owner, taker, 5, 2, 100, core.CtxStatusWaiting)
+	writeCtx(t, remote, 3, owner, "", 5, 2, 200, core.CtxStatusWaiting)
+	gateway := NewGateway(api, []string{"http://dashboard"}, nil)
+
+	var orders map[string]RPCPageCrossTransactions
+	w := getJSON(t, gateway, "/v1/orders?remoteSize=1&order=-price", http.StatusOK, &orders)
+	if w.Header().Get("Access-Control-Allow-Origin") != "http://dashboard" {
+		t.Errorf("origin not allowed")
+	}
+	if orders["local"].Total != 1 || orders["remote"].Total != 2 || len(orders["remote"].Data[5]) != 1 {
+		t.Fatalf("unexpected orders: %+v", orders)
+	}
+	getJSON(t, gateway, "/v1/orders?order=gas", http.StatusBadRequest, nil)
+	getJSON(t, gateway, "/v1/orders?localSize=-1", http.StatusBadRequest, nil)
+
+	var ctx RPCCrossTransaction
+	getJSON(t, gateway, "/v1/ctxs/"+made.ID().String(), http.StatusOK, &ctx)
+	if ctx.CTxId != made.ID() {
+		t.Fatalf("ctx = %s", ctx.CTxId.String())
+	}
+	getJSON(t, gateway, "/v1/ctxs/0x"+made.ID().String()[4:], http.StatusBadRequest, nil)
+	getJSON(t, gateway, "/v1/ctxs/"+made.Data.BlockHash.String(), http.StatusNotFound, nil)
+
+	var page RPCPageCrossTransactions
+	if getJSON(t, gateway, "/v1/ctxs?taker="+taker, http.StatusOK, &page); page.Total != 1 {
+		t.Fatalf("taker ctxs: %+v", page)
+	}
+	if getJSON(t, gateway, "/v1/ctxs?value=200", http.StatusOK, &page); page.Total != 1 {
+		t.Fatalf("value ctxs: %+v", page)
+	}
+	getJSON(t, gateway, "/v1/ctxs", http.StatusBadRequest, nil)
+
+	var status []*RPCChainStatus
+	getJSON(t, gateway, "/v1/status", http.StatusOK, &status)
+	if len(status) != 1 || status[0].Height != 100 || status[0].LocalOrders != 1 || status[0].RemoteOrders != 2 {
+		t.Fatalf("unexpected status: %+v", status[0])
+	}
+
+	var doc struct {
+		Paths map[string]interface{} `json:"paths"`
+	}
+	getJSON(t, gateway, "/v1/openapi.json", http.StatusOK, &doc)
+	for _, path := range []string{"/v1/orders", "/v1/ctxs", "/v1/ctxs/{hash}", "/v1/anchors", "/v1/peers", "/v1/status"} {
+		if doc.Paths[path] == nil {
+			t.Errorf("%s not documented", path)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/orders", nil)
+	w = httptest.NewRecorder()
+	gateway.ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d", w.Code)
+	}
+}
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/simplechain-org/crosshub/api/hubpb"
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// NewGRPCServer returns the gRPC server of the Hub and Admin services of api,
+// clients authenticate with a cert chain to the CA of certs. Calls are then
+// authorized by the credentials of auth like the rpc requests, the Admin
+// service is served only if auth has admin credentials.
+func NewGRPCServer(api *CrossQueryApi, certs *repo.Certs, key *ecdsa.PrivateKey, auth *Auth) *grpc.Server {
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(ServerTLS(certs, key))),
+		grpc.UnaryInterceptor(auth.unaryInterceptor), grpc.StreamInterceptor(auth.streamInterceptor))
+	hubpb.RegisterHubServer(server, &hubServer{api: api})
+	if auth.Grants(RoleAdmin) {
+		hubpb.RegisterAdminServer(server, &adminServer{api: api})
+	}
+	return server
+}
+
+func (a *Auth) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorizeCall(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *Auth) streamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorizeCall(stream.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// authorizeCall authorizes a call of method by the api key or the jwt of the
+// x-api-key or authorization metadata of ctx, like Handler the rpc requests.
+func (a *Auth) authorizeCall(ctx context.Context, method string) error {
+	role := RoleRead
+	if strings.HasPrefix(method, "/hubpb.Admin/") {
+		role = RoleAdmin
+	}
+	if a == nil {
+		if role == RoleRead {
+			return nil
+		}
+		return status.Error(codes.PermissionDenied, (&roleError{role: role}).Error())
+	}
+	var remote string
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var cred *credential
+	token, err := bearerToken(first(md.Get("x-api-key")), first(md.Get("authorization")))
+	if err == nil {
+		cred, err = a.lookup(token)
+	}
+	err = a.admit("grpc", role, cred, err, "remote", remote, "method", method)
+	var (
+		denied  *roleError
+		limited *limitError
+	)
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &denied):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.As(err, &limited):
+		return status.Errorf(codes.ResourceExhausted, "%v, retry in %s", err, limited.wait)
+	}
+	return status.Error(codes.Unauthenticated, err.Error())
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// ServerTLS returns the mutual TLS config of the gRPC server, the node cert is
+// presented with its agency cert and clients need a cert chain to the CA.
+func ServerTLS(certs *repo.Certs, key *ecdsa.PrivateKey) *tls.Config {
+	roots := x509.NewCertPool()
+	roots.AddCert(certs.CACert)
+	return &tls.Config{
+		Certificates: []tls.Certificate{nodeCert(certs, key)},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    roots,
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// ClientTLS returns the mutual TLS config of a gRPC client. The hub certs carry
+// no host names, the server is verified by its cert chain to the CA instead.
+func ClientTLS(certs *repo.Certs, key *ecdsa.PrivateKey) *tls.Config {
+	return &tls.Config{
+		Certificates:          []tls.Certificate{nodeCert(certs, key)},
+		InsecureSkipVerify:    true, // the chain is verified by VerifyPeerCertificate
+		VerifyPeerCertificate: verifyChain(certs.CACert),
+		MinVersion:            tls.VersionTLS12,
+	}
+}
+
+func nodeCert(certs *repo.Certs, key *ecdsa.PrivateKey) tls.Certificate {
+	return tls.Certificate{
+		Certificate: [][]byte{certs.NodeCert.Raw, certs.AgencyCert.Raw},
+		PrivateKey:  key,
+		Leaf:        certs.NodeCert,
+	}
+}
+
+// verifyChain verifies the presented cert chain up to ca.
+func verifyChain(ca *x509.Certificate) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no peer cert")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("parse peer cert: %w", err)
+			}
+			certs[i] = cert
+		}
+		opts := x509.VerifyOptions{
+			Roots:         x509.NewCertPool(),
+			Intermediates: x509.NewCertPool(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}
+		opts.Roots.AddCert(ca)
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(opts)
+		return err
+	}
+}
+
+// hubServer serves the order queries of api over gRPC.
+type hubServer struct {
+	api *CrossQueryApi
+}
+
+func (s *hubServer) Orders(ctx context.Context, req *hubpb.OrdersRequest) (*hubpb.OrdersReply, error) {
+	content, err := s.api.CtxContentByPage(int(req.LocalSize), pageNumber(req.LocalPage),
+		int(req.RemoteSize), pageNumber(req.RemotePage), &req.Order)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &hubpb.OrdersReply{Local: pbPage(content["local"]), Remote: pbPage(content["remote"])}, nil
+}
+
+func (s *hubServer) GetCtx(ctx context.Context, req *hubpb.CtxRequest) (*hubpb.CrossTransaction, error) {
+	if len(req.Hash) != common.HashLength {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid hash length %d", len(req.Hash))
+	}
+	hash := common.BytesToHash(req.Hash)
+	found := s.api.CtxQuery(hash)
+	if found == nil {
+		return nil, status.Errorf(codes.NotFound, "ctx %s not found", hash.String())
+	}
+	return pbCtx(found), nil
+}
+
+func (s *hubServer) OwnerCtxs(ctx context.Context, req *hubpb.AddressRequest) (*hubpb.Page, error) {
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "no address")
+	}
+	page := s.api.CtxOwnerByPage(req.Address, int(req.Size), pageNumber(req.Page))
+	return pbPage(page), nil
+}
+
+func (s *hubServer) TakerCtxs(ctx context.Context, req *hubpb.AddressRequest) (*hubpb.Page, error) {
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "no address")
+	}
+	page := s.api.CtxTakerByPage(req.Address, int(req.Size), pageNumber(req.Page))
+	return pbPage(page), nil
+}
+
+func (s *hubServer) WatchOrders(req *hubpb.OrderFilter, stream hubpb.Hub_WatchOrdersServer) error {
+	filter := &OrderFilter{Owner: req.Owner, Taker: req.Taker}
+	if req.Purpose != 0 {
+		purpose := hexutil.Uint(req.Purpose)
+		filter.Purpose = &purpose
+	}
+	events, unsubscribe := s.api.subscribe(filter)
+	defer unsubscribe()
+	for {
+		select {
+		case ev := <-events:
+			if err := stream.Send(&hubpb.OrderEvent{
+				Type:       ev.Type,
+				Chain:      uint32(ev.Chain),
+				Remote:     ev.Remote,
+				PrevStatus: ev.PrevStatus,
+				Ctx:        pbCtx(ev.Ctx),
+			}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// adminServer serves the chain adapter operations over gRPC.
+type adminServer struct {
+	api *CrossQueryApi
+}
+
+func (s *adminServer) Status(ctx context.Context, req *hubpb.StatusRequest) (*hubpb.StatusReply, error) {
+	reply := new(hubpb.StatusReply)
+	for _, chain := range s.api.Status() {
+		reply.Chains = append(reply.Chains, &hubpb.ChainStatus{
+			Chain:        uint32(chain.Chain),
+			Height:       uint64(chain.Height),
+			Pending:      uint32(chain.Pending),
+			LocalOrders:  uint32(chain.LocalOrders),
+			RemoteOrders: uint32(chain.RemoteOrders),
+		})
+	}
+	for _, peer := range s.api.Peers() {
+		reply.Peers = append(reply.Peers, &hubpb.Peer{
+			Id:        uint64(peer.Id),
+			PeerId:    peer.PeerId,
+			Addrs:     peer.Addrs,
+			Connected: peer.Connected,
+		})
+	}
+	return reply, nil
+}
+
+func (s *adminServer) Rescan(ctx context.Context, req *hubpb.RescanRequest) (*hubpb.AdminReply, error) {
+	admin, err := s.admin(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	if err := admin.Rescan(req.From); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return new(hubpb.AdminReply), nil
+}
+
+func (s *adminServer) ReloadAnchors(ctx context.Context, req *hubpb.ChainRequest) (*hubpb.AdminReply, error) {
+	admin, err := s.admin(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	if err := admin.ReloadAnchors(); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return new(hubpb.AdminReply), nil
+}
+
+func (s *adminServer) DropRemoteOrder(ctx context.Context, req *hubpb.DropRequest) (*hubpb.AdminReply, error) {
+	admin, err := s.admin(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.CtxId) != common.HashLength {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid ctx id length %d", len(req.CtxId))
+	}
+	if err := admin.DropRemote(uint8(req.Origin), common.BytesToHash(req.CtxId)); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return new(hubpb.AdminReply), nil
+}
+
+// admin returns the operations of the chain served by the hub.
+func (s *adminServer) admin(purpose uint32) (AdminSource, error) {
+	if purpose > 255 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid chain %d", purpose)
+	}
+	chain, ok := s.api.chains[uint8(purpose)]
+	if !ok || chain.admin == nil {
+		return nil, status.Errorf(codes.NotFound, "chain %d not served", purpose)
+	}
+	return chain.admin, nil
+}
+
+// pageNumber defaults the page number of a request to the first page.
+func pageNumber(page uint32) int {
+	if page == 0 {
+		return 1
+	}
+	return int(page)
+}
+
+func pbPage(page RPCPageCrossTransactions) *hubpb.Page {
+	origins := make([]int, 0, len(page.Data))
+	for origin := range page.Data {
+		origins = append(origins, int(origin))
+	}
+	sort.Ints(origins)
+	result := &hubpb.Page{Total: uint64(page.Total)}
+	for _, origin := range origins {
+		for _, ctx := range page.Data[uint8(origin)] {
+			result.Ctxs = append(result.Ctxs, pbCtx(ctx))
+		}
+	}
+	return result
+}
+
+func pbCtx(ctx *RPCCrossTransaction) *hubpb.CrossTransaction {
+	result := &hubpb.CrossTransaction{
+		CtxId:     ctx.CTxId.Bytes(),
+		TxHash:    ctx.TxHash.Bytes(),
+		BlockHash: ctx.BlockHash.Bytes(),
+		From:      ctx.From,
+		To:        ctx.To,
+		Origin:    uint32(ctx.Origin),
+		Purpose:   uint32(ctx.Purpose),
+		Payload:   ctx.Payload,
+		Status:    ctx.Status,
+	}
+	if ctx.Value != nil {
+		result.Value = ctx.Value.ToInt().String()
+	}
+	if ctx.Charge != nil {
+		result.Charge = ctx.Charge.ToInt().String()
+	}
+	for _, v := range ctx.V {
+		result.V = append(result.V, v.ToInt().Bytes())
+	}
+	for _, r := range ctx.R {
+		result.R = append(result.R, r.ToInt().Bytes())
+	}
+	for _, s := range ctx.S {
+		result.S = append(result.S, s.ToInt().Bytes())
+	}
+	return result
+}
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/simplechain-org/crosshub/api/hubpb"
+	"github.com/simplechain-org/crosshub/cert"
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// issue returns a cert of a new key signed by parent, self signed if parent is nil.
+func issue(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool, organization string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template, err := cert.GenerateCert(key, isCA, organization)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issued, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return issued, key
+}
+
+// testCerts returns the certs of n nodes of one agency under a new CA.
+func testCerts(t *testing.T, n int) ([]*repo.Certs, []*ecdsa.PrivateKey) {
+	ca, caKey := issue(t, nil, nil, true, "CA")
+	agency, agencyKey := issue(t, ca, caKey, true, "Agency")
+	var (
+		certs []*repo.Certs
+		keys  []*ecdsa.PrivateKey
+	)
+	for i := 0; i < n; i++ {
+		node, key := issue(t, agency, agencyKey, false, "Node")
+		certs = append(certs, &repo.Certs{NodeCert: node, AgencyCert: agency, CACert: ca})
+		keys = append(keys, key)
+	}
+	return certs, keys
+}
+
+type testAdmin struct {
+	rescanned uint64
+}
+
+func (a *testAdmin) Rescan(from uint64) error                      { a.rescanned = from; return nil }
+func (a *testAdmin) ReloadAnchors() error                          { return nil }
+func (a *testAdmin) DropRemote(origin uint8, id common.Hash) error { return nil }
+func (a *testAdmin) Rebroadcast(id common.Hash) error              { return nil }
+
+func dialGRPC(t *testing.T, server *grpc.Server, certs *repo.Certs, key *ecdsa.PrivateKey) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(credentials.NewTLS(ClientTLS(certs, key))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPC(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	admin := new(testAdmin)
+	api.SetAdminSource(2, admin)
+	made := writeCtx(t, local, 1, owner, "", 2, 5, 100, core.CtxStatusWaiting)
+	writeCtx(t, remote, 2, owner, taker, 5, 2, 100, core.CtxStatusWaiting)
+
+	certs, keys := testCerts(t, 2)
+	auth, err := NewAuth(repo.Auth{}, "admin-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := dialGRPC(t, NewGRPCServer(api, certs[0], keys[0], auth), certs[1], keys[1])
+	hub, adminClient := hubpb.NewHubClient(conn), hubpb.NewAdminClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	adminCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer admin-key")
+
+	orders, err := hub.Orders(ctx, &hubpb.OrdersRequest{RemoteSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orders.Local.Total != 1 || orders.Remote.Total != 1 || len(orders.Remote.Ctxs) != 1 {
+		t.Fatalf("unexpected orders: %v", orders)
+	}
+	found, err := hub.GetCtx(ctx, &hubpb.CtxRequest{Hash: made.ID().Bytes()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.BytesToHash(found.CtxId) != made.ID() || found.Value != "1000000000000000000" || found.Status != "waiting" {
+		t.Fatalf("unexpected ctx: %v", found)
+	}
+	if _, err := hub.GetCtx(ctx, &hubpb.CtxRequest{Hash: common.Hash{}.Bytes()}); status.Code(err) != codes.NotFound {
+		t.Fatalf("missing ctx: %v", err)
+	}
+	if page, err := hub.TakerCtxs(ctx, &hubpb.AddressRequest{Address: taker}); err != nil || page.Total != 1 {
+		t.Fatalf("taker ctxs: %v, %v", page, err)
+	}
+
+	stream, err := hub.WatchOrders(ctx, &hubpb.OrderFilter{Owner: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the subscription is added once the stream is served
+	for i := 0; i < 100 && len(api.orders.subs) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	writeCtx(t, local, 3, owner, "", 2, 5, 100, core.CtxStatusWaiting)
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != OrderNew || ev.Chain != 2 || common.BytesToHash(ev.Ctx.CtxId) != common.BytesToHash([]byte{3}) {
+		t.Fatalf("unexpected event: %v", ev)
+	}
+
+	if _, err := adminClient.Rescan(adminCtx, &hubpb.RescanRequest{Chain: 2, From: 42}); err != nil || admin.rescanned != 42 {
+		t.Fatalf("rescan: %v, from %d", err, admin.rescanned)
+	}
+	if _, err := adminClient.ReloadAnchors(adminCtx, &hubpb.ChainRequest{Chain: 7}); status.Code(err) != codes.NotFound {
+		t.Fatalf("unknown chain: %v", err)
+	}
+	reply, err := adminClient.Status(adminCtx, &hubpb.StatusRequest{})
+	if err != nil || len(reply.Chains) != 1 || reply.Chains[0].RemoteOrders != 1 {
+		t.Fatalf("status: %v, %v", reply, err)
+	}
+}
+
+func TestGRPC_UntrustedClient(t *testing.T) {
+	api, _, _ := newTestApi(t)
+	certs, keys := testCerts(t, 1)
+	other, otherKeys := testCerts(t, 1)
+	// the client trusts the server CA but its own cert is of another CA
+	client := *other[0]
+	client.CACert = certs[0].CACert
+	conn := dialGRPC(t, NewGRPCServer(api, certs[0], keys[0], nil), &client, otherKeys[0])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := hubpb.NewHubClient(conn).Orders(ctx, &hubpb.OrdersRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("untrusted client served: %v", err)
+	}
+}
+
+func TestGRPC_Auth(t *testing.T) {
+	api, _, _ := newTestApi(t)
+	api.SetAdminSource(2, new(testAdmin))
+	certs, keys := testCerts(t, 2)
+	auth, err := NewAuth(repo.Auth{Keys: []repo.ApiKey{
+		{Name: "partner", Key: "read-key", Role: "read", Rate: 0.001, Burst: 1},
+		{Name: "ops", Key: "admin-key", Role: "admin"},
+	}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := dialGRPC(t, NewGRPCServer(api, certs[0], keys[0], auth), certs[1], keys[1])
+	hub, admin := hubpb.NewHubClient(conn), hubpb.NewAdminClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	readCtx := metadata.AppendToOutgoingContext(ctx, "x-api-key", "read-key")
+
+	// a cert of the consortium is not a credential
+	if _, err := hub.Orders(ctx, &hubpb.OrdersRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("anonymous orders: %v", err)
+	}
+	if _, err := admin.Status(ctx, &hubpb.StatusRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("anonymous status: %v", err)
+	}
+	if _, err := admin.Status(metadata.AppendToOutgoingContext(ctx, "x-api-key", "guess"), &hubpb.StatusRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unknown key status: %v", err)
+	}
+	if _, err := admin.Rescan(readCtx, &hubpb.RescanRequest{Chain: 2}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("read key rescan: %v", err)
+	}
+	if _, err := hub.Orders(readCtx, &hubpb.OrdersRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hub.Orders(readCtx, &hubpb.OrdersRequest{}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("limited orders: %v", err)
+	}
+	if _, err := admin.Status(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer admin-key"), &hubpb.StatusRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// without admin credentials the Admin service is not served
+	open, err := NewAuth(repo.Auth{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn = dialGRPC(t, NewGRPCServer(api, certs[0], keys[0], open), certs[1], keys[1])
+	if _, err := hubpb.NewHubClient(conn).Orders(ctx, &hubpb.OrdersRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hubpb.NewAdminClient(conn).Status(ctx, &hubpb.StatusRequest{}); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("admin served: %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package hubpb contains the gRPC service of the hub api, generated from
+// hub.proto with protoc-gen-go v1.3.2.
+package hubpb
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. hub.proto
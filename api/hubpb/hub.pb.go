@@ -0,0 +1,1486 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: hub.proto
+
+package hubpb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// CrossTransaction is a cross transaction with the signatures of the anchors,
+// values are decimal strings.
+type CrossTransaction struct {
+	CtxId                []byte   `protobuf:"bytes,1,opt,name=ctx_id,json=ctxId,proto3" json:"ctx_id,omitempty"`
+	TxHash               []byte   `protobuf:"bytes,2,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	BlockHash            []byte   `protobuf:"bytes,3,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Value                string   `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Charge               string   `protobuf:"bytes,5,opt,name=charge,proto3" json:"charge,omitempty"`
+	From                 string   `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
+	Origin               uint32   `protobuf:"varint,8,opt,name=origin,proto3" json:"origin,omitempty"`
+	Purpose              uint32   `protobuf:"varint,9,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	Payload              []byte   `protobuf:"bytes,10,opt,name=payload,proto3" json:"payload,omitempty"`
+	Status               string   `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
+	V                    [][]byte `protobuf:"bytes,12,rep,name=v,proto3" json:"v,omitempty"`
+	R                    [][]byte `protobuf:"bytes,13,rep,name=r,proto3" json:"r,omitempty"`
+	S                    [][]byte `protobuf:"bytes,14,rep,name=s,proto3" json:"s,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CrossTransaction) Reset()         { *m = CrossTransaction{} }
+func (m *CrossTransaction) String() string { return proto.CompactTextString(m) }
+func (*CrossTransaction) ProtoMessage()    {}
+func (*CrossTransaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{0}
+}
+
+func (m *CrossTransaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CrossTransaction.Unmarshal(m, b)
+}
+func (m *CrossTransaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CrossTransaction.Marshal(b, m, deterministic)
+}
+func (m *CrossTransaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrossTransaction.Merge(m, src)
+}
+func (m *CrossTransaction) XXX_Size() int {
+	return xxx_messageInfo_CrossTransaction.Size(m)
+}
+func (m *CrossTransaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrossTransaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrossTransaction proto.InternalMessageInfo
+
+func (m *CrossTransaction) GetCtxId() []byte {
+	if m != nil {
+		return m.CtxId
+	}
+	return nil
+}
+
+func (m *CrossTransaction) GetTxHash() []byte {
+	if m != nil {
+		return m.TxHash
+	}
+	return nil
+}
+
+func (m *CrossTransaction) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *CrossTransaction) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *CrossTransaction) GetCharge() string {
+	if m != nil {
+		return m.Charge
+	}
+	return ""
+}
+
+func (m *CrossTransaction) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *CrossTransaction) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *CrossTransaction) GetOrigin() uint32 {
+	if m != nil {
+		return m.Origin
+	}
+	return 0
+}
+
+func (m *CrossTransaction) GetPurpose() uint32 {
+	if m != nil {
+		return m.Purpose
+	}
+	return 0
+}
+
+func (m *CrossTransaction) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *CrossTransaction) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *CrossTransaction) GetV() [][]byte {
+	if m != nil {
+		return m.V
+	}
+	return nil
+}
+
+func (m *CrossTransaction) GetR() [][]byte {
+	if m != nil {
+		return m.R
+	}
+	return nil
+}
+
+func (m *CrossTransaction) GetS() [][]byte {
+	if m != nil {
+		return m.S
+	}
+	return nil
+}
+
+// Page is a page of ctxs, total is the count over all pages.
+type Page struct {
+	Ctxs                 []*CrossTransaction `protobuf:"bytes,1,rep,name=ctxs,proto3" json:"ctxs,omitempty"`
+	Total                uint64              `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Page) Reset()         { *m = Page{} }
+func (m *Page) String() string { return proto.CompactTextString(m) }
+func (*Page) ProtoMessage()    {}
+func (*Page) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{1}
+}
+
+func (m *Page) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Page.Unmarshal(m, b)
+}
+func (m *Page) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Page.Marshal(b, m, deterministic)
+}
+func (m *Page) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Page.Merge(m, src)
+}
+func (m *Page) XXX_Size() int {
+	return xxx_messageInfo_Page.Size(m)
+}
+func (m *Page) XXX_DiscardUnknown() {
+	xxx_messageInfo_Page.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Page proto.InternalMessageInfo
+
+func (m *Page) GetCtxs() []*CrossTransaction {
+	if m != nil {
+		return m.Ctxs
+	}
+	return nil
+}
+
+func (m *Page) GetTotal() uint64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+// OrdersRequest pages the orders, a zero size selects all orders. order is
+// "price" (default), "value" or "insertion", descending if prefixed by "-".
+type OrdersRequest struct {
+	LocalSize            uint32   `protobuf:"varint,1,opt,name=local_size,json=localSize,proto3" json:"local_size,omitempty"`
+	LocalPage            uint32   `protobuf:"varint,2,opt,name=local_page,json=localPage,proto3" json:"local_page,omitempty"`
+	RemoteSize           uint32   `protobuf:"varint,3,opt,name=remote_size,json=remoteSize,proto3" json:"remote_size,omitempty"`
+	RemotePage           uint32   `protobuf:"varint,4,opt,name=remote_page,json=remotePage,proto3" json:"remote_page,omitempty"`
+	Order                string   `protobuf:"bytes,5,opt,name=order,proto3" json:"order,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrdersRequest) Reset()         { *m = OrdersRequest{} }
+func (m *OrdersRequest) String() string { return proto.CompactTextString(m) }
+func (*OrdersRequest) ProtoMessage()    {}
+func (*OrdersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{2}
+}
+
+func (m *OrdersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrdersRequest.Unmarshal(m, b)
+}
+func (m *OrdersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrdersRequest.Marshal(b, m, deterministic)
+}
+func (m *OrdersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrdersRequest.Merge(m, src)
+}
+func (m *OrdersRequest) XXX_Size() int {
+	return xxx_messageInfo_OrdersRequest.Size(m)
+}
+func (m *OrdersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrdersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrdersRequest proto.InternalMessageInfo
+
+func (m *OrdersRequest) GetLocalSize() uint32 {
+	if m != nil {
+		return m.LocalSize
+	}
+	return 0
+}
+
+func (m *OrdersRequest) GetLocalPage() uint32 {
+	if m != nil {
+		return m.LocalPage
+	}
+	return 0
+}
+
+func (m *OrdersRequest) GetRemoteSize() uint32 {
+	if m != nil {
+		return m.RemoteSize
+	}
+	return 0
+}
+
+func (m *OrdersRequest) GetRemotePage() uint32 {
+	if m != nil {
+		return m.RemotePage
+	}
+	return 0
+}
+
+func (m *OrdersRequest) GetOrder() string {
+	if m != nil {
+		return m.Order
+	}
+	return ""
+}
+
+type OrdersReply struct {
+	Local                *Page    `protobuf:"bytes,1,opt,name=local,proto3" json:"local,omitempty"`
+	Remote               *Page    `protobuf:"bytes,2,opt,name=remote,proto3" json:"remote,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrdersReply) Reset()         { *m = OrdersReply{} }
+func (m *OrdersReply) String() string { return proto.CompactTextString(m) }
+func (*OrdersReply) ProtoMessage()    {}
+func (*OrdersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{3}
+}
+
+func (m *OrdersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrdersReply.Unmarshal(m, b)
+}
+func (m *OrdersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrdersReply.Marshal(b, m, deterministic)
+}
+func (m *OrdersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrdersReply.Merge(m, src)
+}
+func (m *OrdersReply) XXX_Size() int {
+	return xxx_messageInfo_OrdersReply.Size(m)
+}
+func (m *OrdersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrdersReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrdersReply proto.InternalMessageInfo
+
+func (m *OrdersReply) GetLocal() *Page {
+	if m != nil {
+		return m.Local
+	}
+	return nil
+}
+
+func (m *OrdersReply) GetRemote() *Page {
+	if m != nil {
+		return m.Remote
+	}
+	return nil
+}
+
+// CtxRequest selects a ctx by its id or the hash of its maker tx.
+type CtxRequest struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CtxRequest) Reset()         { *m = CtxRequest{} }
+func (m *CtxRequest) String() string { return proto.CompactTextString(m) }
+func (*CtxRequest) ProtoMessage()    {}
+func (*CtxRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{4}
+}
+
+func (m *CtxRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CtxRequest.Unmarshal(m, b)
+}
+func (m *CtxRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CtxRequest.Marshal(b, m, deterministic)
+}
+func (m *CtxRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CtxRequest.Merge(m, src)
+}
+func (m *CtxRequest) XXX_Size() int {
+	return xxx_messageInfo_CtxRequest.Size(m)
+}
+func (m *CtxRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CtxRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CtxRequest proto.InternalMessageInfo
+
+func (m *CtxRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+// AddressRequest pages the ctxs of an address, a zero size selects all ctxs.
+type AddressRequest struct {
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Size                 uint32   `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Page                 uint32   `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddressRequest) Reset()         { *m = AddressRequest{} }
+func (m *AddressRequest) String() string { return proto.CompactTextString(m) }
+func (*AddressRequest) ProtoMessage()    {}
+func (*AddressRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{5}
+}
+
+func (m *AddressRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddressRequest.Unmarshal(m, b)
+}
+func (m *AddressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddressRequest.Marshal(b, m, deterministic)
+}
+func (m *AddressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddressRequest.Merge(m, src)
+}
+func (m *AddressRequest) XXX_Size() int {
+	return xxx_messageInfo_AddressRequest.Size(m)
+}
+func (m *AddressRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddressRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddressRequest proto.InternalMessageInfo
+
+func (m *AddressRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AddressRequest) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *AddressRequest) GetPage() uint32 {
+	if m != nil {
+		return m.Page
+	}
+	return 0
+}
+
+// OrderFilter selects the order events, unset fields match any order.
+// purpose is the chain the order is taken on.
+type OrderFilter struct {
+	Purpose              uint32   `protobuf:"varint,1,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Taker                string   `protobuf:"bytes,3,opt,name=taker,proto3" json:"taker,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrderFilter) Reset()         { *m = OrderFilter{} }
+func (m *OrderFilter) String() string { return proto.CompactTextString(m) }
+func (*OrderFilter) ProtoMessage()    {}
+func (*OrderFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{6}
+}
+
+func (m *OrderFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderFilter.Unmarshal(m, b)
+}
+func (m *OrderFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderFilter.Marshal(b, m, deterministic)
+}
+func (m *OrderFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderFilter.Merge(m, src)
+}
+func (m *OrderFilter) XXX_Size() int {
+	return xxx_messageInfo_OrderFilter.Size(m)
+}
+func (m *OrderFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderFilter proto.InternalMessageInfo
+
+func (m *OrderFilter) GetPurpose() uint32 {
+	if m != nil {
+		return m.Purpose
+	}
+	return 0
+}
+
+func (m *OrderFilter) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *OrderFilter) GetTaker() string {
+	if m != nil {
+		return m.Taker
+	}
+	return ""
+}
+
+// OrderEvent is a change of an order of the chain served by the hub, type is
+// "new", "taken", "finished", "removed" or "status".
+type OrderEvent struct {
+	Type                 string            `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Chain                uint32            `protobuf:"varint,2,opt,name=chain,proto3" json:"chain,omitempty"`
+	Remote               bool              `protobuf:"varint,3,opt,name=remote,proto3" json:"remote,omitempty"`
+	PrevStatus           string            `protobuf:"bytes,4,opt,name=prev_status,json=prevStatus,proto3" json:"prev_status,omitempty"`
+	Ctx                  *CrossTransaction `protobuf:"bytes,5,opt,name=ctx,proto3" json:"ctx,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *OrderEvent) Reset()         { *m = OrderEvent{} }
+func (m *OrderEvent) String() string { return proto.CompactTextString(m) }
+func (*OrderEvent) ProtoMessage()    {}
+func (*OrderEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{7}
+}
+
+func (m *OrderEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderEvent.Unmarshal(m, b)
+}
+func (m *OrderEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderEvent.Marshal(b, m, deterministic)
+}
+func (m *OrderEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderEvent.Merge(m, src)
+}
+func (m *OrderEvent) XXX_Size() int {
+	return xxx_messageInfo_OrderEvent.Size(m)
+}
+func (m *OrderEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderEvent proto.InternalMessageInfo
+
+func (m *OrderEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetChain() uint32 {
+	if m != nil {
+		return m.Chain
+	}
+	return 0
+}
+
+func (m *OrderEvent) GetRemote() bool {
+	if m != nil {
+		return m.Remote
+	}
+	return false
+}
+
+func (m *OrderEvent) GetPrevStatus() string {
+	if m != nil {
+		return m.PrevStatus
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetCtx() *CrossTransaction {
+	if m != nil {
+		return m.Ctx
+	}
+	return nil
+}
+
+type StatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatusRequest) Reset()         { *m = StatusRequest{} }
+func (m *StatusRequest) String() string { return proto.CompactTextString(m) }
+func (*StatusRequest) ProtoMessage()    {}
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{8}
+}
+
+func (m *StatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusRequest.Unmarshal(m, b)
+}
+func (m *StatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusRequest.Marshal(b, m, deterministic)
+}
+func (m *StatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusRequest.Merge(m, src)
+}
+func (m *StatusRequest) XXX_Size() int {
+	return xxx_messageInfo_StatusRequest.Size(m)
+}
+func (m *StatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusRequest proto.InternalMessageInfo
+
+type ChainStatus struct {
+	Chain                uint32   `protobuf:"varint,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	Height               uint64   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Pending              uint32   `protobuf:"varint,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	LocalOrders          uint32   `protobuf:"varint,4,opt,name=local_orders,json=localOrders,proto3" json:"local_orders,omitempty"`
+	RemoteOrders         uint32   `protobuf:"varint,5,opt,name=remote_orders,json=remoteOrders,proto3" json:"remote_orders,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChainStatus) Reset()         { *m = ChainStatus{} }
+func (m *ChainStatus) String() string { return proto.CompactTextString(m) }
+func (*ChainStatus) ProtoMessage()    {}
+func (*ChainStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{9}
+}
+
+func (m *ChainStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChainStatus.Unmarshal(m, b)
+}
+func (m *ChainStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChainStatus.Marshal(b, m, deterministic)
+}
+func (m *ChainStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChainStatus.Merge(m, src)
+}
+func (m *ChainStatus) XXX_Size() int {
+	return xxx_messageInfo_ChainStatus.Size(m)
+}
+func (m *ChainStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChainStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChainStatus proto.InternalMessageInfo
+
+func (m *ChainStatus) GetChain() uint32 {
+	if m != nil {
+		return m.Chain
+	}
+	return 0
+}
+
+func (m *ChainStatus) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ChainStatus) GetPending() uint32 {
+	if m != nil {
+		return m.Pending
+	}
+	return 0
+}
+
+func (m *ChainStatus) GetLocalOrders() uint32 {
+	if m != nil {
+		return m.LocalOrders
+	}
+	return 0
+}
+
+func (m *ChainStatus) GetRemoteOrders() uint32 {
+	if m != nil {
+		return m.RemoteOrders
+	}
+	return 0
+}
+
+type Peer struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PeerId               string   `protobuf:"bytes,2,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	Addrs                []string `protobuf:"bytes,3,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	Connected            bool     `protobuf:"varint,4,opt,name=connected,proto3" json:"connected,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Peer) Reset()         { *m = Peer{} }
+func (m *Peer) String() string { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()    {}
+func (*Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{10}
+}
+
+func (m *Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Peer.Unmarshal(m, b)
+}
+func (m *Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Peer.Marshal(b, m, deterministic)
+}
+func (m *Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Peer.Merge(m, src)
+}
+func (m *Peer) XXX_Size() int {
+	return xxx_messageInfo_Peer.Size(m)
+}
+func (m *Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Peer proto.InternalMessageInfo
+
+func (m *Peer) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Peer) GetPeerId() string {
+	if m != nil {
+		return m.PeerId
+	}
+	return ""
+}
+
+func (m *Peer) GetAddrs() []string {
+	if m != nil {
+		return m.Addrs
+	}
+	return nil
+}
+
+func (m *Peer) GetConnected() bool {
+	if m != nil {
+		return m.Connected
+	}
+	return false
+}
+
+type StatusReply struct {
+	Chains               []*ChainStatus `protobuf:"bytes,1,rep,name=chains,proto3" json:"chains,omitempty"`
+	Peers                []*Peer        `protobuf:"bytes,2,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *StatusReply) Reset()         { *m = StatusReply{} }
+func (m *StatusReply) String() string { return proto.CompactTextString(m) }
+func (*StatusReply) ProtoMessage()    {}
+func (*StatusReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{11}
+}
+
+func (m *StatusReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatusReply.Unmarshal(m, b)
+}
+func (m *StatusReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatusReply.Marshal(b, m, deterministic)
+}
+func (m *StatusReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatusReply.Merge(m, src)
+}
+func (m *StatusReply) XXX_Size() int {
+	return xxx_messageInfo_StatusReply.Size(m)
+}
+func (m *StatusReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatusReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatusReply proto.InternalMessageInfo
+
+func (m *StatusReply) GetChains() []*ChainStatus {
+	if m != nil {
+		return m.Chains
+	}
+	return nil
+}
+
+func (m *StatusReply) GetPeers() []*Peer {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type ChainRequest struct {
+	Chain                uint32   `protobuf:"varint,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChainRequest) Reset()         { *m = ChainRequest{} }
+func (m *ChainRequest) String() string { return proto.CompactTextString(m) }
+func (*ChainRequest) ProtoMessage()    {}
+func (*ChainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{12}
+}
+
+func (m *ChainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChainRequest.Unmarshal(m, b)
+}
+func (m *ChainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChainRequest.Marshal(b, m, deterministic)
+}
+func (m *ChainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChainRequest.Merge(m, src)
+}
+func (m *ChainRequest) XXX_Size() int {
+	return xxx_messageInfo_ChainRequest.Size(m)
+}
+func (m *ChainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChainRequest proto.InternalMessageInfo
+
+func (m *ChainRequest) GetChain() uint32 {
+	if m != nil {
+		return m.Chain
+	}
+	return 0
+}
+
+type RescanRequest struct {
+	Chain                uint32   `protobuf:"varint,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	From                 uint64   `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RescanRequest) Reset()         { *m = RescanRequest{} }
+func (m *RescanRequest) String() string { return proto.CompactTextString(m) }
+func (*RescanRequest) ProtoMessage()    {}
+func (*RescanRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{13}
+}
+
+func (m *RescanRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RescanRequest.Unmarshal(m, b)
+}
+func (m *RescanRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RescanRequest.Marshal(b, m, deterministic)
+}
+func (m *RescanRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RescanRequest.Merge(m, src)
+}
+func (m *RescanRequest) XXX_Size() int {
+	return xxx_messageInfo_RescanRequest.Size(m)
+}
+func (m *RescanRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RescanRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RescanRequest proto.InternalMessageInfo
+
+func (m *RescanRequest) GetChain() uint32 {
+	if m != nil {
+		return m.Chain
+	}
+	return 0
+}
+
+func (m *RescanRequest) GetFrom() uint64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+type DropRequest struct {
+	Chain                uint32   `protobuf:"varint,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	Origin               uint32   `protobuf:"varint,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	CtxId                []byte   `protobuf:"bytes,3,opt,name=ctx_id,json=ctxId,proto3" json:"ctx_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DropRequest) Reset()         { *m = DropRequest{} }
+func (m *DropRequest) String() string { return proto.CompactTextString(m) }
+func (*DropRequest) ProtoMessage()    {}
+func (*DropRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{14}
+}
+
+func (m *DropRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropRequest.Unmarshal(m, b)
+}
+func (m *DropRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropRequest.Marshal(b, m, deterministic)
+}
+func (m *DropRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropRequest.Merge(m, src)
+}
+func (m *DropRequest) XXX_Size() int {
+	return xxx_messageInfo_DropRequest.Size(m)
+}
+func (m *DropRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropRequest proto.InternalMessageInfo
+
+func (m *DropRequest) GetChain() uint32 {
+	if m != nil {
+		return m.Chain
+	}
+	return 0
+}
+
+func (m *DropRequest) GetOrigin() uint32 {
+	if m != nil {
+		return m.Origin
+	}
+	return 0
+}
+
+func (m *DropRequest) GetCtxId() []byte {
+	if m != nil {
+		return m.CtxId
+	}
+	return nil
+}
+
+type AdminReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdminReply) Reset()         { *m = AdminReply{} }
+func (m *AdminReply) String() string { return proto.CompactTextString(m) }
+func (*AdminReply) ProtoMessage()    {}
+func (*AdminReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b3103f8d3056b01c, []int{15}
+}
+
+func (m *AdminReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminReply.Unmarshal(m, b)
+}
+func (m *AdminReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminReply.Marshal(b, m, deterministic)
+}
+func (m *AdminReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminReply.Merge(m, src)
+}
+func (m *AdminReply) XXX_Size() int {
+	return xxx_messageInfo_AdminReply.Size(m)
+}
+func (m *AdminReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminReply proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*CrossTransaction)(nil), "hubpb.CrossTransaction")
+	proto.RegisterType((*Page)(nil), "hubpb.Page")
+	proto.RegisterType((*OrdersRequest)(nil), "hubpb.OrdersRequest")
+	proto.RegisterType((*OrdersReply)(nil), "hubpb.OrdersReply")
+	proto.RegisterType((*CtxRequest)(nil), "hubpb.CtxRequest")
+	proto.RegisterType((*AddressRequest)(nil), "hubpb.AddressRequest")
+	proto.RegisterType((*OrderFilter)(nil), "hubpb.OrderFilter")
+	proto.RegisterType((*OrderEvent)(nil), "hubpb.OrderEvent")
+	proto.RegisterType((*StatusRequest)(nil), "hubpb.StatusRequest")
+	proto.RegisterType((*ChainStatus)(nil), "hubpb.ChainStatus")
+	proto.RegisterType((*Peer)(nil), "hubpb.Peer")
+	proto.RegisterType((*StatusReply)(nil), "hubpb.StatusReply")
+	proto.RegisterType((*ChainRequest)(nil), "hubpb.ChainRequest")
+	proto.RegisterType((*RescanRequest)(nil), "hubpb.RescanRequest")
+	proto.RegisterType((*DropRequest)(nil), "hubpb.DropRequest")
+	proto.RegisterType((*AdminReply)(nil), "hubpb.AdminReply")
+}
+
+func init() { proto.RegisterFile("hub.proto", fileDescriptor_b3103f8d3056b01c) }
+
+var fileDescriptor_b3103f8d3056b01c = []byte{
+	// 964 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x96, 0xed, 0xc4, 0xdd, 0x1c, 0x27, 0x5d, 0x76, 0xe8, 0xb2, 0xa3, 0x0a, 0x44, 0xd6, 0xcb,
+	0x45, 0x01, 0x6d, 0x03, 0x05, 0xf1, 0x73, 0x59, 0xc2, 0xcf, 0xf6, 0x6a, 0x57, 0xee, 0x22, 0x24,
+	0x84, 0x54, 0x4d, 0xec, 0x21, 0xb6, 0xea, 0x78, 0xcc, 0xcc, 0x24, 0xa4, 0xfb, 0x06, 0xbc, 0x02,
+	0x12, 0x97, 0x3c, 0x08, 0x8f, 0xc2, 0x9b, 0xa0, 0x73, 0x66, 0x9c, 0x9f, 0xb2, 0x54, 0xe2, 0x6e,
+	0xbe, 0xf3, 0x33, 0x3e, 0xe7, 0x3b, 0xdf, 0x9c, 0x04, 0x06, 0xe5, 0x72, 0x76, 0xda, 0x6a, 0x65,
+	0x15, 0xeb, 0x97, 0xcb, 0x59, 0x3b, 0x4b, 0xff, 0x0a, 0xe1, 0x8d, 0xa9, 0x56, 0xc6, 0xbc, 0xd4,
+	0xa2, 0x31, 0x22, 0xb7, 0x95, 0x6a, 0xd8, 0x43, 0x88, 0x73, 0xbb, 0xbe, 0xaa, 0x0a, 0x1e, 0x8c,
+	0x83, 0x93, 0x61, 0xd6, 0xcf, 0xed, 0xfa, 0xa2, 0x60, 0x8f, 0xe0, 0xc0, 0xae, 0xaf, 0x4a, 0x61,
+	0x4a, 0x1e, 0x92, 0x3d, 0xb6, 0xeb, 0x67, 0xc2, 0x94, 0xec, 0x1d, 0x80, 0x59, 0xad, 0xf2, 0x6b,
+	0xe7, 0x8b, 0xc8, 0x37, 0x20, 0x0b, 0xb9, 0x8f, 0xa0, 0xbf, 0x12, 0xf5, 0x52, 0xf2, 0xde, 0x38,
+	0x38, 0x19, 0x64, 0x0e, 0xb0, 0xb7, 0x20, 0xce, 0x4b, 0xa1, 0xe7, 0x92, 0xf7, 0xc9, 0xec, 0x11,
+	0x63, 0xd0, 0xfb, 0x59, 0xab, 0x05, 0x8f, 0xc9, 0x4a, 0x67, 0x76, 0x08, 0xa1, 0x55, 0xfc, 0x80,
+	0x2c, 0xa1, 0x55, 0x98, 0xab, 0x74, 0x35, 0xaf, 0x1a, 0x7e, 0x6f, 0x1c, 0x9c, 0x8c, 0x32, 0x8f,
+	0x18, 0x87, 0x83, 0x76, 0xa9, 0x5b, 0x65, 0x24, 0x1f, 0x90, 0xa3, 0x83, 0xe4, 0x11, 0x37, 0xb5,
+	0x12, 0x05, 0x07, 0xaa, 0xaf, 0x83, 0x78, 0x97, 0xb1, 0xc2, 0x2e, 0x0d, 0x4f, 0x5c, 0x1d, 0x0e,
+	0xb1, 0x21, 0x04, 0x2b, 0x3e, 0x1c, 0x47, 0x27, 0xc3, 0x2c, 0x58, 0x21, 0xd2, 0x7c, 0xe4, 0x90,
+	0x46, 0x64, 0xf8, 0xa1, 0x43, 0x26, 0xbd, 0x80, 0xde, 0x0b, 0x31, 0x97, 0xec, 0x43, 0xe8, 0xe5,
+	0x76, 0x6d, 0x78, 0x30, 0x8e, 0x4e, 0x92, 0xb3, 0x47, 0xa7, 0xc4, 0xf0, 0xe9, 0x6d, 0x76, 0x33,
+	0x0a, 0x42, 0x52, 0xac, 0xb2, 0xa2, 0x26, 0x2a, 0x7b, 0x99, 0x03, 0xe9, 0x9f, 0x01, 0x8c, 0x9e,
+	0xeb, 0x42, 0x6a, 0x93, 0xc9, 0x5f, 0x96, 0xd2, 0x58, 0xe4, 0xb6, 0x56, 0xb9, 0xa8, 0xaf, 0x4c,
+	0xf5, 0x4a, 0xd2, 0x3c, 0x46, 0xd9, 0x80, 0x2c, 0x97, 0xd5, 0x2b, 0xb9, 0x75, 0xb7, 0x62, 0x2e,
+	0x79, 0xb8, 0xe3, 0xa6, 0x92, 0xde, 0x85, 0x44, 0xcb, 0x85, 0xb2, 0xd2, 0xa5, 0x47, 0xe4, 0x07,
+	0x67, 0xa2, 0xfc, 0x6d, 0x00, 0x5d, 0xd0, 0xdb, 0x0d, 0xa0, 0x1b, 0x8e, 0xa0, 0xaf, 0xb0, 0x20,
+	0x3f, 0x25, 0x07, 0xd2, 0xef, 0x21, 0xe9, 0xca, 0x6c, 0xeb, 0x1b, 0xf6, 0x18, 0xfa, 0xf4, 0x4d,
+	0xaa, 0x2f, 0x39, 0x4b, 0x7c, 0xeb, 0x78, 0x41, 0xe6, 0x3c, 0xec, 0x09, 0xc4, 0xee, 0x56, 0x1e,
+	0xfe, 0x3b, 0xc6, 0xbb, 0xd2, 0x31, 0xc0, 0xd4, 0xae, 0xbb, 0xd6, 0x19, 0xf4, 0x48, 0x50, 0x4e,
+	0x84, 0x74, 0x4e, 0x33, 0x38, 0x3c, 0x2f, 0x0a, 0x2d, 0xcd, 0x86, 0x20, 0x0e, 0x07, 0xc2, 0x59,
+	0x28, 0x70, 0x90, 0x75, 0x10, 0xf3, 0xa9, 0x6b, 0xc7, 0x0a, 0x9d, 0xd1, 0x46, 0x8d, 0x3a, 0x26,
+	0xe8, 0x9c, 0x5e, 0xfa, 0x66, 0xbe, 0xad, 0x6a, 0x2b, 0xf5, 0xae, 0x88, 0x82, 0x7d, 0x11, 0x21,
+	0x17, 0xbf, 0x36, 0x52, 0xf3, 0xd0, 0x73, 0x81, 0x80, 0x26, 0x29, 0xae, 0xa5, 0xa6, 0x3b, 0x07,
+	0x99, 0x03, 0xe9, 0xef, 0x01, 0x00, 0xdd, 0xfa, 0xcd, 0x4a, 0x36, 0xd4, 0x8b, 0xbd, 0x69, 0xa5,
+	0x2f, 0x91, 0xce, 0x98, 0x98, 0x97, 0xa2, 0x6a, 0x7c, 0x81, 0x0e, 0xa0, 0x1e, 0x3d, 0x51, 0x78,
+	0xdf, 0xbd, 0x8e, 0x1b, 0x9c, 0x54, 0xab, 0xe5, 0xea, 0xca, 0x8b, 0xd5, 0xbd, 0x25, 0x40, 0xd3,
+	0x25, 0x59, 0xd8, 0xfb, 0x10, 0xe5, 0x76, 0x4d, 0x73, 0xba, 0x43, 0x7d, 0x18, 0x93, 0xde, 0x87,
+	0x91, 0x4b, 0xf2, 0x24, 0xa6, 0x7f, 0x04, 0x90, 0x4c, 0xf1, 0xf3, 0xfe, 0xae, 0x4d, 0x69, 0xc1,
+	0xad, 0xd2, 0x4a, 0x59, 0xcd, 0x4b, 0xeb, 0x45, 0xeb, 0x11, 0x31, 0x26, 0x9b, 0xa2, 0x6a, 0xe6,
+	0x9e, 0xd7, 0x0e, 0xb2, 0xc7, 0x30, 0x74, 0xf2, 0x24, 0xd9, 0x18, 0xaf, 0xaf, 0x84, 0x6c, 0x4e,
+	0x40, 0xec, 0x09, 0x8c, 0xbc, 0x02, 0x7d, 0x4c, 0x9f, 0x62, 0x86, 0xce, 0xe8, 0x82, 0xd2, 0x1c,
+	0x7a, 0x2f, 0xa4, 0xd4, 0xb8, 0x08, 0xfc, 0x56, 0xea, 0x65, 0x61, 0x45, 0x2b, 0xa9, 0x95, 0x52,
+	0xe3, 0xaa, 0x72, 0x33, 0x89, 0x11, 0x5e, 0x14, 0xd8, 0x00, 0xca, 0xc0, 0xf0, 0x68, 0x1c, 0xe1,
+	0x50, 0x08, 0xb0, 0xb7, 0x61, 0x90, 0xab, 0xa6, 0x91, 0xb9, 0x95, 0x05, 0xd5, 0x72, 0x2f, 0xdb,
+	0x1a, 0xd2, 0x9f, 0x20, 0xe9, 0x58, 0x41, 0x51, 0x7f, 0x40, 0x0b, 0xaa, 0x6a, 0xba, 0x07, 0xcd,
+	0x3a, 0x4a, 0xb7, 0x3c, 0x65, 0x3e, 0x02, 0x1f, 0x00, 0x7e, 0xd8, 0xf0, 0x70, 0x1c, 0xed, 0x8a,
+	0x5b, 0x4a, 0x9d, 0x39, 0x4f, 0xfa, 0x1e, 0x0c, 0x29, 0xb3, 0xd3, 0xed, 0x6b, 0x29, 0x4e, 0xbf,
+	0x84, 0x51, 0x26, 0x4d, 0x2e, 0xee, 0x0e, 0xdb, 0x2c, 0x49, 0x37, 0x07, 0x3a, 0xa7, 0x19, 0x24,
+	0x5f, 0x6b, 0xd5, 0xde, 0x9d, 0xb8, 0xdd, 0x9c, 0xe1, 0xde, 0xe6, 0xdc, 0xae, 0xfc, 0x68, 0x67,
+	0xe5, 0xa7, 0x43, 0x80, 0xf3, 0x62, 0x81, 0x45, 0xb7, 0xf5, 0xcd, 0xd9, 0x6f, 0x21, 0x44, 0xcf,
+	0x96, 0x33, 0x76, 0x06, 0xb1, 0x1f, 0xde, 0x91, 0x6f, 0x74, 0x6f, 0x67, 0x1d, 0xb3, 0x5b, 0x56,
+	0x64, 0xf3, 0x53, 0x88, 0xbf, 0x93, 0x76, 0x6a, 0xd7, 0xec, 0x41, 0xc7, 0xe3, 0xe6, 0xa5, 0x1f,
+	0xff, 0x97, 0x5a, 0xd9, 0x04, 0x06, 0xcf, 0xf1, 0x91, 0x4d, 0x71, 0x65, 0x3e, 0xf4, 0x51, 0xfb,
+	0x0b, 0xe0, 0x78, 0x77, 0x93, 0x60, 0xc2, 0x4b, 0x71, 0xfd, 0x3f, 0x12, 0x3e, 0x83, 0xe4, 0x07,
+	0x61, 0xf3, 0xd2, 0x37, 0xb4, 0x57, 0xba, 0x5b, 0x08, 0xc7, 0x0f, 0x76, 0x6d, 0xf4, 0x9c, 0x3f,
+	0x0a, 0xce, 0xfe, 0x0e, 0xa0, 0x4f, 0xd4, 0x20, 0x1b, 0xdd, 0xab, 0xf1, 0x81, 0x7b, 0x6f, 0xeb,
+	0x98, 0xdd, 0xb2, 0x22, 0x1b, 0x1f, 0x43, 0xec, 0xc6, 0xbc, 0xc9, 0xd9, 0x9b, 0xfa, 0xe6, 0x93,
+	0x5b, 0xf2, 0xd9, 0xe7, 0xa8, 0x0c, 0xfc, 0xc5, 0x3a, 0x6f, 0xf2, 0x52, 0x69, 0xc3, 0xde, 0xdc,
+	0xd5, 0xe3, 0x1d, 0x89, 0x5f, 0xc0, 0x7d, 0xa7, 0x8b, 0xcd, 0x7b, 0xda, 0x74, 0xb9, 0xa3, 0x97,
+	0xd7, 0x64, 0x7e, 0x35, 0xf9, 0xf1, 0xe9, 0xbc, 0xb2, 0xf8, 0x9f, 0x21, 0x57, 0x8b, 0x89, 0xa9,
+	0x16, 0x6d, 0x2d, 0x49, 0x46, 0x4f, 0x95, 0x9e, 0x4f, 0x72, 0x1c, 0x56, 0xb9, 0x9c, 0x4d, 0x44,
+	0x5b, 0x4d, 0x28, 0x77, 0x16, 0xd3, 0x7f, 0x8b, 0x4f, 0xfe, 0x19, 0x00, 0x0d, 0x1d, 0x1f, 0x53,
+	0x68, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// HubClient is the client API for Hub service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type HubClient interface {
+	// Orders returns a page of the unfinished local orders and a page of the
+	// remote orders waiting to be taken.
+	Orders(ctx context.Context, in *OrdersRequest, opts ...grpc.CallOption) (*OrdersReply, error)
+	// GetCtx returns the ctx with the id or the hash of its maker tx.
+	GetCtx(ctx context.Context, in *CtxRequest, opts ...grpc.CallOption) (*CrossTransaction, error)
+	// OwnerCtxs returns a page of the ctxs made by an address.
+	OwnerCtxs(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*Page, error)
+	// TakerCtxs returns a page of the ctxs designated to be taken by an address.
+	TakerCtxs(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*Page, error)
+	// WatchOrders streams the changes of the orders matching the filter.
+	WatchOrders(ctx context.Context, in *OrderFilter, opts ...grpc.CallOption) (Hub_WatchOrdersClient, error)
+}
+
+type hubClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewHubClient(cc *grpc.ClientConn) HubClient {
+	return &hubClient{cc}
+}
+
+func (c *hubClient) Orders(ctx context.Context, in *OrdersRequest, opts ...grpc.CallOption) (*OrdersReply, error) {
+	out := new(OrdersReply)
+	err := c.cc.Invoke(ctx, "/hubpb.Hub/Orders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) GetCtx(ctx context.Context, in *CtxRequest, opts ...grpc.CallOption) (*CrossTransaction, error) {
+	out := new(CrossTransaction)
+	err := c.cc.Invoke(ctx, "/hubpb.Hub/GetCtx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) OwnerCtxs(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*Page, error) {
+	out := new(Page)
+	err := c.cc.Invoke(ctx, "/hubpb.Hub/OwnerCtxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) TakerCtxs(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*Page, error) {
+	out := new(Page)
+	err := c.cc.Invoke(ctx, "/hubpb.Hub/TakerCtxs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) WatchOrders(ctx context.Context, in *OrderFilter, opts ...grpc.CallOption) (Hub_WatchOrdersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Hub_serviceDesc.Streams[0], "/hubpb.Hub/WatchOrders", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hubWatchOrdersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Hub_WatchOrdersClient interface {
+	Recv() (*OrderEvent, error)
+	grpc.ClientStream
+}
+
+type hubWatchOrdersClient struct {
+	grpc.ClientStream
+}
+
+func (x *hubWatchOrdersClient) Recv() (*OrderEvent, error) {
+	m := new(OrderEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// HubServer is the server API for Hub service.
+type HubServer interface {
+	// Orders returns a page of the unfinished local orders and a page of the
+	// remote orders waiting to be taken.
+	Orders(context.Context, *OrdersRequest) (*OrdersReply, error)
+	// GetCtx returns the ctx with the id or the hash of its maker tx.
+	GetCtx(context.Context, *CtxRequest) (*CrossTransaction, error)
+	// OwnerCtxs returns a page of the ctxs made by an address.
+	OwnerCtxs(context.Context, *AddressRequest) (*Page, error)
+	// TakerCtxs returns a page of the ctxs designated to be taken by an address.
+	TakerCtxs(context.Context, *AddressRequest) (*Page, error)
+	// WatchOrders streams the changes of the orders matching the filter.
+	WatchOrders(*OrderFilter, Hub_WatchOrdersServer) error
+}
+
+// UnimplementedHubServer can be embedded to have forward compatible implementations.
+type UnimplementedHubServer struct {
+}
+
+func (*UnimplementedHubServer) Orders(ctx context.Context, req *OrdersRequest) (*OrdersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Orders not implemented")
+}
+func (*UnimplementedHubServer) GetCtx(ctx context.Context, req *CtxRequest) (*CrossTransaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCtx not implemented")
+}
+func (*UnimplementedHubServer) OwnerCtxs(ctx context.Context, req *AddressRequest) (*Page, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OwnerCtxs not implemented")
+}
+func (*UnimplementedHubServer) TakerCtxs(ctx context.Context, req *AddressRequest) (*Page, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakerCtxs not implemented")
+}
+func (*UnimplementedHubServer) WatchOrders(req *OrderFilter, srv Hub_WatchOrdersServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOrders not implemented")
+}
+
+func RegisterHubServer(s *grpc.Server, srv HubServer) {
+	s.RegisterService(&_Hub_serviceDesc, srv)
+}
+
+func _Hub_Orders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).Orders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hubpb.Hub/Orders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).Orders(ctx, req.(*OrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_GetCtx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CtxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).GetCtx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hubpb.Hub/GetCtx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).GetCtx(ctx, req.(*CtxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_OwnerCtxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).OwnerCtxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hubpb.Hub/OwnerCtxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).OwnerCtxs(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_TakerCtxs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).TakerCtxs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hubpb.Hub/TakerCtxs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).TakerCtxs(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_WatchOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OrderFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HubServer).WatchOrders(m, &hubWatchOrdersServer{stream})
+}
+
+type Hub_WatchOrdersServer interface {
+	Send(*OrderEvent) error
+	grpc.ServerStream
+}
+
+type hubWatchOrdersServer struct {
+	grpc.ServerStream
+}
+
+func (x *hubWatchOrdersServer) Send(m *OrderEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Hub_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hubpb.Hub",
+	HandlerType: (*HubServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Orders",
+			Handler:    _Hub_Orders_Handler,
+		},
+		{
+			MethodName: "GetCtx",
+			Handler:    _Hub_GetCtx_Handler,
+		},
+		{
+			MethodName: "OwnerCtxs",
+			Handler:    _Hub_OwnerCtxs_Handler,
+		},
+		{
+			MethodName: "TakerCtxs",
+			Handler:    _Hub_TakerCtxs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchOrders",
+			Handler:       _Hub_WatchOrders_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "hub.proto",
+}
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminClient interface {
+	// Status returns the scan progress of the chains and the peers of the hub.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error)
+	// Rescan restarts the scan of a chain from a lower block.
+	Rescan(ctx context.Context, in *RescanRequest, opts ...grpc.CallOption) (*AdminReply, error)
+	// ReloadAnchors reads the anchor sets of a chain from its contract.
+	ReloadAnchors(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (*AdminReply, error)
+	// DropRemoteOrder removes a remote order that is not taken yet.
+	DropRemoteOrder(ctx context.Context, in *DropRequest, opts ...grpc.CallOption) (*AdminReply, error)
+}
+
+type adminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAdminClient(cc *grpc.ClientConn) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusReply, error) {
+	out := new(StatusReply)
+	err := c.cc.Invoke(ctx, "/hubpb.Admin/Status", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Rescan(ctx context.Context, in *RescanRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/hubpb.Admin/Rescan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ReloadAnchors(ctx context.Context, in *ChainRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/hubpb.Admin/ReloadAnchors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DropRemoteOrder(ctx context.Context, in *DropRequest, opts ...grpc.CallOption) (*AdminReply, error) {
+	out := new(AdminReply)
+	err := c.cc.Invoke(ctx, "/hubpb.Admin/DropRemoteOrder", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+type AdminServer interface {
+	// Status returns the scan progress of the chains and the peers of the hub.
+	Status(context.Context, *StatusRequest) (*StatusReply, error)
+	// Rescan restarts the scan of a chain from a lower block.
+	Rescan(context.Context, *RescanRequest) (*AdminReply, error)
+	// ReloadAnchors reads the anchor sets of a chain from its contract.
+	ReloadAnchors(context.Context, *ChainRequest) (*AdminReply, error)
+	// DropRemoteOrder removes a remote order that is not taken yet.
+	DropRemoteOrder(context.Context, *DropRequest) (*AdminReply, error)
+}
+
+// UnimplementedAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (*UnimplementedAdminServer) Status(ctx context.Context, req *StatusRequest) (*StatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (*UnimplementedAdminServer) Rescan(ctx context.Context, req *RescanRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rescan not implemented")
+}
+func (*UnimplementedAdminServer) ReloadAnchors(ctx context.Context, req *ChainRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadAnchors not implemented")
+}
+func (*UnimplementedAdminServer) DropRemoteOrder(ctx context.Context, req *DropRequest) (*AdminReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropRemoteOrder not implemented")
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hubpb.Admin/Status",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Rescan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RescanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Rescan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hubpb.Admin/Rescan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Rescan(ctx, req.(*RescanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ReloadAnchors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReloadAnchors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hubpb.Admin/ReloadAnchors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReloadAnchors(ctx, req.(*ChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DropRemoteOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DropRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DropRemoteOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hubpb.Admin/DropRemoteOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DropRemoteOrder(ctx, req.(*DropRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "hubpb.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _Admin_Status_Handler,
+		},
+		{
+			MethodName: "Rescan",
+			Handler:    _Admin_Rescan_Handler,
+		},
+		{
+			MethodName: "ReloadAnchors",
+			Handler:    _Admin_ReloadAnchors_Handler,
+		},
+		{
+			MethodName: "DropRemoteOrder",
+			Handler:    _Admin_DropRemoteOrder_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "hub.proto",
+}
@@ -0,0 +1,198 @@
+package api
+
+// openAPIDoc describes the REST gateway, keep it in line with the handlers of
+// gateway.go and the RPC types they return.
+const openAPIDoc = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "CrossHub gateway",
+    "description": "Orders, cross transactions, anchors, peers and status of a crosshub node. Numbers of the RPC types are hex encoded.",
+    "version": "1.0"
+  },
+  "security": [{"apiKey": []}, {"bearer": []}, {}],
+  "paths": {
+    "/v1/orders": {
+      "get": {
+        "summary": "Unfinished local orders and remote orders waiting to be taken",
+        "parameters": [
+          {"name": "localSize", "in": "query", "schema": {"type": "integer", "minimum": 0}, "description": "local page size, 0 for all orders"},
+          {"name": "localPage", "in": "query", "schema": {"type": "integer", "minimum": 1, "default": 1}},
+          {"name": "remoteSize", "in": "query", "schema": {"type": "integer", "minimum": 0}, "description": "remote page size, 0 for all orders"},
+          {"name": "remotePage", "in": "query", "schema": {"type": "integer", "minimum": 1, "default": 1}},
+          {"name": "order", "in": "query", "schema": {"type": "string", "enum": ["price", "-price", "value", "-value", "insertion", "-insertion"], "default": "price"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "local and remote pages",
+            "content": {"application/json": {"schema": {
+              "type": "object",
+              "properties": {
+                "local": {"$ref": "#/components/schemas/Page"},
+                "remote": {"$ref": "#/components/schemas/Page"}
+              }
+            }}}
+          },
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/ctxs": {
+      "get": {
+        "summary": "Cross transactions of an owner, a designated taker or a destination value",
+        "description": "exactly one of owner, taker and value is used",
+        "parameters": [
+          {"name": "owner", "in": "query", "schema": {"type": "string"}},
+          {"name": "taker", "in": "query", "schema": {"type": "string"}},
+          {"name": "value", "in": "query", "schema": {"type": "string"}, "description": "decimal or 0x prefixed destination value"},
+          {"name": "size", "in": "query", "schema": {"type": "integer", "minimum": 0}, "description": "page size, 0 for all"},
+          {"name": "page", "in": "query", "schema": {"type": "integer", "minimum": 1, "default": 1}}
+        ],
+        "responses": {
+          "200": {"description": "a page", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Page"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/ctxs/{hash}": {
+      "get": {
+        "summary": "Cross transaction by id or maker tx hash",
+        "parameters": [
+          {"name": "hash", "in": "path", "required": true, "schema": {"type": "string", "pattern": "^0x[0-9a-fA-F]{64}$"}}
+        ],
+        "responses": {
+          "200": {"description": "the cross transaction", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/CrossTransaction"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/anchors": {
+      "get": {
+        "summary": "Anchor sets trusted by the served chains",
+        "responses": {
+          "200": {"description": "anchor sets", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/AnchorSet"}}}}}
+        }
+      }
+    },
+    "/v1/anchors/changes": {
+      "get": {
+        "summary": "Latest anchor set changes of the served chains",
+        "responses": {
+          "200": {"description": "anchor set changes", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/AnchorChange"}}}}}
+        }
+      }
+    },
+    "/v1/peers": {
+      "get": {
+        "summary": "Other hubs of the swarm",
+        "responses": {
+          "200": {"description": "peers", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Peer"}}}}}
+        }
+      }
+    },
+    "/v1/status": {
+      "get": {
+        "summary": "Scan progress and order counts of the served chains",
+        "responses": {
+          "200": {"description": "chain statuses", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/ChainStatus"}}}}}
+        }
+      }
+    },
+    "/v1/openapi.json": {
+      "get": {
+        "summary": "This document",
+        "responses": {"200": {"description": "OpenAPI document"}}
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "apiKey": {"type": "apiKey", "in": "header", "name": "X-Api-Key"},
+      "bearer": {"type": "http", "scheme": "bearer", "description": "an api key or an HS256 jwt, required once credentials are configured"}
+    },
+    "responses": {
+      "Error": {
+        "description": "invalid request or resource not found",
+        "content": {"application/json": {"schema": {"type": "object", "properties": {"error": {"type": "string"}}}}}
+      }
+    },
+    "schemas": {
+      "Hex": {"type": "string", "pattern": "^0x[0-9a-fA-F]*$"},
+      "CrossTransaction": {
+        "type": "object",
+        "properties": {
+          "ctxId": {"$ref": "#/components/schemas/Hex"},
+          "txHash": {"$ref": "#/components/schemas/Hex"},
+          "blockHash": {"$ref": "#/components/schemas/Hex"},
+          "value": {"$ref": "#/components/schemas/Hex"},
+          "charge": {"$ref": "#/components/schemas/Hex"},
+          "from": {"type": "string"},
+          "to": {"type": "string"},
+          "origin": {"$ref": "#/components/schemas/Hex"},
+          "purpose": {"$ref": "#/components/schemas/Hex"},
+          "payload": {"$ref": "#/components/schemas/Hex"},
+          "status": {"type": "string", "enum": ["pending", "waiting", "illegal", "executing", "executed", "finishing", "finished"]},
+          "v": {"type": "array", "items": {"$ref": "#/components/schemas/Hex"}},
+          "r": {"type": "array", "items": {"$ref": "#/components/schemas/Hex"}},
+          "s": {"type": "array", "items": {"$ref": "#/components/schemas/Hex"}}
+        }
+      },
+      "Page": {
+        "type": "object",
+        "properties": {
+          "data": {
+            "type": "object",
+            "description": "cross transactions keyed by the chain they are made on",
+            "additionalProperties": {"type": "array", "items": {"$ref": "#/components/schemas/CrossTransaction"}}
+          },
+          "total": {"type": "integer", "description": "count over all pages"}
+        }
+      },
+      "AnchorSet": {
+        "type": "object",
+        "properties": {
+          "chain": {"$ref": "#/components/schemas/Hex"},
+          "purpose": {"$ref": "#/components/schemas/Hex"},
+          "anchors": {"type": "array", "items": {"$ref": "#/components/schemas/Hex"}},
+          "signConfirmCount": {"$ref": "#/components/schemas/Hex"},
+          "number": {"$ref": "#/components/schemas/Hex"}
+        }
+      },
+      "AnchorChange": {
+        "type": "object",
+        "properties": {
+          "chain": {"$ref": "#/components/schemas/Hex"},
+          "purpose": {"$ref": "#/components/schemas/Hex"},
+          "number": {"$ref": "#/components/schemas/Hex"},
+          "added": {"type": "array", "items": {"$ref": "#/components/schemas/Hex"}},
+          "removed": {"type": "array", "items": {"$ref": "#/components/schemas/Hex"}},
+          "signConfirmCount": {"$ref": "#/components/schemas/Hex"}
+        }
+      },
+      "Peer": {
+        "type": "object",
+        "properties": {
+          "id": {"$ref": "#/components/schemas/Hex"},
+          "peerId": {"type": "string"},
+          "addrs": {"type": "array", "items": {"type": "string"}},
+          "connected": {"type": "boolean"},
+          "state": {"type": "string", "enum": ["connecting", "connected", "disconnected"]},
+          "since": {"$ref": "#/components/schemas/Hex"},
+          "lastSeen": {"$ref": "#/components/schemas/Hex"},
+          "rtt": {"$ref": "#/components/schemas/Hex"}
+        }
+      },
+      "ChainStatus": {
+        "type": "object",
+        "properties": {
+          "chain": {"$ref": "#/components/schemas/Hex"},
+          "height": {"$ref": "#/components/schemas/Hex"},
+          "pending": {"$ref": "#/components/schemas/Hex"},
+          "localOrders": {"$ref": "#/components/schemas/Hex"},
+          "remoteOrders": {"$ref": "#/components/schemas/Hex"}
+        }
+      }
+    }
+  }
+}
+`
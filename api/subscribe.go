@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/simplechain-org/crosshub/core"
+	db "github.com/simplechain-org/crosshub/database"
+
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/log"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+// Types of the order events pushed by cross_subscribe("orders").
+const (
+	OrderNew      = "new"      // a local order is made or a remote order can be taken
+	OrderTaken    = "taken"    // a remote order is taken by a taker tx
+	OrderFinished = "finished" // a local order is finished by makerFinish
+	OrderRemoved  = "removed"  // an order is dropped by a reorg or the signature expiry
+	OrderStatus   = "status"   // any other status change
+)
+
+// orderBuffer is the number of events queued for a subscriber, the events of a
+// subscriber falling further behind are dropped.
+const orderBuffer = 256
+
+// RPCOrderEvent is a change of an order of the chain served by the hub.
+type RPCOrderEvent struct {
+	Type       string               `json:"type"`
+	Chain      hexutil.Uint         `json:"chain"`
+	Remote     bool                 `json:"remote"`
+	PrevStatus string               `json:"prevStatus,omitempty"`
+	Ctx        *RPCCrossTransaction `json:"ctx"`
+}
+
+// OrderFilter selects the order events of a subscriber, unset fields match any
+// order. Purpose is the chain the order is taken on, Owner the maker and Taker
+// the designated taker of the order.
+type OrderFilter struct {
+	Purpose *hexutil.Uint `json:"purpose"`
+	Owner   string        `json:"owner"`
+	Taker   string        `json:"taker"`
+}
+
+func (f *OrderFilter) match(ctx *core.CrossTransactionWithSignatures) bool {
+	if f == nil {
+		return true
+	}
+	if f.Purpose != nil && uint8(*f.Purpose) != ctx.Data.Purpose {
+		return false
+	}
+	if f.Owner != "" && !strings.EqualFold(f.Owner, ctx.Data.From) {
+		return false
+	}
+	if f.Taker != "" && !strings.EqualFold(f.Taker, ctx.Data.To) {
+		return false
+	}
+	return true
+}
+
+// orderSubs are the subscribers of the order events.
+type orderSubs struct {
+	mu   sync.RWMutex
+	subs map[rpc.ID]*orderSub
+}
+
+type orderSub struct {
+	filter *OrderFilter
+	ch     chan *RPCOrderEvent
+}
+
+// orderEvent returns the event of a store change, nil if it is not published.
+// Remote orders collecting their signature quorum are not published.
+func orderEvent(chain uint8, remote bool, change db.Change) *RPCOrderEvent {
+	ctx := change.Ctx
+	if remote && (ctx.Status == core.CtxStatusPending || change.Removed && change.From == core.CtxStatusPending) {
+		return nil
+	}
+	ev := &RPCOrderEvent{Chain: hexutil.Uint(chain), Remote: remote, Ctx: newRPCCrossTransaction(ctx)}
+	if !change.Created {
+		ev.PrevStatus = change.From.String()
+	}
+	switch {
+	case change.Removed:
+		ev.Type = OrderRemoved
+	case change.Created && !remote:
+		ev.Type = OrderNew
+	case remote && ctx.Status == core.CtxStatusWaiting && (change.Created || change.From == core.CtxStatusPending):
+		ev.Type = OrderNew
+	case remote && ctx.Status > core.CtxStatusIllegal && change.From <= core.CtxStatusIllegal:
+		ev.Type = OrderTaken
+	case !remote && ctx.Status == core.CtxStatusFinished:
+		ev.Type = OrderFinished
+	default:
+		ev.Type = OrderStatus
+	}
+	return ev
+}
+
+// publish returns the store watcher of the chain, the changes are queued to
+// the subscribers without blocking the store.
+func (s *CrossQueryApi) publish(chain uint8, remote bool) func(db.Change) {
+	return func(change db.Change) {
+		ev := orderEvent(chain, remote, change)
+		if ev == nil {
+			return
+		}
+		s.orders.mu.RLock()
+		defer s.orders.mu.RUnlock()
+		for id, sub := range s.orders.subs {
+			if !sub.filter.match(change.Ctx) {
+				continue
+			}
+			select {
+			case sub.ch <- ev:
+			default:
+				log.Warn("drop order event of slow subscriber", "id", id, "ctx", ev.Ctx.CTxId.String(), "type", ev.Type)
+			}
+		}
+	}
+}
+
+// subscribe adds a subscriber of the order events matching filter, the events
+// are delivered until unsubscribe is called.
+func (s *CrossQueryApi) subscribe(filter *OrderFilter) (events <-chan *RPCOrderEvent, unsubscribe func()) {
+	id := rpc.NewID()
+	sub := &orderSub{filter: filter, ch: make(chan *RPCOrderEvent, orderBuffer)}
+	s.orders.mu.Lock()
+	s.orders.subs[id] = sub
+	s.orders.mu.Unlock()
+	return sub.ch, func() {
+		s.orders.mu.Lock()
+		delete(s.orders.subs, id)
+		s.orders.mu.Unlock()
+	}
+}
+
+// Orders pushes the new, taken, finished and removed orders and the status
+// changes of the orders matching filter, it is subscribed by
+// cross_subscribe("orders", filter) over websocket.
+func (s *CrossQueryApi) Orders(ctx context.Context, filter *OrderFilter) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+	events, unsubscribe := s.subscribe(filter)
+
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, ev)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simplechain-org/crosshub/core"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+func subscribeOrders(t *testing.T, api *CrossQueryApi, filter *OrderFilter) chan *RPCOrderEvent {
+	server := rpc.NewServer()
+	if err := server.RegisterName("cross", api); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	client, err := rpc.DialWebsocket(context.Background(), "ws"+strings.TrimPrefix(httpServer.URL, "http"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan *RPCOrderEvent, 16)
+	sub, err := client.Subscribe(context.Background(), "cross", events, "orders", filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		sub.Unsubscribe()
+		client.Close()
+		httpServer.Close()
+		server.Stop()
+	})
+	return events
+}
+
+func nextOrder(t *testing.T, events chan *RPCOrderEvent) *RPCOrderEvent {
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no order event")
+		return nil
+	}
+}
+
+func TestCrossQueryApi_Orders(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	events := subscribeOrders(t, api, nil)
+
+	// remote orders are published once their signature quorum is reached
+	order := writeCtx(t, remote, 1, owner, "", 5, 2, 100, core.CtxStatusPending)
+	if err := remote.SetStatus(order.ID(), core.CtxStatusWaiting, 0, core.Normal); err != nil {
+		t.Fatal(err)
+	}
+	if ev := nextOrder(t, events); ev.Type != OrderNew || !ev.Remote || ev.Ctx.CTxId != order.ID() || ev.PrevStatus != "pending" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if err := remote.SetStatus(order.ID(), core.CtxStatusExecuting, 1, core.Normal); err != nil {
+		t.Fatal(err)
+	}
+	if ev := nextOrder(t, events); ev.Type != OrderTaken || ev.Ctx.Status != "executing" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	made := writeCtx(t, local, 2, owner, "", 2, 5, 100, core.CtxStatusExecuted)
+	if ev := nextOrder(t, events); ev.Type != OrderNew || ev.Remote || ev.Chain != 2 {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	for _, status := range []core.CtxStatus{core.CtxStatusFinishing, core.CtxStatusFinished} {
+		if err := local.SetStatus(made.ID(), status, 2, core.Normal); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ev := nextOrder(t, events); ev.Type != OrderStatus || ev.Ctx.Status != "finishing" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if ev := nextOrder(t, events); ev.Type != OrderFinished {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	if err := remote.Deletes([]common.Hash{order.ID()}); err != nil {
+		t.Fatal(err)
+	}
+	if ev := nextOrder(t, events); ev.Type != OrderRemoved || ev.PrevStatus != "executing" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+}
+
+func TestCrossQueryApi_OrdersFilter(t *testing.T) {
+	api, local, remote := newTestApi(t)
+	purpose := hexutil.Uint(2)
+	events := subscribeOrders(t, api, &OrderFilter{Purpose: &purpose, Taker: taker})
+
+	writeCtx(t, local, 1, owner, taker, 2, 5, 100, core.CtxStatusWaiting)
+	writeCtx(t, remote, 2, owner, "", 5, 2, 100, core.CtxStatusWaiting)
+	// addresses are matched ignoring the case
+	designated := writeCtx(t, remote, 3, owner, "0x"+strings.ToUpper(taker[2:]), 5, 2, 100, core.CtxStatusWaiting)
+
+	if ev := nextOrder(t, events); ev.Ctx.CTxId != designated.ID() {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event: %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/simplechain-org/go-simplechain/log"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+// errLimitedCode is the json-rpc error code of the calls over the rate limit,
+// the "limit exceeded" code of EIP-1474.
+const errLimitedCode = -32005
+
+// WebsocketHandler serves the json-rpc api of server over websocket to the
+// credentials granted role. The upgrade request is checked by Handler, then
+// every message is checked, rate limited and written to the audit log with
+// the credential of the upgrade. Calls over the limit are answered with an
+// error, the connection is closed once the credential is no longer valid.
+func (a *Auth) WebsocketHandler(role Role, server *rpc.Server, allowedOrigins []string) http.Handler {
+	if a == nil {
+		return server.WebsocketHandler(allowedOrigins)
+	}
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin:     checkOrigin(allowedOrigins),
+	}
+	return a.Handler(role, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the token is verified by Handler already
+		token, _ := requestToken(r)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Debug("websocket upgrade failed", "err", err)
+			return
+		}
+		conn.SetReadLimit(maxAuditBody)
+		ws := &wsConn{auth: a, conn: conn, role: role, token: token, remote: r.RemoteAddr}
+		server.ServeCodec(rpc.NewFuncCodec(conn, ws.write, ws.read), 0)
+	}))
+}
+
+// checkOrigin accepts the websocket upgrades of allowedOrigins, "*" accepts
+// any and none accepts localhost like the rpc package. Requests without Origin
+// are not sent by browsers and are accepted.
+func checkOrigin(allowedOrigins []string) func(*http.Request) bool {
+	if len(allowedOrigins) == 0 {
+		allowedOrigins = []string{"http://localhost"}
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		for _, allowed := range allowedOrigins {
+			if allowed == "*" || strings.EqualFold(allowed, origin) {
+				return true
+			}
+		}
+		log.Warn("rejected websocket connection", "origin", origin)
+		return false
+	}
+}
+
+// wsConn reads the json-rpc messages of a websocket connection admitted by
+// the credential of its token.
+type wsConn struct {
+	auth   *Auth
+	conn   *websocket.Conn
+	role   Role
+	token  string
+	remote string
+	mu     sync.Mutex // one writer at a time, the codec and the limit errors
+}
+
+func (c *wsConn) write(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(v)
+}
+
+// read reads the next message admitted for the credential into v, messages
+// over the rate limit are answered here and skipped.
+func (c *wsConn) read(v interface{}) error {
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		calls, batch := parseCalls(msg)
+		// jwts are verified again, they may expire while connected
+		cred, err := c.auth.lookup(c.token)
+		err = c.auth.admit("ws", c.role, cred, err, "remote", c.remote, "methods", strings.Join(callMethods(calls), ","))
+		var limited *limitError
+		if errors.As(err, &limited) {
+			if err := c.reject(calls, batch, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(msg, v)
+	}
+}
+
+// reject answers the calls of a message with err.
+func (c *wsConn) reject(calls []rpcCall, batch bool, err error) error {
+	type rpcError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	type response struct {
+		Version string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Error   rpcError        `json:"error"`
+	}
+	var responses []response
+	for _, call := range calls {
+		// notifications are not answered
+		if len(call.ID) > 0 {
+			responses = append(responses, response{"2.0", call.ID, rpcError{errLimitedCode, err.Error()}})
+		}
+	}
+	switch {
+	case len(responses) == 0:
+		return nil
+	case batch:
+		return c.write(responses)
+	}
+	return c.write(responses[0])
+}
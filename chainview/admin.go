@@ -0,0 +1,117 @@
+package chainview
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/simplechain-org/crosshub/core"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+var errViewerStopped = errors.New("viewer stopped")
+
+// do runs fn on the scan loop, so it does not interleave with a scan, and
+// returns its error.
+func (this *Viewer) do(fn func() error) error {
+	errc := make(chan error, 1)
+	select {
+	case this.adminCh <- func() { errc <- fn() }:
+	case <-this.ctx.Done():
+		return errViewerStopped
+	}
+	select {
+	case err := <-errc:
+		return err
+	case <-this.ctx.Done():
+		return errViewerStopped
+	}
+}
+
+// Rescan restarts the scan from block number from, below the scan height. The
+// ctxs of the rescanned blocks are written again and reorgs are checked from
+// there.
+func (this *Viewer) Rescan(from uint64) error {
+	return this.do(func() error {
+		height := this.Height()
+		if from >= height {
+			return fmt.Errorf("rescan from %d: not below the scan height %d", from, height)
+		}
+		if err := this.LocalStore.DeleteBlockRecords(from, height); err != nil {
+			return err
+		}
+		if err := this.LocalStore.Set("currentHeight", from); err != nil {
+			return err
+		}
+		atomic.StoreUint64(&this.currentHeight, from)
+		log.Warn("rescan", "purpose", this.chain.Purpose, "from", from, "height", height)
+		this.wake()
+		return nil
+	})
+}
+
+// ReloadAnchors reads the anchor sets of all remote chains from the contract
+// at the chain head.
+func (this *Viewer) ReloadAnchors() error {
+	return this.do(func() error {
+		for _, remote := range this.registry.Remotes(this.chain.Purpose) {
+			if err := this.getAnchors(remote.Purpose, nil); err != nil {
+				return fmt.Errorf("anchors of %d: %w", remote.Purpose, err)
+			}
+		}
+		return nil
+	})
+}
+
+// DropRemote removes the order made on the remote chain origin if it is not
+// taken yet.
+func (this *Viewer) DropRemote(origin uint8, id common.Hash) error {
+	store, err := this.remoteStore(origin)
+	if err != nil {
+		return err
+	}
+	return this.do(func() error {
+		cws, err := store.Read(id)
+		if err != nil {
+			return err
+		}
+		if cws.Status > core.CtxStatusIllegal {
+			return fmt.Errorf("drop remote ctx %s: %s", id.String(), cws.Status)
+		}
+		if err := store.Deletes([]common.Hash{id}); err != nil {
+			return err
+		}
+		this.pendingMu.Lock()
+		delete(this.pending, id)
+		this.pendingMu.Unlock()
+		log.Warn("drop remote ctx", "id", id.String(), "origin", origin, "status", cws.Status)
+		return nil
+	})
+}
+
+// Rebroadcast signs the local order id again and sends it to the other hubs,
+// for orders the remote chains missed. Orders taken already are not sent.
+func (this *Viewer) Rebroadcast(id common.Hash) error {
+	return this.do(func() error {
+		cws, err := this.LocalStore.Read(id)
+		if err != nil {
+			return err
+		}
+		if cws.Status != core.CtxStatusWaiting {
+			return fmt.Errorf("rebroadcast ctx %s: %s", id.String(), cws.Status)
+		}
+		ctx, err := this.signCtx(cws.CrossTransaction())
+		if err != nil {
+			return err
+		}
+		select {
+		case this.eventCh <- ctx:
+		case <-this.ctx.Done():
+			return errViewerStopped
+		}
+		log.Info("rebroadcast ctx", "id", id.String(), "purpose", ctx.Data.Purpose)
+		return nil
+	})
+}
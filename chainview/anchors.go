@@ -0,0 +1,134 @@
+package chainview
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/simplechain-org/crosshub/core"
+
+	"github.com/simplechain-org/go-simplechain/accounts/abi/bind"
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+// maxAnchorChanges bounds the anchor set changes kept for the api
+const maxAnchorChanges = 128
+
+// GetAnchors reloads the anchor sets of all remote chains at the chain head.
+// The remote chains are read from the registry, Anchors is written by the
+// scan loop.
+func (this *Viewer) GetAnchors() {
+	for _, remote := range this.registry.Remotes(this.chain.Purpose) {
+		if err := this.getAnchors(remote.Purpose, nil); err != nil {
+			log.Error("getAnchors", "purpose", remote.Purpose, "err", err)
+		}
+	}
+}
+
+// getAnchors reloads the anchor set of the remote chain purpose at block
+// number, the head if number is nil.
+func (this *Viewer) getAnchors(purpose uint8, number *big.Int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	if number == nil {
+		header, err := this.SimpleClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		number = header.Number
+	}
+	anchors, signConfirmCount, err := this.contract.GetAnchors(&bind.CallOpts{Context: ctx, BlockNumber: number}, purpose)
+	if err != nil {
+		return err
+	}
+	this.setAnchors(&core.AnchorSet{
+		Purpose:          purpose,
+		Anchors:          anchors,
+		SignConfirmCount: signConfirmCount,
+		Number:           number.Uint64(),
+	})
+	return nil
+}
+
+// setAnchors replaces the anchor set of a remote chain, sets read at a lower
+// block than the current one are ignored.
+func (this *Viewer) setAnchors(set *core.AnchorSet) {
+	this.anchorMu.Lock()
+	defer this.anchorMu.Unlock()
+	if old, ok := this.anchorSets[set.Purpose]; ok && set.Number < old.Number {
+		return
+	}
+
+	anchors := make(map[common.Address]struct{}, len(set.Anchors))
+	for _, anchor := range set.Anchors {
+		anchors[anchor] = struct{}{}
+	}
+	change := core.AnchorChange{
+		Purpose:          set.Purpose,
+		Number:           set.Number,
+		SignConfirmCount: set.SignConfirmCount,
+	}
+	for anchor := range anchors {
+		if _, ok := this.Anchors[set.Purpose][anchor]; !ok {
+			change.Added = append(change.Added, anchor)
+		}
+	}
+	for anchor := range this.Anchors[set.Purpose] {
+		if _, ok := anchors[anchor]; !ok {
+			change.Removed = append(change.Removed, anchor)
+		}
+	}
+	old, ok := this.anchorSets[set.Purpose]
+	this.Anchors[set.Purpose] = anchors
+	this.anchorSets[set.Purpose] = set
+	if ok && len(change.Added) == 0 && len(change.Removed) == 0 && old.SignConfirmCount == set.SignConfirmCount {
+		return
+	}
+
+	log.Info("anchor set changed", "purpose", set.Purpose, "number", set.Number, "anchors", len(anchors),
+		"added", len(change.Added), "removed", len(change.Removed), "signConfirmCount", set.SignConfirmCount)
+	this.anchorChanges = append(this.anchorChanges, change)
+	if len(this.anchorChanges) > maxAnchorChanges {
+		this.anchorChanges = this.anchorChanges[len(this.anchorChanges)-maxAnchorChanges:]
+	}
+}
+
+// isAnchor reports whether addr is an anchor of the remote chain purpose.
+func (this *Viewer) isAnchor(purpose uint8, addr common.Address) bool {
+	this.anchorMu.RLock()
+	defer this.anchorMu.RUnlock()
+	_, ok := this.Anchors[purpose][addr]
+	return ok
+}
+
+// SignConfirmCount returns the signatures required by the contract to take
+// the ctxs of the remote chain purpose.
+func (this *Viewer) SignConfirmCount(purpose uint8) uint8 {
+	this.anchorMu.RLock()
+	defer this.anchorMu.RUnlock()
+	if set, ok := this.anchorSets[purpose]; ok {
+		return set.SignConfirmCount
+	}
+	return 0
+}
+
+// AnchorSets returns the anchor sets of the remote chains ordered by purpose.
+func (this *Viewer) AnchorSets() []core.AnchorSet {
+	this.anchorMu.RLock()
+	defer this.anchorMu.RUnlock()
+	sets := make([]core.AnchorSet, 0, len(this.anchorSets))
+	for _, chain := range this.registry.Remotes(this.chain.Purpose) {
+		if set, ok := this.anchorSets[chain.Purpose]; ok {
+			sets = append(sets, *set)
+		}
+	}
+	return sets
+}
+
+// AnchorChanges returns the latest changes of the anchor sets, oldest first.
+func (this *Viewer) AnchorChanges() []core.AnchorChange {
+	this.anchorMu.RLock()
+	defer this.anchorMu.RUnlock()
+	return append([]core.AnchorChange(nil), this.anchorChanges...)
+}
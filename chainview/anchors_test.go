@@ -0,0 +1,78 @@
+package chainview
+
+import (
+	"context"
+	"testing"
+
+	"github.com/simplechain-org/crosshub/core"
+
+	"github.com/simplechain-org/go-simplechain/common"
+)
+
+func TestViewer_SetAnchors(t *testing.T) {
+	var (
+		a1 = common.HexToAddress("0x01")
+		a2 = common.HexToAddress("0x02")
+		a3 = common.HexToAddress("0x03")
+	)
+	v := &Viewer{
+		Anchors:    map[uint8]map[common.Address]struct{}{5: {}},
+		anchorSets: make(map[uint8]*core.AnchorSet),
+	}
+
+	v.setAnchors(&core.AnchorSet{Purpose: 5, Anchors: []common.Address{a1, a2}, SignConfirmCount: 2, Number: 10})
+	if !v.isAnchor(5, a1) || !v.isAnchor(5, a2) || v.isAnchor(5, a3) {
+		t.Fatalf("unexpected anchors after add: %v", v.Anchors[5])
+	}
+	if v.SignConfirmCount(5) != 2 {
+		t.Fatalf("signConfirmCount = %d, want 2", v.SignConfirmCount(5))
+	}
+
+	// a2 removed or disabled, a3 added
+	v.setAnchors(&core.AnchorSet{Purpose: 5, Anchors: []common.Address{a1, a3}, SignConfirmCount: 1, Number: 20})
+	if !v.isAnchor(5, a1) || v.isAnchor(5, a2) || !v.isAnchor(5, a3) {
+		t.Fatalf("unexpected anchors after remove: %v", v.Anchors[5])
+	}
+
+	// sets read at older blocks are ignored
+	v.setAnchors(&core.AnchorSet{Purpose: 5, Anchors: []common.Address{a2}, SignConfirmCount: 1, Number: 15})
+	if !v.isAnchor(5, a1) || v.isAnchor(5, a2) {
+		t.Fatalf("older set applied: %v", v.Anchors[5])
+	}
+
+	// unchanged sets are not recorded as changes
+	v.setAnchors(&core.AnchorSet{Purpose: 5, Anchors: []common.Address{a3, a1}, SignConfirmCount: 1, Number: 30})
+
+	changes := v.AnchorChanges()
+	if len(changes) != 2 {
+		t.Fatalf("changes = %d, want 2", len(changes))
+	}
+	if len(changes[1].Added) != 1 || changes[1].Added[0] != a3 || len(changes[1].Removed) != 1 || changes[1].Removed[0] != a2 {
+		t.Errorf("unexpected change: %+v", changes[1])
+	}
+	if v.anchorSets[5].Number != 30 {
+		t.Errorf("set number = %d, want 30", v.anchorSets[5].Number)
+	}
+}
+
+func TestViewer_ReloadAnchors(t *testing.T) {
+	// the reload runs on the scan loop, not on the caller with Anchors being
+	// written by the loop
+	ctx, cancel := context.WithCancel(context.Background())
+	v := &Viewer{ctx: ctx, cancel: cancel, adminCh: make(chan func())}
+	ran := make(chan struct{})
+	go func() {
+		// the loop takes the reload and stops before running it
+		<-v.adminCh
+		close(ran)
+		cancel()
+	}()
+	if err := v.ReloadAnchors(); err != errViewerStopped {
+		t.Fatalf("reload: %v", err)
+	}
+	select {
+	case <-ran:
+	default:
+		t.Fatal("reload not run by the scan loop")
+	}
+}
@@ -0,0 +1,114 @@
+package chainview
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/database"
+	"github.com/simplechain-org/crosshub/registry"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/log"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// scanBatch collects the ctx writes of a scanned block range, they are committed
+// in one transaction together with the scan cursor. Messages to peers are sent
+// only after the commit.
+type scanBatch struct {
+	tx      *bolt.Tx
+	local   *database.IndexDB
+	remotes map[uint8]*database.IndexDB
+	events  []interface{}
+	// anchors keeps the highest block changing the anchor set of each purpose
+	anchors map[uint8]uint64
+	// finished keeps the local ctxs finished in the range
+	finished []finishedCtx
+	// dropped keeps the local ctxs removed by a reorg, their submitter
+	// elections are dropped
+	dropped []common.Hash
+}
+
+func (this *Viewer) newBatch() (*scanBatch, error) {
+	tx, err := this.rootDB.Bolt.Begin(true)
+	if err != nil {
+		return nil, err
+	}
+	remotes := make(map[uint8]*database.IndexDB, len(this.RemoteStores))
+	for purpose, store := range this.RemoteStores {
+		remotes[purpose] = store.WithTransaction(tx)
+	}
+	return &scanBatch{
+		tx:      tx,
+		local:   this.LocalStore.WithTransaction(tx),
+		remotes: remotes,
+	}, nil
+}
+
+// remote returns the store of the orders made on the remote chain purpose.
+func (b *scanBatch) remote(purpose uint8) (*database.IndexDB, error) {
+	store, ok := b.remotes[purpose]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", registry.ErrUnknownChain, purpose)
+	}
+	return store, nil
+}
+
+// refreshAnchors queues a reload of the anchor set of purpose, changed at block number.
+func (b *scanBatch) refreshAnchors(purpose uint8, number uint64) {
+	if b.anchors == nil {
+		b.anchors = make(map[uint8]uint64)
+	}
+	if number >= b.anchors[purpose] {
+		b.anchors[purpose] = number
+	}
+}
+
+// finish queues the latency record of a local ctx finished at block number.
+func (b *scanBatch) finish(cws *core.CrossTransactionWithSignatures, number uint64) {
+	b.finished = append(b.finished, finishedCtx{cws: cws, number: number})
+}
+
+// send queues a message to peers until the batch is committed.
+func (b *scanBatch) send(ev interface{}) {
+	b.events = append(b.events, ev)
+}
+
+func (b *scanBatch) commit() error {
+	return b.tx.Commit()
+}
+
+// rollback discards the batch, it does nothing after a commit.
+func (b *scanBatch) rollback() {
+	b.tx.Rollback()
+}
+
+// flush reports the store changes, reloads the changed anchor sets, queues
+// the finish latencies, drops the elections of removed ctxs and sends the
+// queued messages of a committed batch to peers.
+func (this *Viewer) flush(b *scanBatch) {
+	b.local.Flush()
+	for _, store := range b.remotes {
+		store.Flush()
+	}
+	for purpose, number := range b.anchors {
+		if err := this.getAnchors(purpose, new(big.Int).SetUint64(number)); err != nil {
+			log.Error("getAnchors", "purpose", purpose, "number", number, "err", err)
+		}
+	}
+	b.anchors = nil
+	for _, finished := range b.finished {
+		this.queueLatency(finished)
+	}
+	b.finished = nil
+	for _, id := range b.dropped {
+		this.dropElection(id)
+	}
+	b.dropped = nil
+	for _, ev := range b.events {
+		this.eventCh <- ev
+	}
+	b.events = nil
+}
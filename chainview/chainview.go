@@ -1,21 +1,24 @@
 package chainview
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"github.com/asdine/storm/v3"
-	"github.com/simplechain-org/crosshub/api"
+	"github.com/simplechain-org/crosshub"
+	"github.com/simplechain-org/crosshub/contract/crossdemo"
 	"github.com/simplechain-org/crosshub/core"
 	"github.com/simplechain-org/crosshub/database"
-	"github.com/simplechain-org/crosshub/repo"
+	"github.com/simplechain-org/crosshub/registry"
+	"github.com/simplechain-org/crosshub/sender"
 	"github.com/simplechain-org/go-simplechain"
-	"github.com/simplechain-org/go-simplechain/cmd/utils"
 	"github.com/simplechain-org/go-simplechain/crypto"
 	"github.com/simplechain-org/go-simplechain/crypto/ecdsa"
 	"github.com/simplechain-org/go-simplechain/log"
 	"github.com/simplechain-org/go-simplechain/rpc"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/simplechain-org/go-simplechain/accounts/abi"
@@ -29,20 +32,37 @@ import (
 
 const (
 	DataDir = "crossData"
+
+	// maxScanRetries bounds the attempts to scan a block range in one tick
+	maxScanRetries = 5
+	// scanRetryBackoff is the wait before the first retry, doubled on each retry
+	scanRetryBackoff = 500 * time.Millisecond
 )
 
-var abiParsed abi.ABI
-var CrossAbi = "0x5b0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022616464726573732070617961626c65222c0a09090909226e616d65223a2022616e63686f72222c0a090909092274797065223a202261646472657373220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022726577617264222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a0909226e616d65223a2022616363756d756c61746552657761726473222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a2022636f6e7374727563746f72220a097d2c0a097b0a090922616e6f6e796d6f7573223a2066616c73652c0a090922696e70757473223a205b0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a2022616e63686f72222c0a090909092274797065223a202261646472657373220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022726577617264222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a0909226e616d65223a2022416363756d756c61746552657761726473222c0a09092274797065223a20226576656e74220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022616464726573735b5d222c0a09090909226e616d65223a20225f616e63686f7273222c0a090909092274797065223a2022616464726573735b5d220a0909097d0a09095d2c0a0909226e616d65223a2022616464416e63686f7273222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922616e6f6e796d6f7573223a2066616c73652c0a090922696e70757473223a205b0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a2022416464416e63686f7273222c0a09092274797065223a20226576656e74220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a20226d617856616c7565222c0a090909092274797065223a202275696e74323536220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a20227369676e436f6e6669726d436f756e74222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022616464726573735b5d222c0a09090909226e616d65223a20225f616e63686f7273222c0a090909092274797065223a2022616464726573735b5d220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022737472696e67222c0a09090909226e616d65223a2022726f75746572222c0a090909092274797065223a2022737472696e67220a0909097d0a09095d2c0a0909226e616d65223a2022636861696e5265676973746572222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a2022626f6f6c222c0a09090909226e616d65223a2022222c0a090909092274797065223a2022626f6f6c220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922636f6d706f6e656e7473223a205b0a09090909097b0a09090909090922696e7465726e616c54797065223a202262797465733332222c0a090909090909226e616d65223a202274784964222c0a0909090909092274797065223a202262797465733332220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202262797465733332222c0a090909090909226e616d65223a2022747848617368222c0a0909090909092274797065223a202262797465733332220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a2022737472696e67222c0a090909090909226e616d65223a202266726f6d222c0a0909090909092274797065223a2022737472696e67220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a2022737472696e67222c0a090909090909226e616d65223a2022746f222c0a0909090909092274797065223a2022737472696e67220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a2022616464726573732070617961626c65222c0a090909090909226e616d65223a202274616b6572222c0a0909090909092274797065223a202261646472657373220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202275696e7438222c0a090909090909226e616d65223a20226f726967696e222c0a0909090909092274797065223a202275696e7438220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202275696e7438222c0a090909090909226e616d65223a2022707572706f7365222c0a0909090909092274797065223a202275696e7438220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a20226279746573222c0a090909090909226e616d65223a202264617461222c0a0909090909092274797065223a20226279746573220a09090909097d0a090909095d2c0a0909090922696e7465726e616c54797065223a20227374727563742043726f73735374727563742e526563657074222c0a09090909226e616d65223a2022727478222c0a090909092274797065223a20227475706c65220a0909097d0a09095d2c0a0909226e616d65223a20226d616b657246696e697368222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a202270617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922616e6f6e796d6f7573223a2066616c73652c0a090922696e70757473223a205b0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202262797465733332222c0a09090909226e616d65223a202274784964222c0a090909092274797065223a202262797465733332220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a2022746f222c0a090909092274797065223a202261646472657373220a0909097d0a09095d2c0a0909226e616d65223a20224d616b657246696e697368222c0a09092274797065223a20226576656e74220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a20226465737456616c7565222c0a090909092274797065223a202275696e74323536220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022737472696e675b325d222c0a09090909226e616d65223a2022617267222c0a090909092274797065223a2022737472696e675b325d220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a20226279746573222c0a09090909226e616d65223a202264617461222c0a090909092274797065223a20226279746573220a0909097d0a09095d2c0a0909226e616d65223a20226d616b65725374617274222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a202270617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922616e6f6e796d6f7573223a2066616c73652c0a090922696e70757473223a205b0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202262797465733332222c0a09090909226e616d65223a202274784964222c0a090909092274797065223a202262797465733332220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a202276616c7565222c0a090909092274797065223a202275696e74323536220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a20226465737456616c7565222c0a090909092274797065223a202275696e74323536220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a2022737472696e67222c0a09090909226e616d65223a202266726f6d222c0a090909092274797065223a2022737472696e67220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a2022737472696e67222c0a09090909226e616d65223a2022746f222c0a090909092274797065223a2022737472696e67220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a20226279746573222c0a09090909226e616d65223a20227061796c6f6164222c0a090909092274797065223a20226279746573220a0909097d0a09095d2c0a0909226e616d65223a20224d616b65725478222c0a09092274797065223a20226576656e74220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022616464726573735b5d222c0a09090909226e616d65223a20225f616e63686f7273222c0a090909092274797065223a2022616464726573735b5d220a0909097d0a09095d2c0a0909226e616d65223a202272656d6f7665416e63686f7273222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922616e6f6e796d6f7573223a2066616c73652c0a090922696e70757473223a205b0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a202252656d6f7665416e63686f7273222c0a09092274797065223a20226576656e74220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a20225f616e63686f72222c0a090909092274797065223a202261646472657373220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022626f6f6c222c0a09090909226e616d65223a2022737461747573222c0a090909092274797065223a2022626f6f6c220a0909097d0a09095d2c0a0909226e616d65223a2022736574416e63686f72537461747573222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922616e6f6e796d6f7573223a2066616c73652c0a090922696e70757473223a205b0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a2022536574416e63686f72537461747573222c0a09092274797065223a20226576656e74220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a20226d617856616c7565222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a0909226e616d65223a20227365744d617856616c7565222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a20225f726577617264222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a0909226e616d65223a2022736574526577617264222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022636f756e74222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a20227365745369676e436f6e6669726d436f756e74222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922636f6d706f6e656e7473223a205b0a09090909097b0a09090909090922696e7465726e616c54797065223a202262797465733332222c0a090909090909226e616d65223a202274784964222c0a0909090909092274797065223a202262797465733332220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202262797465733332222c0a090909090909226e616d65223a2022747848617368222c0a0909090909092274797065223a202262797465733332220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202262797465733332222c0a090909090909226e616d65223a2022626c6f636b48617368222c0a0909090909092274797065223a202262797465733332220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202275696e74323536222c0a090909090909226e616d65223a202276616c7565222c0a0909090909092274797065223a202275696e74323536220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202275696e74323536222c0a090909090909226e616d65223a2022636861726765222c0a0909090909092274797065223a202275696e74323536220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a2022616464726573732070617961626c65222c0a090909090909226e616d65223a202266726f6d222c0a0909090909092274797065223a202261646472657373220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202261646472657373222c0a090909090909226e616d65223a2022746f222c0a0909090909092274797065223a202261646472657373220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202275696e7438222c0a090909090909226e616d65223a20226f726967696e222c0a0909090909092274797065223a202275696e7438220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202275696e7438222c0a090909090909226e616d65223a2022707572706f7365222c0a0909090909092274797065223a202275696e7438220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a20226279746573222c0a090909090909226e616d65223a20227061796c6f6164222c0a0909090909092274797065223a20226279746573220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a202275696e743235365b5d222c0a090909090909226e616d65223a202276222c0a0909090909092274797065223a202275696e743235365b5d220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a2022627974657333325b5d222c0a090909090909226e616d65223a202272222c0a0909090909092274797065223a2022627974657333325b5d220a09090909097d2c0a09090909097b0a09090909090922696e7465726e616c54797065223a2022627974657333325b5d222c0a090909090909226e616d65223a202273222c0a0909090909092274797065223a2022627974657333325b5d220a09090909097d0a090909095d2c0a0909090922696e7465726e616c54797065223a20227374727563742043726f73735374727563742e4f72646572222c0a09090909226e616d65223a2022637478222c0a090909092274797065223a20227475706c65220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022737472696e67222c0a09090909226e616d65223a2022746f222c0a090909092274797065223a2022737472696e67220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a20226279746573222c0a09090909226e616d65223a202264617461222c0a090909092274797065223a20226279746573220a0909097d0a09095d2c0a0909226e616d65223a202274616b6572222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a202270617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922616e6f6e796d6f7573223a2066616c73652c0a090922696e70757473223a205b0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202262797465733332222c0a09090909226e616d65223a202274784964222c0a090909092274797065223a202262797465733332220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a202266726f6d222c0a090909092274797065223a202261646472657373220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a2022746f222c0a090909092274797065223a202261646472657373220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a2022737472696e67222c0a09090909226e616d65223a202274616b6572222c0a090909092274797065223a2022737472696e67220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a20226279746573222c0a09090909226e616d65223a20227061796c6f6164222c0a090909092274797065223a20226279746573220a0909097d0a09095d2c0a0909226e616d65223a202254616b65725478222c0a09092274797065223a20226576656e74220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022737472696e67222c0a09090909226e616d65223a20225f726f75746572222c0a090909092274797065223a2022737472696e67220a0909097d0a09095d2c0a0909226e616d65223a2022757064617465526f75746572222c0a0909226f757470757473223a205b5d2c0a09092273746174654d75746162696c697479223a20226e6f6e70617961626c65222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922616e6f6e796d6f7573223a2066616c73652c0a090922696e70757473223a205b0a0909097b0a0909090922696e6465786564223a2066616c73652c0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a2022557064617465526f75746572222c0a09092274797065223a20226576656e74220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e743634222c0a09090909226e616d65223a20226e222c0a090909092274797065223a202275696e743634220a0909097d0a09095d2c0a0909226e616d65223a2022626974436f756e74222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e743634222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e743634220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202270757265222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b5d2c0a0909226e616d65223a2022636861696e4964222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a20226964222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202270757265222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a202263726f7373436861696e73222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a20227369676e436f6e6669726d436f756e74222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a20226d617856616c7565222c0a090909092274797065223a202275696e74323536220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e743634222c0a09090909226e616d65223a2022616e63686f7273506f736974696f6e426974222c0a090909092274797065223a202275696e743634220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e743634222c0a09090909226e616d65223a202264656c73506f736974696f6e426974222c0a090909092274797065223a202275696e743634220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a202264656c4964222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022726577617264222c0a090909092274797065223a202275696e74323536220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022746f74616c526577617264222c0a090909092274797065223a202275696e74323536220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a2022737472696e67222c0a09090909226e616d65223a2022726f75746572222c0a090909092274797065223a2022737472696e67220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a2022676574416e63686f7273222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a2022616464726573735b5d222c0a09090909226e616d65223a20225f616e63686f7273222c0a090909092274797065223a2022616464726573735b5d220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a20225f616e63686f72222c0a090909092274797065223a202261646472657373220a0909097d0a09095d2c0a0909226e616d65223a2022676574416e63686f72576f726b436f756e74222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e74323536220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a2022676574436861696e526577617264222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a20225f616e63686f72222c0a090909092274797065223a202261646472657373220a0909097d0a09095d2c0a0909226e616d65223a202267657444656c416e63686f725369676e436f756e74222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202262797465733332222c0a09090909226e616d65223a202274784964222c0a090909092274797065223a202262797465733332220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a20226765744d616b65725478222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a20226765744d617856616c7565222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a2022676574526f75746572222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a2022737472696e67222c0a09090909226e616d65223a2022222c0a090909092274797065223a2022737472696e67220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202262797465733332222c0a09090909226e616d65223a202274784964222c0a090909092274797065223a202262797465733332220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a20225f66726f6d222c0a090909092274797065223a202261646472657373220a0909097d2c0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a202267657454616b65725478222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e7438222c0a09090909226e616d65223a2022707572706f7365222c0a090909092274797065223a202275696e7438220a0909097d0a09095d2c0a0909226e616d65223a2022676574546f74616c526577617264222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a2022222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b5d2c0a0909226e616d65223a20226c697374222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202275696e74323536222c0a09090909226e616d65223a20226c6c222c0a090909092274797065223a202275696e74323536220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202270757265222c0a09092274797065223a202266756e6374696f6e220a097d2c0a097b0a090922696e70757473223a205b5d2c0a0909226e616d65223a20226f776e6572222c0a0909226f757470757473223a205b0a0909097b0a0909090922696e7465726e616c54797065223a202261646472657373222c0a09090909226e616d65223a2022222c0a090909092274797065223a202261646472657373220a0909097d0a09095d2c0a09092273746174654d75746162696c697479223a202276696577222c0a09092274797065223a202266756e6374696f6e220a097d0a5d"
+// crossTopics keeps the topics of the cross contract events handled by the viewer
+var crossTopics struct {
+	makerTx, takerTx, makerFinish                  common.Hash
+	addAnchors, removeAnchors, setAnchorStatus common.Hash
+}
 
 func init()  {
-	data, err := hexutil.Decode(CrossAbi)
+	parsed, err := abi.JSON(strings.NewReader(crossdemo.CrossDemoABI))
 	if err != nil {
-		log.Error("init","err",err)
+		panic(fmt.Errorf("crossdemo abi: %w", err))
 	}
-	abiParsed, err = abi.JSON(bytes.NewReader(data))
-	if err != nil {
-		log.Error("abi.JSON","err",err)
+	topic := func(name string) common.Hash {
+		event, ok := parsed.Events[name]
+		if !ok {
+			panic(fmt.Errorf("crossdemo abi: no event %s", name))
+		}
+		return event.ID()
 	}
+	crossTopics.makerTx = topic("MakerTx")
+	crossTopics.takerTx = topic("TakerTx")
+	crossTopics.makerFinish = topic("MakerFinish")
+	crossTopics.addAnchors = topic("AddAnchors")
+	crossTopics.removeAnchors = topic("RemoveAnchors")
+	crossTopics.setAnchorStatus = topic("SetAnchorStatus")
 }
 
 type Viewer struct {
@@ -50,67 +70,136 @@ type Viewer struct {
 	SimpleClient 	*ethclient.Client
 	Address      	string
 	currentHeight   uint64
+	// head is the chain head seen by the last scan
+	head            uint64
 	eventCh        chan<- interface{}
 	messageCh      <-chan interface{}
 
 	PrivateKey  *ecdsa.PrivateKey
-	RemoteStore *database.IndexDB
-	LocalStore  *database.IndexDB
-	Anchors     map[common.Address]struct{}
+	contract    *crossdemo.CrossDemo
+	// RemoteStores keeps the orders of each remote chain, keyed by its purpose
+	RemoteStores map[uint8]*database.IndexDB
+	LocalStore   *database.IndexDB
+	rootDB       *storm.DB
+	// Anchors keeps the anchors of each remote chain, keyed by its purpose
+	Anchors map[uint8]map[common.Address]struct{}
+	// anchorSets keeps the anchor set read from the contract for each remote chain
+	anchorSets    map[uint8]*core.AnchorSet
+	anchorChanges []core.AnchorChange
+	anchorMu      sync.RWMutex
+
+	chain    *registry.Chain
+	registry *registry.Registry
+
+	// wakeCh triggers a scan before the next poll, used by the log subscription
+	wakeCh     chan struct{}
+	subscriber *subscriber
+	// adminCh runs the admin operations on the scan loop
+	adminCh chan func()
+	// sender submits the makerFinish transactions of the hub account
+	sender *sender.Sender
+	// elections keeps the submitter elections of the local anchor by ctx id
+	elections     map[common.Hash]*election
+	electionOrder []common.Hash
+	lastHeard     map[common.Address]time.Time
+	takeover      time.Duration
+	electMu       sync.Mutex
+	// pending keeps the remote ctxs collecting their signature quorum
+	pending   map[common.Hash]pendingCtx
+	pendingMu sync.Mutex
+	// latencyCh queues the local ctxs finished by the scan for their latency record
+	latencyCh chan finishedCtx
 
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
-func New(repo *repo.Repo,eventCh chan<- interface{}, messageCh <-chan interface{}) (*Viewer,error) {
+// New creates the viewer of the simplechain chain, orders of every other chain in
+// the registry are stored as remote orders.
+func New(reg *registry.Registry, chain *registry.Chain, key *ecdsa.PrivateKey, eventCh chan<- interface{}, messageCh <-chan interface{}) (*Viewer,error) {
 	ctx, cancel := context.WithCancel(context.Background())
-	//log.Info("New","addr",repo.Config.RpcUrl)
-	client, err := rpc.DialContext(ctx,fmt.Sprintf("http://%s:%s", repo.Config.RpcIp, repo.Config.RpcPort))
+	client, err := rpc.DialContext(ctx, chain.Endpoint)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
-
-	rootDB,err := storm.Open(filepath.Join(repo.Config.DataDir,DataDir))
+	simpleClient := ethclient.NewClient(client)
+	contract, err := crossdemo.NewCrossDemo(chain.Contract, simpleClient)
 	if err != nil {
-		log.Error("NewIndexDB","err",err)
-	}
-	remoteDb := database.NewIndexDB(big.NewInt(5), rootDB,4096)
-	localDb := database.NewIndexDB(big.NewInt(2), rootDB,4096)
-	crossApi := api.NewPublicCrossQueryApi(remoteDb,localDb)
-	var  queryApi api.CrossApi = crossApi
-	rpcAPI := []rpc.API{
-		{
-			Namespace: "cross",
-			Public:    true,
-			Service:   queryApi,
-			Version:   "1.0",
-		},
-	}
-	httpEndpoint := fmt.Sprintf("0.0.0.0:%d", repo.Config.Grpc)
-	_, _, err = rpc.StartHTTPEndpoint(httpEndpoint, rpcAPI, []string{"cross"}, repo.Config.AllowedOrigins, []string{"*"}, rpc.DefaultHTTPTimeouts)
-	if err != nil {
-		utils.Fatalf("Could not start RPC api: %v", err)
+		cancel()
+		return nil, err
 	}
-	extapiURL := fmt.Sprintf("http://%s", httpEndpoint)
-	log.Info("HTTP endpoint opened", "url", extapiURL)
 
+	rootDB,err := storm.Open(filepath.Join(chain.DataDir,DataDir))
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("open %s: %w", chain.DataDir, err)
+	}
+	remoteDbs := make(map[uint8]*database.IndexDB)
+	anchors := make(map[uint8]map[common.Address]struct{})
+	for _, remote := range reg.Remotes(chain.Purpose) {
+		remoteDbs[remote.Purpose] = database.NewIndexDB(remote.ChainId, rootDB, 4096)
+		anchors[remote.Purpose] = make(map[common.Address]struct{})
+	}
+	localDb := database.NewIndexDB(chain.ChainId, rootDB,4096)
 
+	checkpoint := localDb.Get("currentHeight")
+	currentHeight := checkpoint
+	if currentHeight < chain.StartHeight {
+		currentHeight = chain.StartHeight
+	}
+	log.Info("resume chain scan", "purpose", chain.Purpose, "height", currentHeight,
+		"checkpoint", checkpoint, "startHeight", chain.StartHeight)
 
-	return &Viewer{
+	v := &Viewer{
 		Client:        client,
-		SimpleClient:  ethclient.NewClient(client),
-		Address:       repo.Config.Contract,
-		currentHeight: localDb.Get("currentHeight"),
+		SimpleClient:  simpleClient,
+		Address:       chain.Contract.Hex(),
+		currentHeight: currentHeight,
 		//currentHeight: 35800,
 		eventCh:       eventCh,
 		messageCh:     messageCh,
-		PrivateKey:    repo.Key.PrivKey.(*ecdsa.PrivateKey),
-		RemoteStore:   remoteDb,
+		PrivateKey:    key,
+		contract:      contract,
+		RemoteStores:  remoteDbs,
 		LocalStore:    localDb,
-		Anchors:       make(map[common.Address]struct{}),
+		rootDB:        rootDB,
+		Anchors:       anchors,
+		anchorSets:    make(map[uint8]*core.AnchorSet),
+		chain:         chain,
+		registry:      reg,
 		ctx:           ctx,
 		cancel:        cancel,
-	},nil
+		wakeCh:        make(chan struct{}, 1),
+		adminCh:       make(chan func()),
+		elections:     make(map[common.Hash]*election),
+		lastHeard:     make(map[common.Address]time.Time),
+		pending:       make(map[common.Hash]pendingCtx),
+		latencyCh:     make(chan finishedCtx, latencyQueue),
+		takeover:      time.Duration(chain.Sender.Takeover) * time.Second,
+	}
+	if v.takeover == 0 {
+		v.takeover = defaultTakeover
+	}
+	v.registerMetrics()
+	v.sender = sender.New(simpleClient, rootDB.From("sender"), chain.TxSigner(), crypto.PubkeyToAddress(key.K.PublicKey),
+		func(hash []byte) ([]byte, error) {
+			return crypto.Sign(hash, key.K)
+		}, chain.Sender, v.makerFinished)
+	if chain.Subscribe != "" {
+		v.subscriber = newSubscriber(chain.Subscribe, chain.Contract, chain.Confirmations, v.wake)
+	}
+	return v, nil
+}
+
+// Chain returns the chain served by the viewer.
+func (this *Viewer) Chain() *registry.Chain {
+	return this.chain
+}
+
+// Height returns the next block to scan, the blocks below are scanned.
+func (this *Viewer) Height() uint64 {
+	return atomic.LoadUint64(&this.currentHeight)
 }
 
 func (this *Viewer)Start() error {
@@ -119,23 +208,29 @@ func (this *Viewer)Start() error {
 	//	common.HexToHash("0xa5b27e78847ffd1415c89654d8dbab8148472d452dd5ae311be54bf181b270a4"),
 	//	common.HexToHash("0x57b38851fb67956ce3a5420ed050a1e4eb7e70b31bd103202976b23546326f74"),
 	//})
+	this.loadPending()
+	this.sender.Start()
 	go this.loop()
+	go this.observeLatencies()
+	if this.subscriber != nil {
+		go this.subscriber.run(this.ctx)
+	}
 	return nil
 }
 
+// Stop stops the scan, the height is checkpointed by every scan batch already.
 func (this *Viewer)Stop() error {
-	err := this.LocalStore.Set("currentHeight",this.currentHeight)
-	if err != nil {
-		log.Error("Stop","err",err)
-	}
-	log.Info("Stop","height",this.currentHeight)
+	log.Info("Stop","height",this.Height())
 	this.cancel()
+	this.sender.Stop()
 	return nil
 }
 
 func (this *Viewer)loop()  {
 	var eventTicker = time.NewTicker(time.Second*5)
 	var anchorTicker = time.NewTicker(time.Hour)
+	var electionTicker = time.NewTicker(time.Second*5)
+	defer electionTicker.Stop()
 	defer eventTicker.Stop()
 	for {
 		select {
@@ -143,69 +238,231 @@ func (this *Viewer)loop()  {
 			return
 		case <-eventTicker.C:
 			this.GetEvents()
+		case <-this.wakeCh:
+			// keep scanning until caught up, other events are served in between
+			if this.GetEvents() {
+				this.wake()
+			}
 		case <-anchorTicker.C:
 			this.GetAnchors()
+		case fn := <-this.adminCh:
+			fn()
+		case <-electionTicker.C:
+			this.checkElections()
+			this.checkPending()
 		case ev := <-this.messageCh:
 			if ctm,ok := ev.(*core.CrossTransaction);ok {
-				//store
-				from,err := core.CtxSender(core.MakeCtxSigner(big.NewInt(11)),ctm)
+				if ctm.Data.Purpose != this.chain.Purpose {
+					log.Info("discard ctx of other chain", "id", ctm.ID().String(), "purpose", ctm.Data.Purpose)
+					continue
+				}
+				// the anchors of the origin sign the ctx in the form verified by
+				// the contract of this chain, their signatures are stored as they are
+				from,err := this.chain.CtxSigner().SimpleSender(ctm)
 				if err != nil {
-					log.Info("CtxSender","err",err)
+					log.Info("SimpleSender","id",ctm.ID().String(),"err",err)
+					continue
 				}
-				log.Info("handler sign msg","msg",ctm,"from",from.String(),"anchors", len(this.Anchors))
-				if _,ok := this.Anchors[from];ok {
-					//TODO 改签
-					signHash := func(hash []byte) ([]byte, error) {
-						return  crypto.Sign(hash,this.PrivateKey.K)
-					}
-					ctms,err :=  core.SignSimpleCtx(ctm,core.MakeCtxSigner(big.NewInt(2)),signHash)
-					if err != nil {
-						log.Info("SignCtx","err",err)
-					}
-					log.Info("anchors","ok",ok)
-					err = this.RemoteStore.Write(ctms)
-					if err != nil {
+				ok := this.isAnchor(ctm.Data.Origin, from)
+				log.Info("handler sign msg","msg",ctm,"from",from.String(),"anchor",ok)
+				if ok {
+					this.heard(from)
+					if err := this.storeRemoteSignature(ctm); err != nil {
 						log.Error("write","err",err)
 					}
-				} else {
-					log.Info("anchors","ok",ok)
 				}
 			}
 			if rtm,ok := ev.(*core.ReceptTransaction);ok {
-				from,err := core.RtxSender(core.MakeRtxSigner(big.NewInt(11)),rtm)
+				from,err := core.RtxSender(this.registry.HubRtxSigner(),rtm)
 				if err != nil {
 					log.Info("CtxSender","err",err)
 				}
-				if _,ok := this.Anchors[from];ok {
-					tx,err := this.createTransaction(rtm)
-					if err != nil {
-						log.Info("createTransaction", "err", err)
-					}
-					ctx, _ := context.WithTimeout(context.Background(), time.Second*30)
-					err = this.SimpleClient.SendTransaction(ctx,tx)
-					if err != nil {
-						log.Info("SendTransaction", "err", err)
+				if this.isAnchor(rtm.Data.Origin, from) {
+					this.heard(from)
+					if err := this.advanceStatus(this.LocalStore, rtm.ID(), core.CtxStatusExecuted, 0); err != nil {
+						log.Info("advanceStatus", "id", rtm.ID().String(), "err", err)
 					}
+					// the first signConfirmCount ranked anchors submit, the others take over on timeout
+					this.elect(rtm)
 				}
 				log.Info("rtm","id",rtm.ID().String())
 			}
+			if removed,ok := ev.(*core.RemovedCtx);ok {
+				from,err := this.chain.CtxSigner().SimpleSender(removed.Ctx)
+				if err != nil {
+					log.Info("SimpleSender","id",removed.Ctx.ID().String(),"err",err)
+					continue
+				}
+				if this.isAnchor(removed.Ctx.Data.Origin, from) {
+					this.heard(from)
+					if err := this.removeRemoteSignature(removed.Ctx, from); err != nil {
+						log.Error("removeRemoteSignature","id",removed.Ctx.ID().String(),"err",err)
+					}
+				}
+			}
+			if reverted,ok := ev.(*core.RevertedRtx);ok {
+				from,err := core.RtxSender(this.registry.HubRtxSigner(),reverted.Rtx)
+				if err != nil {
+					log.Info("RtxSender","err",err)
+					continue
+				}
+				if this.isAnchor(reverted.Rtx.Data.Origin, from) {
+					this.heard(from)
+					if err := this.revertLocalTaker(reverted.Rtx.ID()); err != nil {
+						log.Info("revertLocalTaker","id",reverted.Rtx.ID().String(),"err",err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// wake schedules a scan without waiting for the next poll.
+func (this *Viewer) wake() {
+	select {
+	case this.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// signCtx signs the local order ctx for the contract of its purpose chain, as
+// an anchor of this chain.
+func (this *Viewer) signCtx(ctx *core.CrossTransaction) (*core.CrossTransaction, error) {
+	purpose, err := this.registry.Chain(ctx.Data.Purpose)
+	if err != nil {
+		return nil, err
+	}
+	return core.SignSimpleCtx(ctx, purpose.CtxSigner(), func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, this.PrivateKey.K)
+	})
+}
+
+// storeRemoteSignature adds the signature of ctx to the remote ctx it belongs to,
+// the ctx is created pending if it is not stored yet. It is published once the
+// signature quorum is reached.
+func (this *Viewer) storeRemoteSignature(ctx *core.CrossTransaction) error {
+	store, err := this.remoteStore(ctx.Data.Origin)
+	if err != nil {
+		return err
+	}
+	cws, err := store.Read(ctx.ID())
+	if err != nil {
+		cws = core.NewCrossTransactionWithSignatures(ctx, 0)
+		cws.SetStatus(core.CtxStatusPending)
+		if err := store.Write(cws); err != nil {
+			return err
+		}
+		return this.collect(store, cws)
+	}
+	if err := cws.AddSignature(ctx); err != nil {
+		if err == core.ErrDuplicateSign {
+			return nil
+		}
+		// the origin chain reorged, anchors sign the ctx again in its canonical block
+		if err == core.ErrInvalidSign && cws.BlockHash() != ctx.BlockHash() &&
+			(cws.Status == core.CtxStatusWaiting || cws.Status == core.CtxStatusPending) {
+			log.Warn("replace remote ctx of reorged block", "id", ctx.ID().String(),
+				"old", cws.BlockHash().String(), "new", ctx.BlockHash().String(), "err", crosshub.ErrReorgCtx)
+			// signatures of the old block are dropped, the quorum starts over
+			if err := store.Deletes([]common.Hash{ctx.ID()}); err != nil {
+				return err
+			}
+			replaced := core.NewCrossTransactionWithSignatures(ctx, 0)
+			replaced.SetStatus(core.CtxStatusPending)
+			if err := store.Write(replaced); err != nil {
+				return err
+			}
+			return this.collect(store, replaced)
 		}
+		return err
 	}
+	log.Info("add remote signature", "id", ctx.ID().String(), "signatures", cws.SignaturesLength())
+	if err := store.Write(cws); err != nil {
+		return err
+	}
+	return this.collect(store, cws)
+}
+
+// remoteStore returns the store of the orders made on the remote chain purpose.
+func (this *Viewer) remoteStore(purpose uint8) (*database.IndexDB, error) {
+	store, ok := this.RemoteStores[purpose]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", registry.ErrUnknownChain, purpose)
+	}
+	return store, nil
 }
 
-func (this *Viewer)GetEvents() {
+// advanceStatus moves the ctx in store to target through every store sync step
+// in between, events on chain imply the steps before them.
+func (this *Viewer) advanceStatus(store *database.IndexDB, id common.Hash, target core.CtxStatus, number uint64) error {
+	ctx, err := store.Read(id)
+	if err != nil {
+		return err
+	}
+	path, err := core.TransitionPath(ctx.Status, target)
+	if err != nil {
+		return err
+	}
+	for _, status := range path {
+		if err := store.SetStatus(id, status, number, core.Normal); err != nil {
+			return err
+		}
+	}
+	log.Info("ctx status changed", "id", id.String(), "from", ctx.Status, "to", target)
+	return nil
+}
+
+// GetEvents scans the next confirmed block range, retrying with backoff on
+// failure. The scan cursor never advances past a range that failed. It reports
+// whether confirmed blocks are left to scan.
+func (this *Viewer)GetEvents() bool {
+	backoff := scanRetryBackoff
+	for attempt := 1; ; attempt++ {
+		more, err := this.scan()
+		if err == nil {
+			return more
+		}
+		if attempt == maxScanRetries {
+			log.Error("GetEvents","height",this.currentHeight,"attempts",attempt,"err",err)
+			return false
+		}
+		log.Warn("GetEvents retry","height",this.currentHeight,"attempt",attempt,"backoff",backoff,"err",err)
+		select {
+		case <-this.ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// scan processes the logs of the next confirmed block range, the ctxs written
+// and the scan cursor are committed atomically. It reports whether confirmed
+// blocks are left after the range.
+func (this *Viewer) scan() (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
+	if reorged, err := this.checkReorg(ctx); err != nil {
+		return false, fmt.Errorf("check reorg: %w", err)
+	} else if reorged {
+		log.Info("GetEvents","rescan from",this.currentHeight)
+	}
 	var result hexutil.Big
 	if err := this.Client.CallContext(ctx,&result, "eth_blockNumber");err != nil {
-		log.Info("CallContext","err",err)
+		return false, fmt.Errorf("eth_blockNumber: %w", err)
 	}
+	// only blocks with enough confirmations are scanned
+	head := result.ToInt().Uint64()
+	atomic.StoreUint64(&this.head, head)
+	if head < this.currentHeight + this.chain.Confirmations {
+		return false, nil
+	}
+	confirmed := head - this.chain.Confirmations
 	var toBlock uint64
-	if this.currentHeight < result.ToInt().Uint64() - 99 {
+	if this.currentHeight + 99 < confirmed {
 		toBlock = this.currentHeight + 99
 	} else {
-		//toBlock = result.ToInt().Uint64() - 12
-		toBlock = result.ToInt().Uint64() - 1
+		toBlock = confirmed
 	}
 	records := simplechain.FilterQuery{
 		FromBlock: big.NewInt(int64(this.currentHeight)),
@@ -214,193 +471,148 @@ func (this *Viewer)GetEvents() {
 	}
 	logs, err := this.SimpleClient.FilterLogs(ctx, records)
 	if err != nil {
-		log.Info("GetEvents","err",err)
-		//TODO err
+		return false, fmt.Errorf("filter logs: %w", err)
+	}
+	tipHash, err := this.canonicalHash(ctx, toBlock)
+	if err != nil {
+		return false, fmt.Errorf("header %d: %w", toBlock, err)
+	}
+
+	batch, err := this.newBatch()
+	if err != nil {
+		return false, err
 	}
+	defer batch.rollback()
 	if len(logs) > 0 {
-		this.EventLog(logs)
+		this.EventLog(batch, logs)
+	}
+	blocks := make([]logBlock, 0, len(logs)+1)
+	for _, l := range logs {
+		blocks = append(blocks, logBlock{number: l.BlockNumber, hash: l.BlockHash})
+	}
+	blocks = append(blocks, logBlock{number: toBlock, hash: tipHash})
+	if err := this.recordBlocks(batch, blocks, toBlock); err != nil {
+		return false, fmt.Errorf("record blocks: %w", err)
+	}
+	if err := batch.local.Set("currentHeight", toBlock+1); err != nil {
+		return false, err
+	}
+	if err := batch.commit(); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
 	}
-	this.currentHeight = toBlock + 1
+	atomic.StoreUint64(&this.currentHeight, toBlock+1)
+	this.flush(batch)
 	log.Info("GetEvents","currentHeight",this.currentHeight)
-
+	return toBlock < confirmed, nil
 }
 
 
-func (this *Viewer) EventLog(logs []types.Log) {
-	makerTx := abiParsed.Events["MakerTx"].ID().Hex()
-	takerTx := abiParsed.Events["TakerTx"].ID().Hex()
-	makerFinish := abiParsed.Events["MakerFinish"].ID().Hex()
+// EventLog handles the cross contract logs, writing through batch.
+func (this *Viewer) EventLog(batch *scanBatch, logs []types.Log) {
 	for _, event := range logs {
-		switch event.Topics[0].Hex() {
-		case makerTx:
-			var args CrossMakerTx
-			err := abiParsed.Unpack(&args, "MakerTx", event.Data)
+		if len(event.Topics) == 0 {
+			continue
+		}
+		switch event.Topics[0] {
+		case crossTopics.makerTx:
+			args, err := this.contract.ParseMakerTx(event)
 			if err != nil {
-				log.Info("EventLog","Unpack err",err)
+				log.Info("EventLog","ParseMakerTx err",err)
+				continue
 			}
 
-			ctm :=  core.NewCrossTransaction(args.Value,args.DestValue,args.From,args.To,2,args.Purpose, args.TxId,event.TxHash,event.BlockHash,args.Payload)
-			signHash := func(hash []byte) ([]byte, error) {
-				return  crypto.Sign(hash,this.PrivateKey.K)
-			}
-			ctms,err :=  core.SignCtx(ctm,core.MakeCtxSigner(big.NewInt(11)),signHash)
+			ctm :=  core.NewCrossTransaction(args.Value,args.DestValue,args.From,args.To,this.chain.Purpose,args.Purpose, args.TxId,event.TxHash,event.BlockHash,args.Payload)
+			ctms,err := this.signCtx(ctm)
 			if err != nil {
-				log.Info("SignCtx","err",err)
+				log.Info("SignCtx","purpose",args.Purpose,"err",err)
+				continue
 			}
-			//from,err := core.CtxSender(core.MakeCtxSigner(big.NewInt(11)),ctms)
-			//if err != nil {
-			//	log.Info("CtxSender","err",err)
-			//}
-			//publicKey :=  crypto.PubkeyToAddress(this.PrivateKey.K.PublicKey)
-			//if err != nil {
-			//	log.Info("PublicKey","err",err)
-			//}
-
-
-			log.Info("receive ctx msg","msg",args,"ctms",ctms)
-			err = this.LocalStore.Write(ctms)
+
+			log.Info("receive ctx msg","id",hexutil.Encode(args.TxId[:]),"ctms",ctms)
+			cws := core.NewCrossTransactionWithSignatures(ctms, event.BlockNumber)
+			cws.SetStatus(core.CtxStatusWaiting)
+			err = batch.local.Write(cws)
 			if err != nil {
 				log.Error("Write","err",err)
 			}
-			this.eventCh <- ctms
-		case takerTx:
-			var args CrossTakerTx
-			err := abiParsed.Unpack(&args, "TakerTx", event.Data)
+			batch.send(ctms)
+		case crossTopics.takerTx:
+			args, err := this.contract.ParseTakerTx(event)
 			if err != nil {
-				log.Info("EventLog","Unpack err",err)
+				log.Info("EventLog","ParseTakerTx err",err)
+				continue
 			}
-			rtm := core.NewReceptTransaction(args.TxId,event.TxHash,args.From.String(),args.To.String(),args.Taker,2,args.Purpose,args.Payload)
+			rtm := core.NewReceptTransaction(args.TxId,event.TxHash,args.From.String(),args.To.String(),args.Taker,this.chain.Purpose,args.Purpose,args.Payload)
 			signHash := func(hash []byte) ([]byte, error) {
 				return  crypto.Sign(hash,this.PrivateKey.K)
 			}
-			rtms,err :=  core.SignRtx(rtm,core.MakeRtxSigner(big.NewInt(11)),signHash)
+			rtms,err :=  core.SignRtx(rtm,this.registry.HubRtxSigner(),signHash)
 			if err != nil {
 				log.Info("SignRtx","err",err)
+				continue
 			}
-			err = this.RemoteStore.Deletes([]common.Hash{rtms.ID()})
-			if err != nil {
-				log.Info("Deletes","err",err)
+			// logs are scanned behind the chain head, the taker is confirmed
+			if store, err := batch.remote(args.Purpose); err != nil {
+				log.Info("remoteStore","err",err)
+			} else if err = this.advanceStatus(store, rtms.ID(), core.CtxStatusExecuted, event.BlockNumber); err != nil {
+				log.Info("advanceStatus","err",err)
 			}
 			log.Info("takerTx","msg",rtms)
-			this.eventCh <- rtms
-			//TODO rtx and handle
-			//rtxMsg := core.NewReceptTransaction()
-		case makerFinish:
-			var args CrossMakerFinish
-			err := abiParsed.Unpack(&args, "MakerFinish", event.Data)
+			batch.send(rtms)
+		case crossTopics.makerFinish:
+			args, err := this.contract.ParseMakerFinish(event)
 			if err != nil {
-				log.Info("EventLog","Unpack err",err)
+				log.Info("EventLog","ParseMakerFinish err",err)
+				continue
 			}
 			log.Info("receive finish msg","Id",hexutil.Encode(args.TxId[:]))
 
-			//TODO delete localstore
-			err = this.LocalStore.Deletes([]common.Hash{args.TxId})
+			made, _ := batch.local.Read(args.TxId)
+			err = this.advanceStatus(batch.local, args.TxId, core.CtxStatusFinished, event.BlockNumber)
 			if err != nil {
-				log.Error("Deletes","Id",hexutil.Encode(args.TxId[:]))
+				log.Error("advanceStatus","Id",hexutil.Encode(args.TxId[:]),"err",err)
+			} else if made.Status != core.CtxStatusFinished {
+				batch.finish(made, event.BlockNumber)
 			}
-		}
-	}
-}
-
-type CrossMakerTx struct {
-	TxId          [32]byte
-	Value         *big.Int
-	DestValue     *big.Int
-	From          string
-	To            string
-	Purpose       uint8
-	Payload       []byte
-	//Raw           types.Log
-}
-
-type CrossTakerTx struct {
-	TxId          [32]byte
-	From          common.Address
-	To            common.Address
-	Taker         string
-	Purpose       uint8
-	Payload       []byte
-	//Raw           types.Log
-}
-
-type CrossMakerFinish struct {
-	TxId [32]byte
-	To   common.Address
-	//Raw  types.Log
-}
-
-func (this *Viewer)GetAnchors() {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
-	defer cancel()
-	var result hexutil.Big
-	if err := this.Client.CallContext(ctx,&result, "eth_blockNumber");err != nil {
-		log.Info("CallContext","err",err)
-		return
-	}
-	data,_ := hexutil.Decode("0x028acd480000000000000000000000000000000000000000000000000000000000000005")
-	contractAddress := common.HexToAddress(this.Address)
-	ret,err := this.SimpleClient.CallContract(ctx,simplechain.CallMsg{ From: common.Address{},To: &contractAddress, Data: data},result.ToInt())
-	if err != nil {
-		log.Info("CallContract","err",err)
-		return
-	}
-
-
-	//log.Info("CallContract","ret",ret)
-	//type Result struct {
-	//	_anchors []common.Address
-	//	uint8
-	//}
-	//
-	//var anchors Result
-	//err = abiParsed.Unpack(&anchors, "getAnchors", ret)
-	//if err != nil {
-	//	log.Info("Unpack","err",err)
-	//	return
-	//}
-	//log.Info("getAnchors","anchors",anchors)
-	var anchors []common.Address
-	if len(ret) > 64 {
-		signConfirmCount := new(big.Int).SetBytes(ret[common.HashLength : common.HashLength*2]).Uint64()
-		anchorLen := new(big.Int).SetBytes(ret[common.HashLength*2 : common.HashLength*3]).Uint64()
-
-		var anchor common.Address
-		for i := uint64(0); i < anchorLen; i++ {
-			copy(anchor[:], ret[common.HashLength*(4+i)-common.AddressLength:common.HashLength*(4+i)])
-			anchors = append(anchors, anchor)
-		}
-		if signConfirmCount > 0 { //when set no anchors,signConfirmCount Parsed as 0
-			for _,v := range anchors {
-				log.Info("getAnchors","anchors",v.String())
-				this.Anchors[v] = struct{}{}
+		case crossTopics.addAnchors, crossTopics.removeAnchors, crossTopics.setAnchorStatus:
+			// the three events have the same fields
+			args, err := this.contract.ParseAddAnchors(event)
+			if err != nil {
+				log.Info("EventLog","ParseAddAnchors err",err)
+				continue
 			}
+			log.Info("receive anchor msg","purpose",args.Purpose,"number",event.BlockNumber)
+			batch.refreshAnchors(args.Purpose, event.BlockNumber)
 		}
 	}
 }
 
-func (this *Viewer)createTransaction(rtm *core.ReceptTransaction) (*types.Transaction, error) {
-	data, err := rtm.ConstructData(abiParsed)
-	if err != nil {
-		log.Error("ConstructData", "err", err)
-		return nil, err
-	}
-	publicKey :=  crypto.PubkeyToAddress(this.PrivateKey.K.PublicKey)
+// makerFinish sends the makerFinish transaction of rtm, finishing the local ctx.
+// The transaction is sent once per ctx, by the managed sender of the viewer.
+func (this *Viewer)makerFinish(rtm *core.ReceptTransaction) (*types.Transaction, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
-	nonce,err := this.SimpleClient.PendingNonceAt(ctx,publicKey)
-	if err != nil {
-		log.Info("PendingNonceAt","err",err)
-	}
-	tx := types.NewTransaction(nonce, common.HexToAddress(this.Address), big.NewInt(0), 250000, big.NewInt(1e10), data)
-	signer := types.NewEIP155Signer(big.NewInt(2))
-	txHash := signer.Hash(tx)
-	signature, err := crypto.Sign(txHash.Bytes(),this.PrivateKey.K)
+	data, err := crossdemo.PackMakerFinish(crossdemo.CrossStructRecept{
+		TxId:    rtm.Data.CTxId,
+		TxHash:  rtm.Data.TxHash,
+		From:    rtm.Data.From,
+		To:      rtm.Data.To,
+		Taker:   common.HexToAddress(rtm.Data.Taker),
+		Origin:  rtm.Data.Origin,
+		Purpose: rtm.Data.Purpose,
+		Data:    rtm.Data.Payload,
+	})
 	if err != nil {
 		return nil, err
 	}
-	signedTx, err := tx.WithSignature(signer, signature)
+	return this.sender.Send(ctx, rtm.ID(), this.chain.Contract, nil, data)
+}
+
+// makerFinished logs the outcome of the makerFinish transaction of the ctx id.
+func (this *Viewer)makerFinished(id common.Hash, receipt *types.Receipt, err error) {
 	if err != nil {
-		return nil, err
+		log.Warn("makerFinish failed", "id", id.String(), "err", err)
+		return
 	}
-	return signedTx, nil
-}
\ No newline at end of file
+	log.Info("makerFinish mined", "id", id.String(), "tx", receipt.TxHash.String(), "number", receipt.BlockNumber)
+}
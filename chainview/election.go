@@ -0,0 +1,215 @@
+package chainview
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/simplechain-org/crosshub/core"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/crypto"
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+const (
+	// defaultTakeover is the wait before the next anchor takes over a submission
+	defaultTakeover = time.Minute
+	// liveWindow is how long an anchor counts as a swarm member after its last
+	// signed message
+	liveWindow = 10 * time.Minute
+	// maxElections bounds the elections kept for the api
+	maxElections = 128
+)
+
+// election is a submitter election of the local anchor, rtm is the receipt it
+// submits once elected.
+type election struct {
+	core.SubmitElection
+	rtm *core.ReceptTransaction
+}
+
+// rankAnchors orders anchors for the ctx id by keccak256(id, anchor), every hub
+// ranks the same anchors the same way.
+func rankAnchors(id common.Hash, anchors []common.Address) []common.Address {
+	keys := make(map[common.Address][]byte, len(anchors))
+	for _, anchor := range anchors {
+		keys[anchor] = crypto.Keccak256(id[:], anchor[:])
+	}
+	ranked := append([]common.Address(nil), anchors...)
+	sort.Slice(ranked, func(i, j int) bool {
+		return bytes.Compare(keys[ranked[i]], keys[ranked[j]]) < 0
+	})
+	return ranked
+}
+
+// heard records a signed message of anchor, anchors heard within liveWindow are
+// live members of the swarm.
+func (this *Viewer) heard(anchor common.Address) {
+	this.electMu.Lock()
+	defer this.electMu.Unlock()
+	this.lastHeard[anchor] = time.Now()
+}
+
+// candidates returns the on-chain anchors of the remote chain purpose ranked
+// for the ctx id. Every hub reads the same anchor set, so every hub elects the
+// same submitters whatever peers it has heard from.
+func (this *Viewer) candidates(id common.Hash, purpose uint8) []common.Address {
+	this.anchorMu.RLock()
+	defer this.anchorMu.RUnlock()
+	set, ok := this.anchorSets[purpose]
+	if !ok {
+		return nil
+	}
+	return rankAnchors(id, set.Anchors)
+}
+
+// takeoverDelay returns the wait of the candidate ranked rank before it takes
+// over, one takeover per live standby anchor ranked before it. Anchors not
+// heard within liveWindow are skipped, the local anchor is always live. The
+// caller holds electMu.
+func (this *Viewer) takeoverDelay(candidates []common.Address, rank, required int, self common.Address) time.Duration {
+	if rank < required {
+		return 0
+	}
+	turns := 1
+	for _, anchor := range candidates[required:rank] {
+		if heard, ok := this.lastHeard[anchor]; anchor == self || ok && time.Since(heard) < liveWindow {
+			turns++
+		}
+	}
+	return time.Duration(turns) * this.takeover
+}
+
+// elect runs the submitter election of the ctx finished by rtm. The first
+// signConfirmCount candidates submit at once, the others stand by and take
+// over one after another if the ctx has not finished by their deadline, dead
+// anchors ranked before them are not waited for.
+func (this *Viewer) elect(rtm *core.ReceptTransaction) {
+	id := rtm.ID()
+	if ctx, err := this.LocalStore.Read(id); err == nil && ctx.Status >= core.CtxStatusFinishing {
+		return
+	}
+	self := this.self()
+	required := int(this.SignConfirmCount(rtm.Data.Origin))
+	if required < 1 {
+		required = 1
+	}
+
+	this.electMu.Lock()
+	if _, ok := this.elections[id]; ok {
+		this.electMu.Unlock()
+		return
+	}
+	candidates := this.candidates(id, rtm.Data.Origin)
+	rank := -1
+	for i, anchor := range candidates {
+		if anchor == self {
+			rank = i
+		}
+	}
+	if rank < 0 {
+		this.electMu.Unlock()
+		log.Warn("not an anchor, skip submitter election", "id", id.String(), "purpose", rtm.Data.Origin, "self", self.String())
+		return
+	}
+	e := &election{
+		SubmitElection: core.SubmitElection{
+			CtxId:      id,
+			Purpose:    rtm.Data.Origin,
+			Submitter:  self,
+			Candidates: candidates,
+			Rank:       rank,
+			Required:   required,
+			Deadline:   time.Now().Add(this.takeoverDelay(candidates, rank, required, self)),
+			State:      core.ElectionStandby,
+		},
+		rtm: rtm,
+	}
+	this.addElection(e)
+	this.electMu.Unlock()
+
+	log.Info("submitter election", "id", id.String(), "rank", rank, "required", required,
+		"candidates", len(candidates), "deadline", e.Deadline)
+	if rank < required {
+		this.submit(e)
+	}
+}
+
+// addElection keeps e, dropping the oldest settled elections over maxElections.
+func (this *Viewer) addElection(e *election) {
+	this.elections[e.CtxId] = e
+	this.electionOrder = append(this.electionOrder, e.CtxId)
+	for i := 0; len(this.electionOrder) > maxElections && i < len(this.electionOrder); {
+		id := this.electionOrder[i]
+		if this.elections[id].State == core.ElectionStandby {
+			i++
+			continue
+		}
+		delete(this.elections, id)
+		this.electionOrder = append(this.electionOrder[:i], this.electionOrder[i+1:]...)
+	}
+}
+
+// checkElections settles the standby elections whose ctx finished and takes
+// over the submissions past their deadline.
+func (this *Viewer) checkElections() {
+	var due []*election
+	this.electMu.Lock()
+	for _, id := range this.electionOrder {
+		if e := this.elections[id]; e.State == core.ElectionStandby {
+			due = append(due, e)
+		}
+	}
+	this.electMu.Unlock()
+
+	for _, e := range due {
+		if ctx, err := this.LocalStore.Read(e.CtxId); err == nil && ctx.Status == core.CtxStatusFinished {
+			this.settle(e, core.ElectionLanded, common.Hash{})
+			log.Info("ctx finished by other anchors", "id", e.CtxId.String(), "rank", e.Rank)
+			continue
+		}
+		if time.Now().Before(e.Deadline) {
+			continue
+		}
+		log.Warn("take over makerFinish submission", "id", e.CtxId.String(), "rank", e.Rank, "deadline", e.Deadline)
+		this.submit(e)
+	}
+}
+
+// submit sends the makerFinish transaction of the elected local anchor.
+func (this *Viewer) submit(e *election) {
+	tx, err := this.makerFinish(e.rtm)
+	if err != nil {
+		log.Error("makerFinish", "id", e.CtxId.String(), "err", err)
+		this.settle(e, core.ElectionFailed, common.Hash{})
+		return
+	}
+	this.settle(e, core.ElectionSubmitted, tx.Hash())
+	if err := this.advanceStatus(this.LocalStore, e.CtxId, core.CtxStatusFinishing, 0); err != nil {
+		log.Info("advanceStatus", "id", e.CtxId.String(), "err", err)
+	}
+	log.Info("makerFinish", "id", e.CtxId.String(), "tx", tx.Hash().String(), "rank", e.Rank)
+}
+
+func (this *Viewer) settle(e *election, state core.ElectionState, txHash common.Hash) {
+	this.electMu.Lock()
+	defer this.electMu.Unlock()
+	e.State, e.TxHash = state, txHash
+}
+
+// self returns the address the local anchor signs with.
+func (this *Viewer) self() common.Address {
+	return crypto.PubkeyToAddress(this.PrivateKey.K.PublicKey)
+}
+
+// Elections returns the latest submitter elections of the local anchor, oldest first.
+func (this *Viewer) Elections() []core.SubmitElection {
+	this.electMu.Lock()
+	defer this.electMu.Unlock()
+	elections := make([]core.SubmitElection, 0, len(this.electionOrder))
+	for _, id := range this.electionOrder {
+		elections = append(elections, this.elections[id].SubmitElection)
+	}
+	return elections
+}
@@ -0,0 +1,110 @@
+package chainview
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/simplechain-org/crosshub/core"
+
+	"github.com/simplechain-org/go-simplechain/common"
+)
+
+func TestRankAnchors(t *testing.T) {
+	anchors := []common.Address{
+		common.HexToAddress("0x01"),
+		common.HexToAddress("0x02"),
+		common.HexToAddress("0x03"),
+		common.HexToAddress("0x04"),
+	}
+	reversed := []common.Address{anchors[3], anchors[2], anchors[1], anchors[0]}
+
+	id := common.HexToHash("0xaa")
+	ranked := rankAnchors(id, anchors)
+	if len(ranked) != len(anchors) {
+		t.Fatalf("ranked %d anchors, want %d", len(ranked), len(anchors))
+	}
+	// the ranking does not depend on the order anchors are known in
+	for i, anchor := range rankAnchors(id, reversed) {
+		if ranked[i] != anchor {
+			t.Fatalf("rank %d = %s, want %s", i, anchor.String(), ranked[i].String())
+		}
+	}
+
+	// submissions are spread over the anchors
+	first := make(map[common.Address]bool)
+	for i := 0; i < 64; i++ {
+		first[rankAnchors(common.BigToHash(big.NewInt(int64(i))), anchors)[0]] = true
+	}
+	if len(first) < 2 {
+		t.Errorf("one anchor ranked first for every ctx")
+	}
+}
+
+func TestViewer_Candidates(t *testing.T) {
+	var (
+		first  = common.HexToAddress("0x01")
+		second = common.HexToAddress("0x02")
+		third  = common.HexToAddress("0x03")
+		fourth = common.HexToAddress("0x04")
+		set    = []common.Address{first, second, third, fourth}
+		id     = common.HexToHash("0xaa")
+	)
+	viewer := func(heard ...common.Address) *Viewer {
+		v := &Viewer{
+			anchorSets: map[uint8]*core.AnchorSet{5: {Purpose: 5, Anchors: set, SignConfirmCount: 2}},
+			lastHeard:  make(map[common.Address]time.Time),
+			takeover:   time.Minute,
+		}
+		for _, anchor := range heard {
+			v.lastHeard[anchor] = time.Now()
+		}
+		return v
+	}
+	// a hub that just restarted has heard of no one, it elects the same
+	// submitters as a hub that has heard of every anchor
+	restarted, running := viewer(), viewer(set...)
+	candidates := restarted.candidates(id, 5)
+	if !reflect.DeepEqual(candidates, running.candidates(id, 5)) || !reflect.DeepEqual(candidates, rankAnchors(id, set)) {
+		t.Fatalf("candidates %v and %v differ", candidates, running.candidates(id, 5))
+	}
+	for _, v := range []*Viewer{restarted, running} {
+		for rank, anchor := range candidates[:2] {
+			if delay := v.takeoverDelay(candidates, rank, 2, anchor); delay != 0 {
+				t.Fatalf("submitter %d waits %v", rank, delay)
+			}
+		}
+	}
+
+	// standby anchors wait for the live ones ranked before them only
+	last := candidates[3]
+	if delay := running.takeoverDelay(candidates, 3, 2, last); delay != 2*time.Minute {
+		t.Errorf("last standby waits %v after live anchors", delay)
+	}
+	stale := viewer(set...)
+	stale.lastHeard[candidates[2]] = time.Now().Add(-2 * liveWindow)
+	if delay := stale.takeoverDelay(candidates, 3, 2, last); delay != time.Minute {
+		t.Errorf("last standby waits %v after a dead anchor", delay)
+	}
+}
+
+func TestViewer_AddElection(t *testing.T) {
+	v := &Viewer{elections: make(map[common.Hash]*election)}
+	standby := common.HexToHash("0x01")
+	v.addElection(&election{SubmitElection: core.SubmitElection{CtxId: standby, State: core.ElectionStandby}})
+	for i := 2; i <= maxElections+10; i++ {
+		id := common.BigToHash(big.NewInt(int64(i)))
+		v.addElection(&election{SubmitElection: core.SubmitElection{CtxId: id, State: core.ElectionSubmitted}})
+	}
+	if len(v.electionOrder) != maxElections || len(v.elections) != maxElections {
+		t.Fatalf("kept %d elections, want %d", len(v.electionOrder), maxElections)
+	}
+	// standby elections are kept until settled
+	if _, ok := v.elections[standby]; !ok {
+		t.Errorf("standby election dropped")
+	}
+	if got := v.Elections(); len(got) != maxElections || got[0].CtxId != standby {
+		t.Errorf("unexpected elections order")
+	}
+}
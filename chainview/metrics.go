@@ -0,0 +1,100 @@
+package chainview
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/simplechain-org/crosshub/core"
+
+	"github.com/simplechain-org/go-simplechain/log"
+	"github.com/simplechain-org/go-simplechain/metrics"
+)
+
+// latencyQueue is the number of finished ctxs waiting for their latency record.
+const latencyQueue = 256
+
+// finishedCtx is a local ctx finished at block number.
+type finishedCtx struct {
+	cws    *core.CrossTransactionWithSignatures
+	number uint64
+}
+
+// registerMetrics registers the scan progress and the store sizes of the
+// viewer, they are stubs unless metrics are enabled.
+func (this *Viewer) registerMetrics() {
+	prefix := fmt.Sprintf("chainview/%d", this.chain.Purpose)
+	metrics.NewRegisteredFunctionalGauge(prefix+"/height", nil, func() int64 {
+		return int64(this.Height())
+	})
+	// blocks between the chain head and the last scanned block
+	metrics.NewRegisteredFunctionalGauge(prefix+"/lag", nil, func() int64 {
+		head, height := atomic.LoadUint64(&this.head), this.Height()
+		if head < height {
+			return 0
+		}
+		return int64(head + 1 - height)
+	})
+	metrics.NewRegisteredFunctionalGauge(prefix+"/store/local", nil, func() int64 {
+		return int64(this.LocalStore.Count())
+	})
+	for origin, store := range this.RemoteStores {
+		store := store
+		metrics.NewRegisteredFunctionalGauge(fmt.Sprintf("%s/store/remote/%d", prefix, origin), nil, func() int64 {
+			return int64(store.Count())
+		})
+	}
+}
+
+// queueLatency queues the latency record of a finished ctx for
+// observeLatencies, it is dropped while the queue is full.
+func (this *Viewer) queueLatency(finished finishedCtx) {
+	if !metrics.Enabled {
+		return
+	}
+	select {
+	case this.latencyCh <- finished:
+	default:
+		log.Debug("latency record dropped", "id", finished.cws.ID().String())
+	}
+}
+
+// observeLatencies records the queued latencies until the viewer stops, the
+// block headers are requested off the scan loop.
+func (this *Viewer) observeLatencies() {
+	for {
+		select {
+		case finished := <-this.latencyCh:
+			this.observeLatency(finished)
+		case <-this.ctx.Done():
+			return
+		}
+	}
+}
+
+// observeLatency records the time from the maker block to the finish block of
+// a local ctx, by the destination chain of the ctx.
+func (this *Viewer) observeLatency(finished finishedCtx) {
+	if !metrics.Enabled {
+		return
+	}
+	ctx, cancel := context.WithTimeout(this.ctx, 10*time.Second)
+	defer cancel()
+	maker, err := this.SimpleClient.HeaderByNumber(ctx, new(big.Int).SetUint64(finished.cws.BlockNum))
+	if err != nil {
+		log.Debug("maker header", "id", finished.cws.ID().String(), "err", err)
+		return
+	}
+	finish, err := this.SimpleClient.HeaderByNumber(ctx, new(big.Int).SetUint64(finished.number))
+	if err != nil {
+		log.Debug("finish header", "id", finished.cws.ID().String(), "err", err)
+		return
+	}
+	if finish.Time < maker.Time {
+		return
+	}
+	name := fmt.Sprintf("chainview/%d/latency/%d", this.chain.Purpose, finished.cws.Data.Purpose)
+	metrics.GetOrRegisterTimer(name, nil).Update(time.Duration(finish.Time-maker.Time) * time.Second)
+}
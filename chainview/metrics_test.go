@@ -0,0 +1,71 @@
+package chainview
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/database"
+	"github.com/simplechain-org/crosshub/registry"
+
+	"github.com/simplechain-org/go-simplechain/metrics"
+)
+
+func TestViewer_Metrics(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	t.Cleanup(func() {
+		metrics.Enabled = enabled
+		metrics.DefaultRegistry.UnregisterAll()
+	})
+	v := newQuorumViewer(t, 1)
+	v.chain = &registry.Chain{Purpose: 9}
+	v.LocalStore = database.NewIndexDB(big.NewInt(9), v.rootDB, 16)
+	v.currentHeight, v.head = 100, 150
+	v.registerMetrics()
+	if err := v.storeRemoteSignature(signedCtx(t, v)()); err != nil {
+		t.Fatal(err)
+	}
+
+	gauge := func(name string) int64 {
+		g, ok := metrics.DefaultRegistry.Get(name).(metrics.Gauge)
+		if !ok {
+			t.Fatalf("no gauge %s", name)
+		}
+		return g.Value()
+	}
+	if h := gauge("chainview/9/height"); h != 100 {
+		t.Fatalf("height %d", h)
+	}
+	if lag := gauge("chainview/9/lag"); lag != 51 {
+		t.Fatalf("lag %d", lag)
+	}
+	if n := gauge("chainview/9/store/remote/2"); n != 1 {
+		t.Fatalf("remote store size %d", n)
+	}
+	if n := gauge("chainview/9/store/local"); n != 0 {
+		t.Fatalf("local store size %d", n)
+	}
+}
+
+func TestViewer_QueueLatency(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	t.Cleanup(func() { metrics.Enabled = enabled })
+	v := newQuorumViewer(t, 1)
+	v.LocalStore = database.NewIndexDB(big.NewInt(5), v.rootDB, 16)
+	v.latencyCh = make(chan finishedCtx, 1)
+
+	// the viewer has no client, headers requested by the flush would panic
+	cws := core.NewCrossTransactionWithSignatures(signedCtx(t, v)(), 10)
+	b := &scanBatch{local: v.LocalStore}
+	b.finish(cws, 12)
+	b.finish(cws, 13)
+	v.flush(b)
+	if len(v.latencyCh) != 1 {
+		t.Fatalf("%d latencies queued, want 1", len(v.latencyCh))
+	}
+	if finished := <-v.latencyCh; finished.number != 12 {
+		t.Fatalf("queued the ctx finished at %d", finished.number)
+	}
+}
@@ -0,0 +1,124 @@
+package chainview
+
+import (
+	"time"
+
+	"github.com/asdine/storm/v3/q"
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/database"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+// pendingExpiry is how long a remote ctx may wait for its signature quorum
+// before the partial signatures are dropped
+const pendingExpiry = 30 * time.Minute
+
+// pendingCtx is a remote ctx collecting anchor signatures.
+type pendingCtx struct {
+	origin uint8
+	seen   time.Time
+}
+
+// loadPending tracks the remote ctxs left pending before a restart, their
+// expiry starts over.
+func (this *Viewer) loadPending() {
+	this.pendingMu.Lock()
+	defer this.pendingMu.Unlock()
+	for origin, store := range this.RemoteStores {
+		for _, ctx := range store.Query(0, 0, nil, false, q.Eq(database.StatusField, uint8(core.CtxStatusPending))) {
+			this.pending[ctx.ID()] = pendingCtx{origin: origin, seen: time.Now()}
+		}
+	}
+	if len(this.pending) > 0 {
+		log.Info("resume pending remote ctxs", "purpose", this.chain.Purpose, "count", len(this.pending))
+	}
+}
+
+// collect keeps the remote ctx pending until the contract's signConfirmCount
+// distinct anchors signed it, it is then published as waiting to be taken.
+func (this *Viewer) collect(store *database.IndexDB, cws *core.CrossTransactionWithSignatures) error {
+	if cws.Status != core.CtxStatusPending {
+		return nil
+	}
+	required := int(this.SignConfirmCount(cws.Data.Origin))
+	signatures := this.anchorSignatures(cws)
+	if required == 0 || signatures < required {
+		this.pendingMu.Lock()
+		if _, ok := this.pending[cws.ID()]; !ok {
+			this.pending[cws.ID()] = pendingCtx{origin: cws.Data.Origin, seen: time.Now()}
+		}
+		this.pendingMu.Unlock()
+		log.Info("remote ctx pending", "id", cws.ID().String(), "signatures", signatures, "required", required)
+		return nil
+	}
+	if err := this.advanceStatus(store, cws.ID(), core.CtxStatusWaiting, 0); err != nil {
+		return err
+	}
+	this.pendingMu.Lock()
+	delete(this.pending, cws.ID())
+	this.pendingMu.Unlock()
+	log.Info("remote ctx quorum reached", "id", cws.ID().String(), "signatures", signatures, "required", required)
+	return nil
+}
+
+// anchorSignatures returns how many distinct anchors of the origin chain signed
+// the remote ctx cws, signatures of a removed anchor do not count.
+func (this *Viewer) anchorSignatures(cws *core.CrossTransactionWithSignatures) int {
+	var count int
+	for _, signer := range cws.Signers(this.chain.CtxSigner().SimpleSender) {
+		if this.isAnchor(cws.Data.Origin, signer) {
+			count++
+		}
+	}
+	return count
+}
+
+// checkPending publishes the pending remote ctxs completed by a lower
+// signConfirmCount and drops the expired ones.
+func (this *Viewer) checkPending() {
+	this.pendingMu.Lock()
+	pending := make(map[common.Hash]pendingCtx, len(this.pending))
+	for id, p := range this.pending {
+		pending[id] = p
+	}
+	this.pendingMu.Unlock()
+
+	for id, p := range pending {
+		store, err := this.remoteStore(p.origin)
+		if err != nil {
+			continue
+		}
+		cws, err := store.Read(id)
+		if err != nil || cws.Status != core.CtxStatusPending {
+			// taken or dropped by a reorg in the meantime
+			this.pendingMu.Lock()
+			delete(this.pending, id)
+			this.pendingMu.Unlock()
+			continue
+		}
+		if time.Since(p.seen) > pendingExpiry {
+			log.Warn("drop expired pending remote ctx", "id", id.String(), "signatures", this.anchorSignatures(cws),
+				"required", this.SignConfirmCount(p.origin))
+			if err := store.Deletes([]common.Hash{id}); err != nil {
+				log.Error("Deletes", "id", id.String(), "err", err)
+				continue
+			}
+			this.pendingMu.Lock()
+			delete(this.pending, id)
+			this.pendingMu.Unlock()
+			continue
+		}
+		if err := this.collect(store, cws); err != nil {
+			log.Error("collect", "id", id.String(), "err", err)
+		}
+	}
+}
+
+// PendingCount returns the number of remote ctxs waiting for their signature quorum.
+func (this *Viewer) PendingCount() int {
+	this.pendingMu.Lock()
+	defer this.pendingMu.Unlock()
+	return len(this.pending)
+}
@@ -0,0 +1,182 @@
+package chainview
+
+import (
+	"context"
+	stdecdsa "crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asdine/storm/v3"
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/database"
+	"github.com/simplechain-org/crosshub/registry"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/crypto"
+)
+
+// quorumChain is the chain of the viewers of the tests, the ctxs are made on
+// the remote chain 2.
+var quorumChain = &registry.Chain{Purpose: 5, ChainId: big.NewInt(5)}
+
+func newQuorumViewer(t *testing.T, signConfirmCount uint8) *Viewer {
+	dir, err := ioutil.TempDir("", "chainview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootDB, err := storm.Open(filepath.Join(dir, DataDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		rootDB.Close()
+		os.RemoveAll(dir)
+	})
+	return &Viewer{
+		rootDB:       rootDB,
+		RemoteStores: map[uint8]*database.IndexDB{2: database.NewIndexDB(big.NewInt(2), rootDB, 16)},
+		Anchors:      map[uint8]map[common.Address]struct{}{2: {}},
+		anchorSets:   map[uint8]*core.AnchorSet{2: {Purpose: 2, SignConfirmCount: signConfirmCount}},
+		chain:        quorumChain,
+		lastHeard:    make(map[common.Address]time.Time),
+		pending:      make(map[common.Hash]pendingCtx),
+	}
+}
+
+// signedCtx returns a signer of the same ctx by a new anchor of the chain 2
+// at each call.
+func signedCtx(t *testing.T, v *Viewer) func() *core.CrossTransaction {
+	ctx := core.NewCrossTransaction(big.NewInt(1e18), big.NewInt(1e17), "0x01", "", 2, 5,
+		common.HexToHash("0xaa"), common.HexToHash("0xbb"), common.HexToHash("0xcc"), nil)
+	return func() *core.CrossTransaction {
+		key, _ := crypto.GenerateKey()
+		v.Anchors[2][crypto.PubkeyToAddress(key.PublicKey)] = struct{}{}
+		return signCtxBy(t, ctx, key)
+	}
+}
+
+func signCtxBy(t *testing.T, ctx *core.CrossTransaction, key *stdecdsa.PrivateKey) *core.CrossTransaction {
+	signed, err := core.SignSimpleCtx(ctx, quorumChain.CtxSigner(), func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestViewer_Quorum(t *testing.T) {
+	v := newQuorumViewer(t, 2)
+	sign := signedCtx(t, v)
+	store := v.RemoteStores[2]
+
+	first := sign()
+	if err := v.storeRemoteSignature(first); err != nil {
+		t.Fatal(err)
+	}
+	if ctx, _ := store.Read(first.ID()); ctx.Status != core.CtxStatusPending {
+		t.Fatalf("status = %v, want pending", ctx.Status)
+	}
+	// the same anchor does not count twice
+	if err := v.storeRemoteSignature(first); err != nil {
+		t.Fatal(err)
+	}
+	if v.PendingCount() != 1 {
+		t.Fatalf("pending = %d, want 1", v.PendingCount())
+	}
+
+	if err := v.storeRemoteSignature(sign()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, _ := store.Read(first.ID())
+	if ctx.Status != core.CtxStatusWaiting || ctx.SignaturesLength() != 2 {
+		t.Fatalf("status = %v with %d signatures, want waiting with 2", ctx.Status, ctx.SignaturesLength())
+	}
+	if v.PendingCount() != 0 {
+		t.Fatalf("pending = %d, want 0", v.PendingCount())
+	}
+}
+
+func TestViewer_PendingExpiry(t *testing.T) {
+	v := newQuorumViewer(t, 3)
+	sign := signedCtx(t, v)
+	store := v.RemoteStores[2]
+
+	ctx := sign()
+	if err := v.storeRemoteSignature(ctx); err != nil {
+		t.Fatal(err)
+	}
+	v.checkPending()
+	if !store.Has(ctx.ID()) {
+		t.Fatal("pending ctx dropped before expiry")
+	}
+
+	v.pending[ctx.ID()] = pendingCtx{origin: 2, seen: time.Now().Add(-2 * pendingExpiry)}
+	v.checkPending()
+	if store.Has(ctx.ID()) || v.PendingCount() != 0 {
+		t.Fatal("expired pending ctx kept")
+	}
+}
+
+func TestViewer_PendingConfirmCountLowered(t *testing.T) {
+	v := newQuorumViewer(t, 3)
+	sign := signedCtx(t, v)
+	ctx := sign()
+	if err := v.storeRemoteSignature(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	v.anchorSets[2].SignConfirmCount = 1
+	v.checkPending()
+	if stored, _ := v.RemoteStores[2].Read(ctx.ID()); stored.Status != core.CtxStatusWaiting {
+		t.Fatalf("status = %v, want waiting", stored.Status)
+	}
+}
+
+func TestViewer_QuorumOfAnchors(t *testing.T) {
+	v := newQuorumViewer(t, 2)
+	sign := signedCtx(t, v)
+	store := v.RemoteStores[2]
+	messageCh := make(chan interface{})
+	v.messageCh = messageCh
+	v.ctx, v.cancel = context.WithCancel(context.Background())
+	defer v.cancel()
+	go v.loop()
+
+	first := sign()
+	stranger, _ := crypto.GenerateKey()
+	for _, ctx := range []*core.CrossTransaction{first, first, signCtxBy(t, first, stranger)} {
+		messageCh <- ctx
+	}
+	// the loop handled the messages before it receives the next one
+	messageCh <- nil
+	ctx, err := store.Read(first.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Status != core.CtxStatusPending || ctx.SignaturesLength() != 1 {
+		t.Fatalf("status = %v with %d signatures, want pending with 1", ctx.Status, ctx.SignaturesLength())
+	}
+
+	second := sign()
+	messageCh <- second
+	messageCh <- nil
+	ctx, _ = store.Read(first.ID())
+	if ctx.Status != core.CtxStatusWaiting {
+		t.Fatalf("status = %v, want waiting", ctx.Status)
+	}
+	// the signatures of the anchors are kept for the contract
+	signers := ctx.Signers(quorumChain.CtxSigner().SimpleSender)
+	want := make([]common.Address, 0, 2)
+	for _, signed := range []*core.CrossTransaction{first, second} {
+		addr, _ := quorumChain.CtxSigner().SimpleSender(signed)
+		want = append(want, addr)
+	}
+	if len(signers) != 2 || signers[0] != want[0] || signers[1] != want[1] {
+		t.Fatalf("signers = %v, want %v", signers, want)
+	}
+}
@@ -0,0 +1,260 @@
+package chainview
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/asdine/storm/v3/q"
+	"github.com/simplechain-org/crosshub"
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/database"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/crypto"
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+// reorgCheckDepth is how many blocks below the scan height keep their hash records
+const reorgCheckDepth = 1024
+
+// canonicalHash returns the hash of the block number on the canonical chain.
+func (this *Viewer) canonicalHash(ctx context.Context, number uint64) (common.Hash, error) {
+	header, err := this.SimpleClient.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// checkCanonical returns crosshub.ErrReorgCtx if the block hash of ctx left the canonical chain.
+func (this *Viewer) checkCanonical(ctx context.Context, cws *core.CrossTransactionWithSignatures) error {
+	hash, err := this.canonicalHash(ctx, cws.BlockNum)
+	if err != nil {
+		return err
+	}
+	if hash != cws.BlockHash() {
+		return crosshub.ErrReorgCtx
+	}
+	return nil
+}
+
+// recordBlocks records the hashes of the scanned blocks through batch, records
+// deeper than reorgCheckDepth below tip are pruned.
+func (this *Viewer) recordBlocks(batch *scanBatch, blocks []logBlock, tip uint64) error {
+	for _, b := range blocks {
+		if err := batch.local.SetBlockHash(b.number, b.hash); err != nil {
+			return err
+		}
+	}
+	if tip > reorgCheckDepth {
+		return batch.local.DeleteBlockRecords(0, tip-reorgCheckDepth)
+	}
+	return nil
+}
+
+type logBlock struct {
+	number uint64
+	hash   common.Hash
+}
+
+// checkReorg compares the recorded block hashes with the canonical chain, from
+// the highest down to the first one still canonical. If a recorded block left
+// the canonical chain, the ctxs after the fork are rolled back and the scan
+// restarts from the fork, re-broadcasting the ctxs and rtxs of the canonical
+// blocks to peers.
+func (this *Viewer) checkReorg(ctx context.Context) (bool, error) {
+	records := this.LocalStore.BlockRecords(0)
+	if len(records) == 0 {
+		return false, nil
+	}
+	var fork uint64
+	for i, record := range records {
+		hash, err := this.canonicalHash(ctx, record.Number)
+		if err != nil {
+			return false, err
+		}
+		if hash == record.Hash {
+			if i == 0 {
+				return false, nil
+			}
+			fork = record.Number
+			break
+		}
+		// no record is canonical, roll back below the lowest one
+		if i == len(records)-1 && record.Number > 0 {
+			fork = record.Number - 1
+		}
+	}
+
+	log.Warn("chain reorg detected", "purpose", this.chain.Purpose, "fork", fork,
+		"scanned", records[0].Number, "depth", records[0].Number-fork)
+	// the rollback and the scan cursor are committed together
+	batch, err := this.newBatch()
+	if err != nil {
+		return false, err
+	}
+	defer batch.rollback()
+	if err := this.rollback(ctx, batch, fork); err != nil {
+		return false, err
+	}
+	if err := batch.local.DeleteBlockRecords(fork+1, records[0].Number); err != nil {
+		return false, err
+	}
+	if err := batch.local.Set("currentHeight", fork+1); err != nil {
+		return false, err
+	}
+	if err := batch.commit(); err != nil {
+		return false, err
+	}
+	this.flush(batch)
+	atomic.StoreUint64(&this.currentHeight, fork+1)
+	return true, nil
+}
+
+// rollback reverts the ctxs changed by the blocks after fork, writing through
+// batch. Local orders whose block left the chain are removed, taken ones too
+// with their submitter election. The hubs holding the removed orders and the
+// reverted takers are told once the batch is committed, a finish rolled back
+// concerns the local hub only.
+func (this *Viewer) rollback(ctx context.Context, batch *scanBatch, fork uint64) error {
+	// local orders made or finished after the fork
+	var removed []common.Hash
+	for _, cws := range batch.local.Query(0, 0, nil, false, q.Gt(database.BlockNumField, fork)) {
+		switch cws.Status {
+		case core.CtxStatusPending, core.CtxStatusWaiting, core.CtxStatusIllegal,
+			core.CtxStatusExecuting, core.CtxStatusExecuted:
+			err := this.checkCanonical(ctx, cws)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, crosshub.ErrReorgCtx) {
+				return err
+			}
+			log.Warn("rollback local ctx", "id", cws.ID().String(), "number", cws.BlockNum, "status", cws.Status, "err", err)
+			removed = append(removed, cws.ID())
+			signed, err := this.signCtx(cws.CrossTransaction())
+			if err != nil {
+				log.Error("SignCtx", "id", cws.ID().String(), "err", err)
+				continue
+			}
+			batch.send(&core.RemovedCtx{Ctx: signed})
+
+		case core.CtxStatusFinished:
+			log.Warn("rollback local ctx", "id", cws.ID().String(), "number", cws.BlockNum, "status", cws.Status)
+			if err := batch.local.SetStatus(cws.ID(), core.CtxStatusExecuted, fork, core.Reorg); err != nil {
+				return err
+			}
+		}
+	}
+	if len(removed) > 0 {
+		if err := batch.local.Deletes(removed); err != nil {
+			return err
+		}
+	}
+	// the orders are gone, their makerFinish would revert
+	batch.dropped = append(batch.dropped, removed...)
+
+	// remote orders taken after the fork
+	for purpose, store := range batch.remotes {
+		for _, cws := range store.Query(0, 0, nil, false, q.Gt(database.BlockNumField, fork),
+			q.In(database.StatusField, []uint8{uint8(core.CtxStatusExecuting), uint8(core.CtxStatusExecuted)})) {
+			log.Warn("rollback remote ctx", "purpose", purpose, "id", cws.ID().String(), "number", cws.BlockNum, "status", cws.Status)
+			if err := store.SetStatus(cws.ID(), core.CtxStatusWaiting, fork, core.Reorg); err != nil {
+				return err
+			}
+			rtm := core.NewReceptTransaction(cws.ID(), common.Hash{}, cws.Data.From, cws.Data.To, "",
+				this.chain.Purpose, purpose, cws.Data.Payload)
+			signed, err := core.SignRtx(rtm, this.registry.HubRtxSigner(), func(hash []byte) ([]byte, error) {
+				return crypto.Sign(hash, this.PrivateKey.K)
+			})
+			if err != nil {
+				log.Error("SignRtx", "id", cws.ID().String(), "err", err)
+				continue
+			}
+			batch.send(&core.RevertedRtx{Rtx: signed})
+		}
+	}
+	return nil
+}
+
+// removeRemoteSignature drops the signature of anchor from the remote order
+// ctx, whose block left the canonical chain of its origin. The order starts
+// over pending with the signatures left, it is deleted if none is left. Orders
+// taken or signed again in another block are kept.
+func (this *Viewer) removeRemoteSignature(ctx *core.CrossTransaction, anchor common.Address) error {
+	store, err := this.remoteStore(ctx.Data.Origin)
+	if err != nil {
+		return err
+	}
+	cws, err := store.Read(ctx.ID())
+	if err != nil || cws.BlockHash() != ctx.BlockHash() {
+		return nil
+	}
+	if cws.Status != core.CtxStatusPending && cws.Status != core.CtxStatusWaiting {
+		log.Warn("keep removed remote ctx", "id", ctx.ID().String(), "status", cws.Status, "anchor", anchor.String())
+		return nil
+	}
+	signatures := cws.Resolution()
+	var kept []*core.CrossTransaction
+	for _, signed := range signatures {
+		if from, err := this.chain.CtxSigner().SimpleSender(signed); err == nil && from != anchor {
+			kept = append(kept, signed)
+		}
+	}
+	if len(kept) == len(signatures) {
+		return nil
+	}
+
+	log.Warn("remove remote signature of reorged block", "id", ctx.ID().String(), "anchor", anchor.String(),
+		"left", len(kept), "err", crosshub.ErrReorgCtx)
+	if err := store.Deletes([]common.Hash{ctx.ID()}); err != nil {
+		return err
+	}
+	this.pendingMu.Lock()
+	delete(this.pending, ctx.ID())
+	this.pendingMu.Unlock()
+	if len(kept) == 0 {
+		return nil
+	}
+	replaced := core.NewCrossTransactionWithSignatures(kept[0], 0)
+	for _, signed := range kept[1:] {
+		if err := replaced.AddSignature(signed); err != nil {
+			return err
+		}
+	}
+	replaced.SetStatus(core.CtxStatusPending)
+	if err := store.Write(replaced); err != nil {
+		return err
+	}
+	return this.collect(store, replaced)
+}
+
+// revertLocalTaker moves the local order id back to waiting, its taker left
+// the canonical chain of the remote chain. The election submitting its
+// makerFinish is dropped, a new taker starts another one.
+func (this *Viewer) revertLocalTaker(id common.Hash) error {
+	if err := this.LocalStore.SetStatus(id, core.CtxStatusWaiting, 0, core.Reorg); err != nil {
+		return err
+	}
+	this.dropElection(id)
+	log.Warn("revert local ctx taker", "id", id.String(), "err", crosshub.ErrReorgCtx)
+	return nil
+}
+
+// dropElection drops the submitter election of the ctx id, if any.
+func (this *Viewer) dropElection(id common.Hash) {
+	this.electMu.Lock()
+	defer this.electMu.Unlock()
+	if _, ok := this.elections[id]; !ok {
+		return
+	}
+	delete(this.elections, id)
+	for i, eid := range this.electionOrder {
+		if eid == id {
+			this.electionOrder = append(this.electionOrder[:i], this.electionOrder[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package chainview
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asdine/storm/v3"
+	"github.com/simplechain-org/crosshub/contract/crossdemo"
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/database"
+	"github.com/simplechain-org/crosshub/registry"
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain/accounts/abi"
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/core/types"
+	"github.com/simplechain-org/go-simplechain/crypto"
+	"github.com/simplechain-org/go-simplechain/crypto/ecdsa"
+	"github.com/simplechain-org/go-simplechain/ethclient"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+var scanContract = common.HexToAddress("0x737217d6768E96fee112c16a0A9DcF0af5d56979")
+
+// chainStandIn serves the blocks and the logs of a chain the test forks.
+type chainStandIn struct {
+	mu      sync.Mutex
+	headers []*types.Header
+	logs    []types.Log
+	// failTip fails the next header requests of the chain head
+	failTip int
+}
+
+// mine appends blocks up to number, tagged by fork so the blocks of a fork
+// have other hashes.
+func (c *chainStandIn) mine(number uint64, fork byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uint64(len(c.headers)) <= number {
+		n := len(c.headers)
+		parent := common.Hash{}
+		if n > 0 {
+			parent = c.headers[n-1].Hash()
+		}
+		c.headers = append(c.headers, &types.Header{
+			ParentHash: parent,
+			Number:     big.NewInt(int64(n)),
+			Difficulty: big.NewInt(1),
+			Time:       uint64(n),
+			Extra:      []byte{fork},
+		})
+	}
+}
+
+// fork drops the blocks from number on with their logs.
+func (c *chainStandIn) fork(number uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.headers = c.headers[:number]
+	logs := c.logs[:0]
+	for _, l := range c.logs {
+		if l.BlockNumber < number {
+			logs = append(logs, l)
+		}
+	}
+	c.logs = logs
+}
+
+// emit adds a log of the cross contract event to the block number.
+func (c *chainStandIn) emit(t *testing.T, number uint64, event string, args ...interface{}) {
+	parsed, err := abi.JSON(strings.NewReader(crossdemo.CrossDemoABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := parsed.Events[event].Inputs.Pack(args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logs = append(c.logs, types.Log{
+		Address:     scanContract,
+		Topics:      []common.Hash{parsed.Events[event].ID()},
+		Data:        data,
+		BlockNumber: number,
+		BlockHash:   c.headers[number].Hash(),
+		TxHash:      common.BytesToHash([]byte{byte(number), byte(len(c.logs))}),
+	})
+}
+
+func (c *chainStandIn) BlockNumber() *hexutil.Big {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return (*hexutil.Big)(big.NewInt(int64(len(c.headers) - 1)))
+}
+
+func (c *chainStandIn) GetBlockByNumber(number hexutil.Uint64, full bool) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if int(number) >= len(c.headers) {
+		return nil, nil
+	}
+	if c.failTip > 0 && int(number) == len(c.headers)-2 {
+		c.failTip--
+		return nil, errors.New("header unavailable")
+	}
+	return c.headers[number], nil
+}
+
+func (c *chainStandIn) GetLogs(crit map[string]interface{}) ([]types.Log, error) {
+	from, err := hexutil.DecodeUint64(crit["fromBlock"].(string))
+	if err != nil {
+		return nil, err
+	}
+	to, err := hexutil.DecodeUint64(crit["toBlock"].(string))
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	logs := []types.Log{}
+	for _, l := range c.logs {
+		if l.BlockNumber >= from && l.BlockNumber <= to {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+// newScanViewer returns the viewer of the chain 2 served by chain, the orders
+// of the remote chain 5 are kept in its remote store. The messages to peers
+// are sent to the returned channel.
+func newScanViewer(t *testing.T, chain *chainStandIn) (*Viewer, <-chan interface{}) {
+	reg, err := registry.New(&repo.Config{
+		Hub: repo.Hub{ChainId: 100},
+		Chains: []repo.Chain{
+			{Purpose: 2, ChainId: 2, Type: "simplechain", Endpoint: "http://127.0.0.1:8545",
+				Contract: scanContract.Hex()},
+			{Purpose: 5, ChainId: 5, Type: "simplechain", Remote: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := reg.Chain(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", chain); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	simpleClient := ethclient.NewClient(client)
+	contract, err := crossdemo.NewCrossDemo(scanContract, simpleClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "chainview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootDB, err := storm.Open(filepath.Join(dir, DataDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := crypto.GenerateKey()
+	eventCh := make(chan interface{}, 16)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		client.Close()
+		server.Stop()
+		rootDB.Close()
+		os.RemoveAll(dir)
+	})
+	return &Viewer{
+		Client:        client,
+		SimpleClient:  simpleClient,
+		Address:       scanContract.Hex(),
+		currentHeight: 1,
+		eventCh:       eventCh,
+		PrivateKey:    &ecdsa.PrivateKey{K: key},
+		contract:      contract,
+		RemoteStores:  map[uint8]*database.IndexDB{5: database.NewIndexDB(big.NewInt(5), rootDB, 16)},
+		LocalStore:    database.NewIndexDB(big.NewInt(2), rootDB, 16),
+		rootDB:        rootDB,
+		Anchors:       map[uint8]map[common.Address]struct{}{5: {}},
+		anchorSets:    make(map[uint8]*core.AnchorSet),
+		chain:         local,
+		registry:      reg,
+		elections:     make(map[common.Hash]*election),
+		lastHeard:     make(map[common.Address]time.Time),
+		pending:       make(map[common.Hash]pendingCtx),
+		ctx:           ctx,
+		cancel:        cancel,
+	}, eventCh
+}
+
+// drain returns the messages sent to peers so far.
+func drain(eventCh <-chan interface{}) []interface{} {
+	var events []interface{}
+	for {
+		select {
+		case ev := <-eventCh:
+			events = append(events, ev)
+		default:
+			return events
+		}
+	}
+}
+
+func TestViewer_Reorg(t *testing.T) {
+	chain := &chainStandIn{}
+	chain.mine(4, 0)
+	v, eventCh := newScanViewer(t, chain)
+
+	made, taken := common.HexToHash("0x01"), common.HexToHash("0x02")
+	chain.emit(t, 2, "MakerTx", made, big.NewInt(1e18), big.NewInt(1e17), "0x01", "", uint8(5), []byte{})
+	chain.emit(t, 3, "TakerTx", taken, common.HexToAddress("0x03"), common.HexToAddress("0x04"), "0x05", uint8(5), []byte{})
+	// the order of the chain 5 taken on this chain
+	order := core.NewCrossTransactionWithSignatures(core.NewCrossTransaction(big.NewInt(1e18), big.NewInt(1e17),
+		"0x03", "", 5, 2, taken, common.Hash{}, common.Hash{}, nil), 0)
+	order.SetStatus(core.CtxStatusWaiting)
+	if err := v.RemoteStores[5].Write(order); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.GetEvents() {
+		t.Fatal("blocks left to scan")
+	}
+	if v.Height() != 4 {
+		t.Fatalf("height = %d, want 4", v.Height())
+	}
+	ctx, err := v.LocalStore.Read(made)
+	if err != nil || ctx.Status != core.CtxStatusWaiting {
+		t.Fatalf("local ctx %v, err %v", ctx, err)
+	}
+	oldBlock := ctx.BlockHash()
+	if ctx, _ := v.RemoteStores[5].Read(taken); ctx.Status != core.CtxStatusExecuted {
+		t.Fatalf("remote ctx status = %v, want executed", ctx.Status)
+	}
+	if events := drain(eventCh); len(events) != 2 {
+		t.Fatalf("sent %d messages, want the ctx and the rtx", len(events))
+	}
+
+	// the blocks from 2 on are replaced, the order is made again in block 3
+	// and the taker is dropped
+	chain.fork(2)
+	chain.mine(5, 1)
+	chain.emit(t, 3, "MakerTx", made, big.NewInt(1e18), big.NewInt(1e17), "0x01", "", uint8(5), []byte{})
+
+	if v.GetEvents() {
+		t.Fatal("blocks left to scan")
+	}
+	if v.Height() != 5 {
+		t.Fatalf("height = %d, want 5", v.Height())
+	}
+	ctx, err = v.LocalStore.Read(made)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.BlockHash() == oldBlock || ctx.BlockNum != 3 || ctx.Status != core.CtxStatusWaiting {
+		t.Fatalf("local ctx in block %d %s with status %v, want rescanned in block 3", ctx.BlockNum, ctx.BlockHash().String(), ctx.Status)
+	}
+	if ctx, _ := v.RemoteStores[5].Read(taken); ctx.Status != core.CtxStatusWaiting {
+		t.Fatalf("remote ctx status = %v, want waiting", ctx.Status)
+	}
+
+	// the peers are told of the removed order and the reverted taker before
+	// the order is sent again
+	events := drain(eventCh)
+	if len(events) != 3 {
+		t.Fatalf("sent %d messages, want 3", len(events))
+	}
+	removed, ok := events[0].(*core.RemovedCtx)
+	if !ok || removed.Ctx.ID() != made || removed.Ctx.BlockHash() != oldBlock {
+		t.Fatalf("first message %#v, want the removed ctx", events[0])
+	}
+	if from, err := core.MakeCtxSigner(big.NewInt(5)).SimpleSender(removed.Ctx); err != nil || from != v.self() {
+		t.Fatalf("removed ctx signed by %s, err %v", from.String(), err)
+	}
+	if reverted, ok := events[1].(*core.RevertedRtx); !ok || reverted.Rtx.ID() != taken {
+		t.Fatalf("second message %#v, want the reverted rtx", events[1])
+	}
+	if resent, ok := events[2].(*core.CrossTransaction); !ok || resent.BlockHash() != ctx.BlockHash() {
+		t.Fatalf("third message %#v, want the ctx of the new block", events[2])
+	}
+}
+
+func TestViewer_ReorgTakenOrder(t *testing.T) {
+	chain := &chainStandIn{}
+	chain.mine(4, 0)
+	v, eventCh := newScanViewer(t, chain)
+	made := common.HexToHash("0x01")
+	chain.emit(t, 3, "MakerTx", made, big.NewInt(1e18), big.NewInt(1e17), "0x01", "", uint8(5), []byte{})
+	if v.GetEvents() {
+		t.Fatal("blocks left to scan")
+	}
+	drain(eventCh)
+
+	// a taker took the order, the local anchor stands by to submit its finish
+	for _, status := range []core.CtxStatus{core.CtxStatusExecuting, core.CtxStatusExecuted} {
+		if err := v.LocalStore.SetStatus(made, status, 0, core.Normal); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v.addElection(&election{SubmitElection: core.SubmitElection{CtxId: made, State: core.ElectionStandby}})
+
+	// the block of the order is replaced by one without it
+	chain.fork(2)
+	chain.mine(5, 1)
+	if v.GetEvents() {
+		t.Fatal("blocks left to scan")
+	}
+	if v.LocalStore.Has(made) {
+		t.Fatal("order of a reorged block kept")
+	}
+	if len(v.Elections()) != 0 {
+		t.Fatal("makerFinish election of a removed order kept")
+	}
+	events := drain(eventCh)
+	if len(events) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(events))
+	}
+	if removed, ok := events[0].(*core.RemovedCtx); !ok || removed.Ctx.ID() != made {
+		t.Fatalf("message %#v, want the removed ctx", events[0])
+	}
+}
+
+func TestViewer_RemovedCtx(t *testing.T) {
+	v := newQuorumViewer(t, 2)
+	sign := signedCtx(t, v)
+	store := v.RemoteStores[2]
+	messageCh := make(chan interface{})
+	v.messageCh = messageCh
+	v.ctx, v.cancel = context.WithCancel(context.Background())
+	defer v.cancel()
+	go v.loop()
+
+	first, second := sign(), sign()
+	messageCh <- first
+	messageCh <- second
+	messageCh <- nil
+	if ctx, _ := store.Read(first.ID()); ctx.Status != core.CtxStatusWaiting {
+		t.Fatalf("status = %v, want waiting", ctx.Status)
+	}
+
+	// the order starts over pending without the signature of the anchor
+	// which saw it leave the origin chain
+	messageCh <- &core.RemovedCtx{Ctx: first}
+	messageCh <- nil
+	ctx, err := store.Read(first.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Status != core.CtxStatusPending || ctx.SignaturesLength() != 1 || v.PendingCount() != 1 {
+		t.Fatalf("status = %v with %d signatures, want pending with 1", ctx.Status, ctx.SignaturesLength())
+	}
+
+	messageCh <- &core.RemovedCtx{Ctx: second}
+	messageCh <- nil
+	if store.Has(first.ID()) {
+		t.Fatal("removed ctx kept")
+	}
+}
+
+func TestViewer_ScanRetry(t *testing.T) {
+	chain := &chainStandIn{}
+	chain.mine(4, 0)
+	v, eventCh := newScanViewer(t, chain)
+	made := common.HexToHash("0x01")
+	chain.emit(t, 2, "MakerTx", made, big.NewInt(1e18), big.NewInt(1e17), "0x01", "", uint8(5), []byte{})
+
+	// a range failing after its logs are read leaves nothing behind
+	chain.failTip = 1
+	if _, err := v.scan(); err == nil {
+		t.Fatal("scan succeeded without the tip header")
+	}
+	if v.Height() != 1 || v.LocalStore.Has(made) || v.LocalStore.Get("currentHeight") != 0 {
+		t.Fatalf("failed scan advanced to %d", v.Height())
+	}
+	if events := drain(eventCh); len(events) != 0 {
+		t.Fatalf("failed scan sent %d messages", len(events))
+	}
+
+	// the range is scanned once by the retry
+	chain.failTip = 1
+	if v.GetEvents() {
+		t.Fatal("blocks left to scan")
+	}
+	if v.Height() != 4 || v.LocalStore.Get("currentHeight") != 4 || !v.LocalStore.Has(made) {
+		t.Fatalf("height = %d, checkpoint %d", v.Height(), v.LocalStore.Get("currentHeight"))
+	}
+	if events := drain(eventCh); len(events) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(events))
+	}
+}
@@ -0,0 +1,135 @@
+package chainview
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/simplechain-org/go-simplechain"
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/core/types"
+	"github.com/simplechain-org/go-simplechain/ethclient"
+	"github.com/simplechain-org/go-simplechain/log"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+const (
+	// subscribeBackoff is the wait before the first reconnect, doubled up to subscribeBackoffMax
+	subscribeBackoff    = time.Second
+	subscribeBackoffMax = time.Minute
+)
+
+var errSubscriptionClosed = errors.New("subscription closed")
+
+// subscriber pushes the logs of the cross contract over eth_subscribe, every
+// notification wakes the viewer to scan at once. The scan itself still reads
+// the confirmed range from the scan cursor, so the blocks missed while the
+// subscription was down are back-filled after a reconnect, and the polling
+// ticker keeps the viewer going in the meantime.
+type subscriber struct {
+	endpoint      string
+	contract      common.Address
+	confirmations uint64
+	wake          func()
+}
+
+func newSubscriber(endpoint string, contract common.Address, confirmations uint64, wake func()) *subscriber {
+	return &subscriber{
+		endpoint:      endpoint,
+		contract:      contract,
+		confirmations: confirmations,
+		wake:          wake,
+	}
+}
+
+// run keeps the subscription up until ctx is done.
+func (s *subscriber) run(ctx context.Context) {
+	backoff := subscribeBackoff
+	for {
+		start := time.Now()
+		err := s.subscribe(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		// a subscription that stayed up for a while starts over from the first backoff
+		if time.Since(start) > subscribeBackoffMax {
+			backoff = subscribeBackoff
+		}
+		log.Warn("log subscription down, polling until reconnected", "endpoint", s.endpoint, "retry", backoff, "err", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > subscribeBackoffMax {
+			backoff = subscribeBackoffMax
+		}
+	}
+}
+
+// subscribe serves one subscription, it returns when the subscription fails.
+func (s *subscriber) subscribe(ctx context.Context) error {
+	client, err := rpc.DialContext(ctx, s.endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	ec := ethclient.NewClient(client)
+
+	logs := make(chan types.Log, 128)
+	logSub, err := ec.SubscribeFilterLogs(ctx, simplechain.FilterQuery{Addresses: []common.Address{s.contract}}, logs)
+	if err != nil {
+		return err
+	}
+	defer logSub.Unsubscribe()
+
+	// pushed logs are scanned once their block has enough confirmations
+	var (
+		heads   chan *types.Header
+		headErr <-chan error
+	)
+	if s.confirmations > 0 {
+		heads = make(chan *types.Header, 16)
+		headSub, err := ec.SubscribeNewHead(ctx, heads)
+		if err != nil {
+			return err
+		}
+		defer headSub.Unsubscribe()
+		headErr = headSub.Err()
+	}
+	log.Info("log subscription up", "endpoint", s.endpoint, "contract", s.contract.String())
+
+	// back-fill the blocks missed while the subscription was down
+	s.wake()
+
+	var pending uint64 // highest block of the pushed logs waiting for confirmations
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-logSub.Err():
+			if err == nil {
+				err = errSubscriptionClosed
+			}
+			return err
+		case err := <-headErr:
+			if err == nil {
+				err = errSubscriptionClosed
+			}
+			return err
+		case l := <-logs:
+			log.Debug("log pushed", "number", l.BlockNumber, "tx", l.TxHash.String(), "removed", l.Removed)
+			// removed logs are handled by the reorg check of the scan
+			if s.confirmations == 0 || l.Removed {
+				s.wake()
+			} else if l.BlockNumber > pending {
+				pending = l.BlockNumber
+			}
+		case head := <-heads:
+			if pending != 0 && head.Number.Uint64() >= pending+s.confirmations {
+				pending = 0
+				s.wake()
+			}
+		}
+	}
+}
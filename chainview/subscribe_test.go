@@ -0,0 +1,186 @@
+package chainview
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/core/types"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+// ethStandIn serves the eth_subscribe notifications pushed by the test.
+type ethStandIn struct {
+	logs  chan types.Log
+	heads chan *types.Header
+}
+
+func (s *ethStandIn) Logs(ctx context.Context, crit map[string]interface{}) (*rpc.Subscription, error) {
+	return s.push(ctx, func(notifier *rpc.Notifier, id rpc.ID) bool {
+		select {
+		case l := <-s.logs:
+			return notifier.Notify(id, l) == nil
+		case <-time.After(10 * time.Millisecond):
+			return true
+		}
+	})
+}
+
+func (s *ethStandIn) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	return s.push(ctx, func(notifier *rpc.Notifier, id rpc.ID) bool {
+		select {
+		case h := <-s.heads:
+			return notifier.Notify(id, h) == nil
+		case <-time.After(10 * time.Millisecond):
+			return true
+		}
+	})
+}
+
+func (s *ethStandIn) push(ctx context.Context, next func(*rpc.Notifier, rpc.ID) bool) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for {
+			select {
+			case <-sub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			default:
+			}
+			if !next(notifier, sub.ID) {
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// connListener keeps the accepted connections to drop them, websocket
+// connections are hijacked and left alone by httptest.
+type connListener struct {
+	net.Listener
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.mu.Lock()
+		l.conns = append(l.conns, conn)
+		l.mu.Unlock()
+	}
+	return conn, err
+}
+
+func (l *connListener) drop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, conn := range l.conns {
+		conn.Close()
+	}
+	l.conns = nil
+}
+
+func startStandIn(t *testing.T) (*ethStandIn, *connListener, string) {
+	standIn := &ethStandIn{
+		logs:  make(chan types.Log),
+		heads: make(chan *types.Header),
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", standIn); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewUnstartedServer(server.WebsocketHandler([]string{"*"}))
+	listener := &connListener{Listener: ts.Listener}
+	ts.Listener = listener
+	ts.Start()
+	t.Cleanup(func() {
+		listener.drop()
+		ts.Close()
+		server.Stop()
+	})
+	return standIn, listener, "ws://" + strings.TrimPrefix(ts.URL, "http://")
+}
+
+func runSubscriber(t *testing.T, endpoint string, confirmations uint64) <-chan struct{} {
+	wakes := make(chan struct{}, 16)
+	s := newSubscriber(endpoint, common.HexToAddress("0x737217d6768E96fee112c16a0A9DcF0af5d56979"), confirmations, func() {
+		wakes <- struct{}{}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go s.run(ctx)
+	return wakes
+}
+
+func expectWake(t *testing.T, wakes <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-wakes:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no wake: %s", msg)
+	}
+}
+
+func expectNoWake(t *testing.T, wakes <-chan struct{}, msg string) {
+	t.Helper()
+	select {
+	case <-wakes:
+		t.Fatalf("unexpected wake: %s", msg)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func testLog(number uint64) types.Log {
+	return types.Log{
+		Address:     common.HexToAddress("0x737217d6768E96fee112c16a0A9DcF0af5d56979"),
+		Topics:      []common.Hash{},
+		Data:        []byte{},
+		BlockNumber: number,
+	}
+}
+
+func TestSubscriber_Wake(t *testing.T) {
+	standIn, _, endpoint := startStandIn(t)
+	wakes := runSubscriber(t, endpoint, 0)
+
+	expectWake(t, wakes, "back-fill after subscribe")
+	standIn.logs <- testLog(10)
+	expectWake(t, wakes, "pushed log")
+}
+
+func TestSubscriber_Confirmations(t *testing.T) {
+	standIn, _, endpoint := startStandIn(t)
+	wakes := runSubscriber(t, endpoint, 2)
+
+	expectWake(t, wakes, "back-fill after subscribe")
+	standIn.logs <- testLog(10)
+	expectNoWake(t, wakes, "log not confirmed")
+	standIn.heads <- &types.Header{Number: big.NewInt(11), Difficulty: big.NewInt(1)}
+	expectNoWake(t, wakes, "log confirmed once")
+	standIn.heads <- &types.Header{Number: big.NewInt(12), Difficulty: big.NewInt(1)}
+	expectWake(t, wakes, "log confirmed")
+}
+
+func TestSubscriber_Reconnect(t *testing.T) {
+	standIn, listener, endpoint := startStandIn(t)
+	wakes := runSubscriber(t, endpoint, 0)
+
+	expectWake(t, wakes, "back-fill after subscribe")
+	listener.drop()
+	expectWake(t, wakes, "back-fill after reconnect")
+	standIn.logs <- testLog(11)
+	expectWake(t, wakes, "pushed log after reconnect")
+}
@@ -1,17 +1,14 @@
 package main
 
 import (
-	"bytes"
-	"context"
 	"flag"
 	"fmt"
-	"log"
-	"math/big"
+	"math"
+
+	"github.com/simplechain-org/crosshub/contract/crossdemo"
 
-	"github.com/simplechain-org/go-simplechain/accounts/abi"
 	"github.com/simplechain-org/go-simplechain/common"
-	"github.com/simplechain-org/go-simplechain/common/hexutil"
-	"github.com/simplechain-org/go-simplechain/params"
+	"github.com/simplechain-org/go-simplechain/ethclient"
 	"github.com/simplechain-org/go-simplechain/rpc"
 )
 
@@ -20,7 +17,7 @@ var (
 
 	contract = flag.String("contract", "0xAa22934Df3867B8d59574dD4557ef1BA6dA2f8f3", "合约地址")
 
-	chainId = flag.Uint64("chainId", 512, "目的链id")
+	chainId = flag.Uint("chainId", 5, "目的链id")
 
 	fromVar = flag.String("from", "0x7964576407c299ec0e65991ba74019d622316a0d", "发起人地址")
 
@@ -30,85 +27,29 @@ var (
 	anchor2 = flag.String("anchor2", "0x90185B43E0B1ed1875Ec5FdC3A4AC2A7934EcF24", "锚定节点名单")
 )
 
-type SendTxArgs struct {
-	From     common.Address  `json:"from"`
-	To       *common.Address `json:"to"`
-	Gas      *hexutil.Uint64 `json:"gas"`
-	GasPrice *hexutil.Big    `json:"gasPrice"`
-	Value    *hexutil.Big    `json:"value"`
-	Nonce    *hexutil.Uint64 `json:"nonce"`
-	Data     *hexutil.Bytes  `json:"data"`
-	Input    *hexutil.Bytes  `json:"input"`
-}
-
 func AddAnchors(client *rpc.Client) {
-
-	data, err := hexutil.Decode(params.CrossDemoAbi)
-
-	if err != nil {
-		fmt.Println(err)
+	if *chainId > math.MaxUint8 {
+		fmt.Println("chainId must fit in uint8")
 		return
 	}
-
-	from := common.HexToAddress(*fromVar)
-
-	to := common.HexToAddress(*contract)
-
-	gas := hexutil.Uint64(*gaslimitVar)
-
-	price := hexutil.Big(*big.NewInt(1e9))
-
-	value := hexutil.Big(*big.NewInt(0))
-
-	abi, err := abi.JSON(bytes.NewReader(data))
-
-	if err != nil {
-		log.Fatalln(err)
-	}
-	////想要随机，请设置随机种子
-	//rand.Seed(time.Now().UnixNano())
-	//
-	////这个值必须改变，因为已经限定合约中，计算出来的txid不能相同
-	//nonce:=big.NewInt(0).SetUint64(rand.Uint64())
-	//nonce:=big.NewInt(0).SetUint64(9536605289005490782)
-
-	remoteChainId := big.NewInt(0).SetUint64(*chainId)
-
-	anchorA := common.HexToAddress(*anchor1)
-
-	anchorB := common.HexToAddress(*anchor2)
-
-	var anchors []common.Address
-	anchors = append(anchors, anchorA)
-	anchors = append(anchors, anchorB)
-
-	out, err := abi.Pack("addAnchors", remoteChainId, anchors)
+	cross, err := crossdemo.NewCrossDemo(common.HexToAddress(*contract), ethclient.NewClient(client))
 	if err != nil {
-		log.Fatalln(err)
-	}
-	input := hexutil.Bytes(out)
-
-	fmt.Println("input=", input)
-
-	args := &SendTxArgs{
-		From:     from,
-		To:       &to,
-		Gas:      &gas,
-		GasPrice: &price,
-		Value:    &value,
-		Input:    &input,
+		fmt.Println(err)
+		return
 	}
 
-	var result common.Hash
+	opts := crossdemo.NodeTransactOpts(client, common.HexToAddress(*fromVar))
+	opts.GasLimit = *gaslimitVar
 
-	err = client.CallContext(context.Background(), &result, "eth_sendTransaction", args)
+	anchors := []common.Address{common.HexToAddress(*anchor1), common.HexToAddress(*anchor2)}
 
+	tx, err := cross.AddAnchors(opts, uint8(*chainId), anchors)
 	if err != nil {
-		fmt.Println("CallContext", "err", err)
+		fmt.Println("AddAnchors", "err", err)
 		return
 	}
 
-	fmt.Println("result=", result.Hex())
+	fmt.Println("result=", tx.Hash().Hex())
 }
 
 //跨链交易发起人
@@ -2,19 +2,27 @@ package main
 
 import (
 	"fmt"
+	"github.com/simplechain-org/crosshub/api"
 	"github.com/simplechain-org/crosshub/chainview"
+	"github.com/simplechain-org/crosshub/core"
 	"github.com/simplechain-org/crosshub/fabric/courier"
 	"github.com/simplechain-org/crosshub/fabric/courier/client"
 	"github.com/simplechain-org/crosshub/fabric/courier/utils"
+	"github.com/simplechain-org/crosshub/registry"
 	"github.com/simplechain-org/crosshub/repo"
 	"github.com/simplechain-org/crosshub/swarm"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
-	"sync"
+	"strings"
 	"syscall"
 
 	"github.com/simplechain-org/go-simplechain/crypto/ecdsa"
 	"github.com/simplechain-org/go-simplechain/log"
+	"github.com/simplechain-org/go-simplechain/metrics"
+	"github.com/simplechain-org/go-simplechain/metrics/prometheus"
+	"github.com/simplechain-org/go-simplechain/rpc"
 	"github.com/urfave/cli"
 )
 
@@ -27,7 +35,7 @@ func startCMD() cli.Command {
 }
 
 func start(ctx *cli.Context) error {
-	var stop = make(chan os.Signal)
+	var stop = make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM)
 	signal.Notify(stop, syscall.SIGINT)
 	log.Info("start")
@@ -43,6 +51,15 @@ func start(ctx *cli.Context) error {
 		return fmt.Errorf("repo load: %w", err)
 	}
 
+	// metrics are created with the adapters, they are stubs unless enabled first
+	metrics.Enabled = repo.Config.Port.Metrics != 0
+
+	reg, err := registry.New(repo.Config)
+	if err != nil {
+		log.Error("registry.New", "err", err)
+		return fmt.Errorf("chain registry: %w", err)
+	}
+
 	//eventCh := make(chan *core.CrossTransaction, 4096)
 	//rtxCh  := make(chan *core.ReceptTransaction,4096)
 	eventCh := make(chan interface{}, 4096)
@@ -58,56 +75,305 @@ func start(ctx *cli.Context) error {
 		return err
 	}
 
-	switch repo.Config.Role {
-	case 1:
-		var wg sync.WaitGroup
-		wg.Add(1)
-		v, err := chainview.New(repo, eventCh, messageCh)
-		if err != nil {
-			log.Error("chainview.New", "err", err)
-			return err
+	// one adapter per chain section, messages from the swarm are routed to the
+	// adapter of their purpose
+	var (
+		stops    []func()
+		routes   = make(map[uint8]chan interface{})
+		crossApi = api.NewPublicCrossQueryApi()
+		couriers = make(map[uint8]api.CourierSource)
+		done     = make(chan struct{})
+	)
+	stopAll := func() {
+		close(done)
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
 		}
+	}
 
-		if err := v.Start(); err != nil {
-			log.Error("s.Start", "err", err)
-			return err
+	for _, chain := range reg.Locals() {
+		key, err := chainKey(repo, chain)
+		if err != nil {
+			stopAll()
+			return fmt.Errorf("chain %d key: %w", chain.Purpose, err)
 		}
+		recvCh := make(chan interface{}, 4096)
+		routes[chain.Purpose] = recvCh
 
-		go func() {
-			<-stop
-			fmt.Println("received interrupt signal, shutting down...")
-			v.Stop()
-			wg.Done()
-			os.Exit(0)
-		}()
-		wg.Wait()
-	default:
-		// set utils.log level
-		utils.Verbosity(repo.Config.Fabric.LogLevel)
-
-		courierHandler, err := courier.New(client.InitConfig(repo.Config.Fabric), &courier.CrossChannel{
-			SendCh: eventCh,
-			RecvCh: messageCh,
-		})
+		switch chain.Type {
+		case registry.Simplechain:
+			v, err := chainview.New(reg, chain, key, eventCh, recvCh)
+			if err != nil {
+				log.Error("chainview.New", "chain", chain, "err", err)
+				stopAll()
+				return err
+			}
+			crossApi.AddChain(chain.Purpose, v.LocalStore, v.RemoteStores)
+			crossApi.SetAnchorSource(chain.Purpose, v)
+			crossApi.SetElectionSource(chain.Purpose, v)
+			crossApi.SetStatusSource(chain.Purpose, v)
+			crossApi.SetAdminSource(chain.Purpose, v)
 
-		if err != nil {
-			log.Error("[courier.Handler] new handler", "err", err)
-			return err
+			if err := v.Start(); err != nil {
+				log.Error("v.Start", "chain", chain, "err", err)
+				stopAll()
+				return err
+			}
+			stops = append(stops, func() { v.Stop() })
+
+		case registry.Fabric:
+			courierHandler, err := startCourier(reg, chain, key, eventCh, recvCh)
+			if err != nil {
+				log.Error("[courier.Handler] new handler", "chain", chain, "err", err)
+				stopAll()
+				return err
+			}
+			couriers[chain.Purpose] = courierHandler
+			stops = append(stops, courierHandler.Stop)
 		}
+		log.Info("adapter started", "chain", chain)
+	}
 
-		// set private key
-		courierHandler.SetPrivateKey(repo.Key.PrivKey.(*ecdsa.PrivateKey))
-		// accept cross request from simplechain
-		utils.Logger.Info("[courier.Handler] enable outchain flag", "outchain", repo.Config.Fabric.Outchain)
-		courierHandler.SetOutChainFlag(repo.Config.Fabric.Outchain)
+	if len(reg.Locals()) == 0 {
+		log.Warn("no chain adapter started")
+	}
 
-		courierHandler.Start()
-		defer courierHandler.Stop()
-		<-stop
-		os.Exit(0)
+	crossApi.SetPeerSource(s)
+	auth, err := api.NewAuth(repo.Config.Auth, repo.Config.Admin.Token)
+	if err != nil {
+		stopAll()
+		return fmt.Errorf("auth: %w", err)
+	}
+	if err := startAPI(repo.Config, auth, crossApi, api.NewAdminApi(crossApi, couriers)); err != nil {
+		stopAll()
+		return err
+	}
+	if err := startGateway(repo.Config, auth, crossApi); err != nil {
+		stopAll()
+		return err
+	}
+	if err := startMetrics(repo.Config); err != nil {
+		stopAll()
+		return err
 	}
+	stopGRPC, err := startGRPC(repo, auth, crossApi)
+	if err != nil {
+		stopAll()
+		return err
+	}
+	stops = append(stops, stopGRPC)
+
+	go route(messageCh, routes, done)
+
+	<-stop
+	fmt.Println("received interrupt signal, shutting down...")
+	stopAll()
 
 	//log.Info("new config", "store", repo.Config.Fabric)
 	//fabricView.New(repo,eventCh)
 	return nil
 }
+
+// chainKey returns the signing key of the chain, the node key is used if the
+// chain section has no key.
+func chainKey(r *repo.Repo, chain *registry.Chain) (*ecdsa.PrivateKey, error) {
+	if chain.Key == "" {
+		return r.Key.PrivKey.(*ecdsa.PrivateKey), nil
+	}
+	return repo.LoadChainKey(r.Config.RepoRoot, chain.Key)
+}
+
+func startCourier(reg *registry.Registry, chain *registry.Chain, key *ecdsa.PrivateKey, sendCh, recvCh chan interface{}) (*courier.Handler, error) {
+	target, err := courierTarget(reg, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	// set utils.log level
+	utils.Verbosity(chain.Fabric.LogLevel)
+
+	courierHandler, err := courier.New(client.InitConfig(chain.Fabric), &courier.CrossChannel{
+		SendCh: sendCh,
+		RecvCh: recvCh,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	courierHandler.SetChains(reg, chain, target)
+	courierHandler.SetStartHeight(chain.StartHeight)
+	// set private key
+	courierHandler.SetPrivateKey(key)
+	// accept cross request from simplechain
+	utils.Logger.Info("[courier.Handler] enable outchain flag", "outchain", chain.Fabric.Outchain)
+	courierHandler.SetOutChainFlag(chain.Fabric.Outchain)
+
+	courierHandler.Start()
+	return courierHandler, nil
+}
+
+// courierTarget returns the chain taking the cross transactions made on the
+// fabric chain, it defaults to the first simplechain.
+func courierTarget(reg *registry.Registry, chain *registry.Chain) (*registry.Chain, error) {
+	if chain.Fabric.Target != 0 {
+		return reg.Chain(chain.Fabric.Target)
+	}
+	for _, c := range reg.Chains() {
+		if c.Type == registry.Simplechain {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("chain %d: no %s chain to take its cross transactions", chain.Purpose, registry.Simplechain)
+}
+
+// startAPI serves the cross namespace to the read credentials of auth and the
+// admin namespace at /admin to its admin credentials.
+func startAPI(cfg *repo.Config, auth *api.Auth, crossApi *api.CrossQueryApi, adminApi *api.AdminApi) error {
+	var queryApi api.CrossApi = crossApi
+	server := rpc.NewServer()
+	if err := server.RegisterName("cross", queryApi); err != nil {
+		return fmt.Errorf("could not register RPC api: %w", err)
+	}
+	port := cfg.JsonRpc
+	if port == 0 {
+		// configs before the gRPC service serve json-rpc on the grpc port
+		port = cfg.Grpc
+	}
+	endpoint := fmt.Sprintf("0.0.0.0:%d", port)
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return fmt.Errorf("could not start RPC api: %w", err)
+	}
+	// http and websocket requests share the port, subscriptions need websocket
+	httpServer := rpc.NewHTTPServer(cfg.AllowedOrigins, []string{"*"}, rpc.DefaultHTTPTimeouts, auth.Handler(api.RoleRead, server))
+	handler := rpcHandler(httpServer.Handler, auth.WebsocketHandler(api.RoleRead, server, cfg.AllowedOrigins))
+	if auth.Grants(api.RoleAdmin) {
+		admin, err := api.NewAdminHandler(adminApi)
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("could not register admin api: %w", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/admin", auth.Handler(api.RoleAdmin, admin))
+		mux.Handle("/", handler)
+		handler = mux
+		log.Info("admin endpoint opened", "url", fmt.Sprintf("http://%s/admin", endpoint))
+	}
+	httpServer.Handler = handler
+	go httpServer.Serve(listener)
+	log.Info("RPC endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "ws", fmt.Sprintf("ws://%s", endpoint))
+	return nil
+}
+
+// startGRPC serves the gRPC services of the api on the grpc port with mutual
+// TLS and the credentials of auth, it is disabled while json-rpc is served on
+// the grpc port.
+func startGRPC(r *repo.Repo, auth *api.Auth, crossApi *api.CrossQueryApi) (func(), error) {
+	if r.Config.JsonRpc == 0 {
+		log.Warn("gRPC disabled, the grpc port serves json-rpc until port.jsonrpc is set", "port", r.Config.Grpc)
+		return func() {}, nil
+	}
+	endpoint := fmt.Sprintf("0.0.0.0:%d", r.Config.Grpc)
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not start gRPC: %w", err)
+	}
+	server := api.NewGRPCServer(crossApi, r.Certs, r.Key.PrivKey.(*ecdsa.PrivateKey).K, auth)
+	go server.Serve(listener)
+	log.Info("gRPC endpoint opened", "addr", endpoint)
+	return server.Stop, nil
+}
+
+// startGateway serves the REST gateway of the api on the gateway port, a zero
+// port disables it.
+func startGateway(cfg *repo.Config, auth *api.Auth, crossApi *api.CrossQueryApi) error {
+	if cfg.Port.Gateway == 0 {
+		return nil
+	}
+	endpoint := fmt.Sprintf("0.0.0.0:%d", cfg.Port.Gateway)
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return fmt.Errorf("could not start gateway: %w", err)
+	}
+	server := &http.Server{
+		Handler:      api.NewGateway(crossApi, cfg.Gateway.AllowedOrigins, auth),
+		ReadTimeout:  rpc.DefaultHTTPTimeouts.ReadTimeout,
+		WriteTimeout: rpc.DefaultHTTPTimeouts.WriteTimeout,
+		IdleTimeout:  rpc.DefaultHTTPTimeouts.IdleTimeout,
+	}
+	go server.Serve(listener)
+	log.Info("gateway opened", "url", fmt.Sprintf("http://%s/v1", endpoint),
+		"openapi", fmt.Sprintf("http://%s/v1/openapi.json", endpoint))
+	return nil
+}
+
+// startMetrics serves the prometheus metrics on the metrics port, a zero port
+// disables them.
+func startMetrics(cfg *repo.Config) error {
+	if cfg.Port.Metrics == 0 {
+		return nil
+	}
+	endpoint := fmt.Sprintf("0.0.0.0:%d", cfg.Port.Metrics)
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return fmt.Errorf("could not start metrics: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", prometheus.Handler(metrics.DefaultRegistry))
+	server := &http.Server{
+		Handler:      mux,
+		ReadTimeout:  rpc.DefaultHTTPTimeouts.ReadTimeout,
+		WriteTimeout: rpc.DefaultHTTPTimeouts.WriteTimeout,
+		IdleTimeout:  rpc.DefaultHTTPTimeouts.IdleTimeout,
+	}
+	go server.Serve(listener)
+	log.Info("metrics opened", "url", fmt.Sprintf("http://%s/metrics", endpoint))
+	return nil
+}
+
+// rpcHandler serves the websocket upgrade requests by ws and the others by http.
+func rpcHandler(rpcHTTP, ws http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		rpcHTTP.ServeHTTP(w, r)
+	})
+}
+
+// route delivers the ctx and rtx received from other hubs to the adapter of
+// the chain they are sent to.
+func route(messageCh <-chan interface{}, routes map[uint8]chan interface{}, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case ev := <-messageCh:
+			var purpose uint8
+			switch msg := ev.(type) {
+			case *core.CrossTransaction:
+				purpose = msg.Data.Purpose
+			case *core.ReceptTransaction:
+				purpose = msg.Data.Purpose
+			case *core.RemovedCtx:
+				purpose = msg.Ctx.Data.Purpose
+			case *core.RevertedRtx:
+				purpose = msg.Rtx.Data.Purpose
+			default:
+				log.Warn("discard unsupported message", "type", fmt.Sprintf("%T", ev))
+				continue
+			}
+			ch, ok := routes[purpose]
+			if !ok {
+				log.Debug("discard message of unserved chain", "purpose", purpose)
+				continue
+			}
+			select {
+			case ch <- ev:
+			case <-done:
+				return
+			}
+		}
+	}
+}
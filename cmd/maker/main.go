@@ -1,17 +1,15 @@
 package main
 
 import (
-	"bytes"
-	"context"
 	"flag"
 	"fmt"
-	"github.com/simplechain-org/crosshub/chainview"
-	"log"
+	"math"
 	"math/big"
 
-	"github.com/simplechain-org/go-simplechain/accounts/abi"
+	"github.com/simplechain-org/crosshub/contract/crossdemo"
+
 	"github.com/simplechain-org/go-simplechain/common"
-	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/ethclient"
 	"github.com/simplechain-org/go-simplechain/rpc"
 )
 
@@ -35,17 +33,6 @@ var (
 	countTx = flag.Int("count", 1, "交易数")
 )
 
-type SendTxArgs struct {
-	From     common.Address  `json:"from"`
-	To       *common.Address `json:"to"`
-	Gas      *hexutil.Uint64 `json:"gas"`
-	GasPrice *hexutil.Big    `json:"gasPrice"`
-	Value    *hexutil.Big    `json:"value"`
-	Nonce    *hexutil.Uint64 `json:"nonce"`
-	Data     *hexutil.Bytes  `json:"data"`
-	Input    *hexutil.Bytes  `json:"input"`
-}
-
 //跨链交易发起人
 func main() {
 	flag.Parse()
@@ -53,53 +40,36 @@ func main() {
 }
 
 func maker() {
+	if *chainId > math.MaxUint8 {
+		fmt.Println("chainId must fit in uint8")
+		return
+	}
 	client, err := rpc.Dial(*rawurlVar)
 	if err != nil {
 		fmt.Println("dial", "err", err)
 		return
 	}
-	data, err := hexutil.Decode(chainview.CrossAbi)
+	cross, err := crossdemo.NewCrossDemo(common.HexToAddress(*contract), ethclient.NewClient(client))
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	from := common.HexToAddress(*fromVar)
 	//focusAddr := common.HexToAddress(*focusVar)
-	to := common.HexToAddress(*contract)
-	gas := hexutil.Uint64(*gaslimitVar)
-	value := hexutil.Big(*new(big.Int).SetUint64(*value))
-	price := hexutil.Big(*big.NewInt(1e9))
-
-	abi, err := abi.JSON(bytes.NewReader(data))
-	if err != nil {
-		log.Fatalln(err)
-	}
+	opts := crossdemo.NodeTransactOpts(client, common.HexToAddress(*fromVar))
+	opts.GasLimit = *gaslimitVar
+	opts.GasPrice = big.NewInt(1e9)
+	opts.Value = new(big.Int).SetUint64(*value)
 
 	des := new(big.Int).SetUint64(*destValue)
 
-	//out, err := abi.Pack("makerStart",remoteChainId ,des,[]byte("In the end, it’s not the years in your life that count. It’s the life in your years."))
-	out, err := abi.Pack("makerStart", des, uint8(*chainId), []string{"b",""}, []byte{})
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	input := hexutil.Bytes(out)
-
 	for i := 0; i < *countTx; i++ {
-		var result common.Hash
-		if err = client.CallContext(context.Background(), &result, "eth_sendTransaction", &SendTxArgs{
-			From:     from,
-			To:       &to,
-			Gas:      &gas,
-			GasPrice: &price,
-			Value:    &value,
-			Input:    &input,
-		}); err != nil {
-			fmt.Println("CallContext", "err", err)
+		tx, err := cross.MakerStart(opts, des, uint8(*chainId), [2]string{"b", ""}, []byte{})
+		if err != nil {
+			fmt.Println("MakerStart", "err", err)
 			return
 		}
 
-		fmt.Println("result=", result.Hex())
+		fmt.Println("result=", tx.Hash().Hex())
 	}
 }
@@ -13,6 +13,7 @@ import (
 var rawurlVar = flag.String("rawurl", "http://127.0.0.1:8556", "rpc url")
 var pageSize = flag.Uint64("limit", 200, "每页条数")
 var pageNum = flag.Uint64("page", 1, "页数")
+var order = flag.String("order", "price", "排序方式: price, value, insertion，加-前缀为降序")
 
 type RPCCrossTransaction struct {
 	Value            *hexutil.Big   `json:"Value"`
@@ -29,8 +30,8 @@ type RPCCrossTransaction struct {
 }
 
 type RPCPageCrossTransactions struct {
-	Data map[uint64][]*RPCCrossTransaction `json:"data"`
-	//Total int                               `json:"total"`
+	Data  map[uint64][]*RPCCrossTransaction `json:"data"`
+	Total int                               `json:"total"`
 }
 
 func main() {
@@ -47,11 +48,12 @@ func query() {
 
 	var signatures map[string]RPCPageCrossTransactions
 	if err = client.CallContext(context.Background(), &signatures,
-		"cross_ctxContentByPage", 0, 0, pageSize, pageNum); err != nil {
+		"cross_ctxContentByPage", 0, 0, pageSize, pageNum, order); err != nil {
 		fmt.Println("CallContext", "err", err)
 		return
 	}
 
+	fmt.Println("remote total=", signatures["remote"].Total)
 	for remoteId, value := range signatures["remote"].Data {
 		for i, v := range value {
 			fmt.Println("remoteId=", remoteId, " i=", i, " hash=", v.TxHash.String())
@@ -1,17 +1,15 @@
 package main
 
 import (
-	"bytes"
-	"context"
 	"flag"
 	"fmt"
-	"log"
+	"math"
 	"math/big"
 
-	"github.com/simplechain-org/go-simplechain/accounts/abi"
+	"github.com/simplechain-org/crosshub/contract/crossdemo"
+
 	"github.com/simplechain-org/go-simplechain/common"
-	"github.com/simplechain-org/go-simplechain/common/hexutil"
-	"github.com/simplechain-org/go-simplechain/params"
+	"github.com/simplechain-org/go-simplechain/ethclient"
 	"github.com/simplechain-org/go-simplechain/rpc"
 )
 
@@ -22,7 +20,9 @@ var (
 
 	signConfirm = flag.Uint64("signConfirm", 2, "最小锚定节点数")
 
-	chainId = flag.Uint64("chainId", 512, "目的链id")
+	chainId = flag.Uint("chainId", 5, "目的链id")
+
+	router = flag.String("router", "", "目的链路由")
 
 	fromVar = flag.String("from", "0x7964576407c299ec0e65991ba74019d622316a0d", "发起人地址")
 
@@ -33,17 +33,6 @@ var (
 	anchor3 = flag.String("anchor3", "0x935d0d6851c8db45C75D2DD66A630db22A1a918A", "锚定节点名单")
 )
 
-type SendTxArgs struct {
-	From     common.Address  `json:"from"`
-	To       *common.Address `json:"to"`
-	Gas      *hexutil.Uint64 `json:"gas"`
-	GasPrice *hexutil.Big    `json:"gasPrice"`
-	Value    *hexutil.Big    `json:"value"`
-	Nonce    *hexutil.Uint64 `json:"nonce"`
-	Data     *hexutil.Bytes  `json:"data"`
-	Input    *hexutil.Bytes  `json:"input"`
-}
-
 //注册子链信息
 func main() {
 	flag.Parse()
@@ -51,57 +40,34 @@ func main() {
 }
 
 func register() {
+	if *chainId > math.MaxUint8 || *signConfirm > math.MaxUint8 {
+		fmt.Println("chainId and signConfirm must fit in uint8")
+		return
+	}
 	client, err := rpc.Dial(*rawurlVar)
 	if err != nil {
 		fmt.Println("dial", "err", err)
 		return
 	}
-	data, err := hexutil.Decode(params.CrossDemoAbi)
-
+	cross, err := crossdemo.NewCrossDemo(common.HexToAddress(*contract), ethclient.NewClient(client))
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	from := common.HexToAddress(*fromVar)
-	to := common.HexToAddress(*contract)
-	gas := hexutil.Uint64(*gaslimitVar)
-	price := hexutil.Big(*big.NewInt(1e9))
-
-	abi, err := abi.JSON(bytes.NewReader(data))
-	if err != nil {
-		log.Fatalln(err)
-	}
-	remoteChainId := new(big.Int).SetUint64(*chainId)
+	opts := crossdemo.NodeTransactOpts(client, common.HexToAddress(*fromVar))
+	opts.GasLimit = *gaslimitVar
+	opts.GasPrice = big.NewInt(1e9)
 
 	maxValue, _ := new(big.Int).SetString("10000000000000000000000", 10)
 
-	signConfirmCount := uint8(*signConfirm)
-
 	anchors := []common.Address{common.HexToAddress(*anchor1), common.HexToAddress(*anchor2), common.HexToAddress(*anchor3)}
 
-	out, err := abi.Pack("chainRegister", remoteChainId, maxValue, signConfirmCount, anchors)
+	tx, err := cross.ChainRegister(opts, uint8(*chainId), maxValue, uint8(*signConfirm), anchors, *router)
 	if err != nil {
-		fmt.Println(err)
-		return
-	}
-
-	input := hexutil.Bytes(out)
-
-	fmt.Println("input=", input)
-
-	var result common.Hash
-	if err = client.CallContext(context.Background(), &result, "eth_sendTransaction", &SendTxArgs{
-		From:     from,
-		To:       &to,
-		Gas:      &gas,
-		GasPrice: &price,
-		Value:    nil,
-		Input:    &input,
-	}); err != nil {
-		fmt.Println("CallContext", "err", err)
+		fmt.Println("ChainRegister", "err", err)
 		return
 	}
 
-	fmt.Println("result=", result.Hex())
+	fmt.Println("result=", tx.Hash().Hex())
 }
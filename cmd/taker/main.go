@@ -1,17 +1,15 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"flag"
 	"fmt"
-	"github.com/simplechain-org/crosshub/chainview"
-	"log"
+	"github.com/simplechain-org/crosshub/contract/crossdemo"
 	"math/big"
 
-	"github.com/simplechain-org/go-simplechain/accounts/abi"
 	"github.com/simplechain-org/go-simplechain/common"
 	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/ethclient"
 	"github.com/simplechain-org/go-simplechain/rpc"
 )
 
@@ -28,17 +26,6 @@ var (
 	limit = flag.Uint64("count", 1, "接单数量")
 )
 
-type SendTxArgs struct {
-	From     common.Address  `json:"From"`
-	To       *common.Address `json:"to"`
-	Gas      *hexutil.Uint64 `json:"gas"`
-	GasPrice *hexutil.Big    `json:"gasPrice"`
-	Value    *hexutil.Big    `json:"Value"`
-	Nonce    *hexutil.Uint64 `json:"nonce"`
-	Data     *hexutil.Bytes  `json:"Data"`
-	Input    *hexutil.Bytes  `json:"input"`
-}
-
 type RPCCrossTransaction struct {
 	CTxId     common.Hash 	`json:"ctxId"`
 	TxHash    common.Hash 	`json:"txHash"`
@@ -52,9 +39,9 @@ type RPCCrossTransaction struct {
 	Payload   hexutil.Bytes	`json:"payload"`
 
 	// Signature values
-	V *hexutil.Big 		`json:"v"`
-	R *hexutil.Big 		`json:"r"`
-	S *hexutil.Big 		`json:"s"`
+	V []*hexutil.Big 	`json:"v"`
+	R []*hexutil.Big 	`json:"r"`
+	S []*hexutil.Big 	`json:"s"`
 }
 
 type RPCPageCrossTransactions struct {
@@ -62,22 +49,6 @@ type RPCPageCrossTransactions struct {
 	//Total int                               `json:"total"`
 }
 
-type Order struct {
-	TxId 	common.Hash
-	TxHash	common.Hash
- 	BlockHash common.Hash
-	Value     *big.Int
- 	Charge    *big.Int
- 	From    common.Address
- 	To   	common.Address
-    Origin  uint8
- 	Purpose uint8
-	Payload []byte
-	V                []*big.Int
-	R                [][32]byte
-	S                [][32]byte
-}
-
 var signatures map[string]RPCPageCrossTransactions
 
 func main() {
@@ -86,23 +57,12 @@ func main() {
 }
 
 func taker() {
-	data, err := hexutil.Decode(chainview.CrossAbi)
-	if err != nil {
-		fmt.Println(err)
-		return
-	}
-	abi, err := abi.JSON(bytes.NewReader(data))
-	if err != nil {
-		log.Fatalln(err)
-	}
 	//账户地址
 	from := common.HexToAddress(*fromVar)
 	//合约地址
 	//在子链上接单就要填写子链上的合约地址
 	//在主链上接单就要填写主链上的合约地址
 	to := common.HexToAddress(*contract)
-	gas := hexutil.Uint64(*gaslimitVar)
-	price := hexutil.Big(*big.NewInt(1e9))
 
 	client, err := rpc.Dial(*rawurlVar)
 	if err != nil {
@@ -123,6 +83,11 @@ func taker() {
 		fmt.Println("dial", "err", err)
 		return
 	}
+	cross, err := crossdemo.NewCrossDemo(to, ethclient.NewClient(client))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	for _, value := range signatures["remote"].Data {
 		for _, v := range value {
@@ -131,26 +96,31 @@ func taker() {
 				fmt.Printf("tx: %s need taker: %s\n", v.TxHash.String(), v.To)
 				continue
 			}
-			r := make([][32]byte, 0, 1)
-			s := make([][32]byte, 0, 1)
-			vv := make([]*big.Int, 0, 1)
+			if len(v.V) == 0 || len(v.V) != len(v.R) || len(v.V) != len(v.S) {
+				fmt.Printf("tx: %s has invalid signatures\n", v.TxHash.String())
+				continue
+			}
+			//提交所有锚定节点的签名
+			r := make([][32]byte, 0, len(v.R))
+			s := make([][32]byte, 0, len(v.S))
+			vv := make([]*big.Int, 0, len(v.V))
 
-			for i := 0; i < 1; i++ {
-				rone := common.LeftPadBytes(v.R.ToInt().Bytes(), 32)
+			for i := range v.V {
+				rone := common.LeftPadBytes(v.R[i].ToInt().Bytes(), 32)
 				var a [32]byte
 				copy(a[:], rone)
 				r = append(r, a)
-				sone := common.LeftPadBytes(v.S.ToInt().Bytes(), 32)
+				sone := common.LeftPadBytes(v.S[i].ToInt().Bytes(), 32)
 				var b [32]byte
 				copy(b[:], sone)
 				s = append(s, b)
-				vv = append(vv, v.V.ToInt())
+				vv = append(vv, v.V[i].ToInt())
 			}
 			//在调用这个函数中调用的chainId其实就是表示的是发单的链id
 			//也就是maker的源头，那条链调用了maker,这个链id就对应那条链的id
 			//chainId := big.NewInt(int64(remoteId))
 
-			ord := Order{
+			ord := crossdemo.CrossStructOrder{
 				TxId:v.CTxId,
 				TxHash:v.TxHash,
 				BlockHash:v.BlockHash,
@@ -167,28 +137,17 @@ func taker() {
 			}
 			fmt.Println(ord.To.String())
 
-			out, err := abi.Pack("taker", &ord,"b",[]byte("i am b!"))
+			opts := crossdemo.NodeTransactOpts(client, from)
+			opts.GasLimit = *gaslimitVar
+			opts.GasPrice = big.NewInt(1e9)
+			opts.Value = v.Charge.ToInt()
+			tx, err := cross.Taker(opts, ord, "b", []byte("i am b!"))
 			if err != nil {
-				fmt.Println("abi.Pack err=", err)
-				continue
-			}
-
-			input := hexutil.Bytes(out)
-
-			var result common.Hash
-			if err := client.CallContext(context.Background(), &result, "eth_sendTransaction", &SendTxArgs{
-				From:     from,
-				To:       &to,
-				Gas:      &gas,
-				GasPrice: &price,
-				Value:    v.Charge,
-				Input:    &input,
-			}); err != nil {
-				fmt.Println("SendTransaction", "err", err)
+				fmt.Println("Taker", "err", err)
 				return
 			}
 
-			fmt.Printf("eth_sendTransaction result=%s, ctxID=%s\n", result.Hex(), v.CTxId.String())
+			fmt.Printf("taker result=%s, ctxID=%s\n", tx.Hash().Hex(), v.CTxId.String())
 		}
 
 		//}
@@ -0,0 +1,1959 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package crossdemo
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/simplechain-org/go-simplechain"
+	"github.com/simplechain-org/go-simplechain/accounts/abi"
+	"github.com/simplechain-org/go-simplechain/accounts/abi/bind"
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/core/types"
+	"github.com/simplechain-org/go-simplechain/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = abi.U256
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// CrossStructOrder is an auto generated low-level Go binding around an user-defined struct.
+type CrossStructOrder struct {
+	TxId      [32]byte
+	TxHash    [32]byte
+	BlockHash [32]byte
+	Value     *big.Int
+	Charge    *big.Int
+	From      common.Address
+	To        common.Address
+	Origin    uint8
+	Purpose   uint8
+	Payload   []byte
+	V         []*big.Int
+	R         [][32]byte
+	S         [][32]byte
+}
+
+// CrossStructRecept is an auto generated low-level Go binding around an user-defined struct.
+type CrossStructRecept struct {
+	TxId    [32]byte
+	TxHash  [32]byte
+	From    string
+	To      string
+	Taker   common.Address
+	Origin  uint8
+	Purpose uint8
+	Data    []byte
+}
+
+// CrossDemoABI is the input ABI used to generate the binding from.
+const CrossDemoABI = "[{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"addresspayable\",\"name\":\"anchor\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"reward\",\"type\":\"uint256\"}],\"name\":\"accumulateRewards\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"anchor\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"reward\",\"type\":\"uint256\"}],\"name\":\"AccumulateRewards\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"address[]\",\"name\":\"_anchors\",\"type\":\"address[]\"}],\"name\":\"addAnchors\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"AddAnchors\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"maxValue\",\"type\":\"uint256\"},{\"internalType\":\"uint8\",\"name\":\"signConfirmCount\",\"type\":\"uint8\"},{\"internalType\":\"address[]\",\"name\":\"_anchors\",\"type\":\"address[]\"},{\"internalType\":\"string\",\"name\":\"router\",\"type\":\"string\"}],\"name\":\"chainRegister\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"bytes32\",\"name\":\"txId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"txHash\",\"type\":\"bytes32\"},{\"internalType\":\"string\",\"name\":\"from\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"to\",\"type\":\"string\"},{\"internalType\":\"addresspayable\",\"name\":\"taker\",\"type\":\"address\"},{\"internalType\":\"uint8\",\"name\":\"origin\",\"type\":\"uint8\"},{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"internalType\":\"structCrossStruct.Recept\",\"name\":\"rtx\",\"type\":\"tuple\"}],\"name\":\"makerFinish\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"txId\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"}],\"name\":\"MakerFinish\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"destValue\",\"type\":\"uint256\"},{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"string[2]\",\"name\":\"arg\",\"type\":\"string[2]\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"makerStart\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"txId\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"destValue\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"from\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"to\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"payload\",\"type\":\"bytes\"}],\"name\":\"MakerTx\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"address[]\",\"name\":\"_anchors\",\"type\":\"address[]\"}],\"name\":\"removeAnchors\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"RemoveAnchors\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"_anchor\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"status\",\"type\":\"bool\"}],\"name\":\"setAnchorStatus\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"SetAnchorStatus\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"maxValue\",\"type\":\"uint256\"}],\"name\":\"setMaxValue\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"_reward\",\"type\":\"uint256\"}],\"name\":\"setReward\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"uint8\",\"name\":\"count\",\"type\":\"uint8\"}],\"name\":\"setSignConfirmCount\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"bytes32\",\"name\":\"txId\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"txHash\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"blockHash\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"charge\",\"type\":\"uint256\"},{\"internalType\":\"addresspayable\",\"name\":\"from\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint8\",\"name\":\"origin\",\"type\":\"uint8\"},{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"bytes\",\"name\":\"payload\",\"type\":\"bytes\"},{\"internalType\":\"uint256[]\",\"name\":\"v\",\"type\":\"uint256[]\"},{\"internalType\":\"bytes32[]\",\"name\":\"r\",\"type\":\"bytes32[]\"},{\"internalType\":\"bytes32[]\",\"name\":\"s\",\"type\":\"bytes32[]\"}],\"internalType\":\"structCrossStruct.Order\",\"name\":\"ctx\",\"type\":\"tuple\"},{\"internalType\":\"string\",\"name\":\"to\",\"type\":\"string\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"taker\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"txId\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"taker\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"indexed\":false,\"internalType\":\"bytes\",\"name\":\"payload\",\"type\":\"bytes\"}],\"name\":\"TakerTx\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"string\",\"name\":\"_router\",\"type\":\"string\"}],\"name\":\"updateRouter\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"UpdateRouter\",\"type\":\"event\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint64\",\"name\":\"n\",\"type\":\"uint64\"}],\"name\":\"bitCount\",\"outputs\":[{\"internalType\":\"uint64\",\"name\":\"\",\"type\":\"uint64\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"chainId\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"name\":\"crossChains\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"uint8\",\"name\":\"signConfirmCount\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"maxValue\",\"type\":\"uint256\"},{\"internalType\":\"uint64\",\"name\":\"anchorsPositionBit\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"delsPositionBit\",\"type\":\"uint64\"},{\"internalType\":\"uint8\",\"name\":\"delId\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"reward\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalReward\",\"type\":\"uint256\"},{\"internalType\":\"string\",\"name\":\"router\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"getAnchors\",\"outputs\":[{\"internalType\":\"address[]\",\"name\":\"_anchors\",\"type\":\"address[]\"},{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"_anchor\",\"type\":\"address\"}],\"name\":\"getAnchorWorkCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"getChainReward\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"_anchor\",\"type\":\"address\"}],\"name\":\"getDelAnchorSignCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"txId\",\"type\":\"bytes32\"},{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"getMakerTx\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"getMaxValue\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"getRouter\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"txId\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"_from\",\"type\":\"address\"},{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"getTakerTx\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"internalType\":\"uint8\",\"name\":\"purpose\",\"type\":\"uint8\"}],\"name\":\"getTotalReward\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"list\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"ll\",\"type\":\"uint256\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]"
+
+// CrossDemoBin is the compiled bytecode used for deploying new contracts.
+var CrossDemoBin = "0x608060405234801561001057600080fd5b50600080546001600160a01b03191633179055614070806100326000396000f3fe6080604052600436106101815760003560e01c80638da5cb5b116100d1578063cb2db4ee1161008a578063d415144611610064578063d41514461461046e578063dfbfed8a1461048e578063e0db6ff6146104ae578063eb16432e146104ce57610181565b8063cb2db4ee1461040e578063cb93af0d1461042e578063d0bcc16f1461044e57610181565b80638da5cb5b1461034f5780639a8a059214610371578063a9a7c9cb14610386578063aa8a65ac146103bb578063b7df03cc146103db578063ba0193d4146103ee57610181565b806346a90a151161013e5780635f4288e1116101185780635f4288e1146102c15780636af2dbe3146102e157806380033daf14610301578063848afae81461032157610181565b806346a90a15146102615780634af4ce3c14610281578063583517461461029457610181565b8063028acd48146101865780630f560cd7146101bd578063121c439d146101df578063215240121461020c5780632feb60e61461022c578063388e7e3514610241575b600080fd5b34801561019257600080fd5b506101a66101a13660046136b6565b6104fb565b6040516101b4929190613a98565b60405180910390f35b3480156101c957600080fd5b506101d26106e5565b6040516101b49190613eb0565b3480156101eb57600080fd5b506101ff6101fa36600461368f565b6106e9565b6040516101b49190613ec7565b34801561021857600080fd5b506101d26102273660046136d1565b61072e565b61023f61023a3660046135d1565b610761565b005b34801561024d57600080fd5b5061023f61025c3660046136fe565b6109bd565b34801561026d57600080fd5b506101d261027c3660046132c0565b610b1b565b61023f61028f3660046134d3565b610b82565b3480156102a057600080fd5b506102b46102af3660046136b6565b61115a565b6040516101b49190613bd5565b3480156102cd57600080fd5b506101d26102dc3660046136b6565b611201565b3480156102ed57600080fd5b5061023f6102fc3660046138cf565b611219565b34801561030d57600080fd5b5061023f61031c366004613818565b6112f8565b34801561032d57600080fd5b5061034161033c3660046136d1565b61133b565b6040516101b4929190613eb9565b34801561035b57600080fd5b50610364611377565b6040516101b49190613a84565b34801561037d57600080fd5b506101d2611386565b34801561039257600080fd5b506103a66103a13660046136b6565b61138a565b6040516101b499989796959493929190613f0d565b3480156103c757600080fd5b5061023f6103d636600461373d565b611473565b61023f6103e936600461332d565b61164a565b3480156103fa57600080fd5b5061023f6104093660046137d6565b611ae8565b34801561041a57600080fd5b506101d26104293660046132fe565b611c1a565b34801561043a57600080fd5b5061023f610449366004613818565b611c3d565b34801561045a57600080fd5b5061023f61046936600461378a565b611d03565b34801561047a57600080fd5b5061023f61048936600461378a565b6120e3565b34801561049a57600080fd5b506101d26104a93660046136b6565b612432565b3480156104ba57600080fd5b506101d26104c93660046136b6565b61244a565b3480156104da57600080fd5b506104ee6104e9366004613842565b612463565b6040516101b49190613aef565b6060600080805b60ff80861660009081526001602052604090206003015490821610156105925760ff8086166000908152600160205260408120600381018054600490920193909190851690811061054f57fe5b60009182526020808320909101546001600160a01b0316835282019290925260400190205460ff62010000909104161561058a576001909101905b600101610502565b508060ff166040519080825280602002602001820160405280156105c0578160200160208202803683370190505b5092506000805b60ff80871660009081526001602052604090206003015490821610156106c15760ff8087166000908152600160205260408120600381018054600490920193909190851690811061061457fe5b60009182526020808320909101546001600160a01b0316835282019290925260400190205460ff6201000090910416156106b95760ff808716600090815260016020526040902060030180549091831690811061066d57fe5b9060005260206000200160009054906101000a90046001600160a01b0316858360ff168151811061069a57fe5b6001600160a01b03909216602092830291909101909101526001909101905b6001016105c7565b50505060ff8084166000908152600160205260409020546101009004169050915091565b4890565b603f600282901c67124924924924924916600183901c6736db6db6db6db6db1690920391909103600381901c671fffffffffffffff16016771c71c71c71c71c7160690565b60ff821660009081526001602081815260408084206001600160a01b038616855260070190915290912001545b92915050565b60ff831660009081526001602081905260409091200154341061079f5760405162461bcd60e51b815260040161079690613c74565b60405180910390fd5b60ff808416600090815260016020526040902054166107d05760405162461bcd60e51b815260040161079690613ddd565b6000336107db6106e5565b6107e3611386565b6040516020016107f59392919061392f565b60408051601f19818403018152918152815160209283012060ff87166000908152600184528281208282526005019093529120549091501561083357fe5b6040805160a08101825260ff86166000908152600160205291909120600b0154819061086690349063ffffffff61283816565b8152602001600060ff1681526020018460006002811061088257fe5b602002015181526020018460016002811061089957fe5b602090810291909101518252600091810182905260ff8781168352600180835260408085208786526005018452938490208551815585840151918101805460ff191692909316919091179091559183015180516108fc9260038501920190613000565b5060608201518051610918916004840191602090910190613000565b506080919091015160059091015560ff84166000908152600160205260409020600b810154600c909101546109529163ffffffff61284a16565b60ff8516600090815260016020908152604091829020600c019290925584519185015190517f7077d55391204330e8e9f714f632753f266f6bbdaa441a2b922c8ae84a4355b5926109ae92859234928b9290918b908a90613b56565b60405180910390a15050505050565b6000546001600160a01b031633146109e75760405162461bcd60e51b815260040161079690613e25565b60ff83166000908152600160205260409020600c0154811115610a1c5760405162461bcd60e51b815260040161079690613db9565b60ff80841660008181526001602090815260408083206001600160a01b038816845260040190915290205490911614610a675760405162461bcd60e51b815260040161079690613d91565b60ff83166000908152600160205260409020600c0154610a8d908263ffffffff61283816565b60ff8416600090815260016020526040808220600c019290925590516001600160a01b0384169183156108fc02918491818181858888f19350505050158015610ada573d6000803e3d6000fd5b507fa85c9df2b080602d65330640cfb34e3ee866e77820f350d723f8ffbc33e459e5838383604051610b0e93929190613ee9565b60405180910390a1505050565b60ff81166000908152600160208181526040808420878552600601909152822001546001600160a01b0384811691161415610b77575060ff81166000908152600160209081526040808320868452600601909152902054610b7b565b5060005b9392505050565b60a081015160ff80821660009081526001602052604090205416610bb85760405162461bcd60e51b815260040161079690613d02565b60ff808216600081815260016020908152604080832033845260040190915290205490911614610bfa5760405162461bcd60e51b815260040161079690613d4a565b60a082015160ff908116600090815260016020908152604080832033845260040190915290205462010000900416610c3157600080fd5b60a082015160ff9081166000908152600160208181526040808420875185526005018252808420338552600201909152909120549091161415610c7357600080fd5b60a082015160ff16600090815260016020908152604080832085518452600501909152902054610ca257600080fd5b8160400151604051602001610cb791906139e0565b60408051601f19818403018152828252805160209182012060a086015160ff16600090815260018352838120875182526005018352929092209192610d009260030191016139fc565b6040516020818303038152906040528051906020012014610d335760405162461bcd60e51b815260040161079690613e6c565b604051602001610d4290613140565b60408051601f19818403018152828252805160209182012060a086015160ff16600090815260018352838120875182526005018352929092209192610d8b9260040191016139fc565b604051602081830303815290604052805190602001201480610e1e57508160600151604051602001610dbd91906139e0565b60408051601f19818403018152828252805160209182012060a086015160ff16600090815260018352838120875182526005018352929092209192610e069260040191016139fc565b60405160208183030381529060405280519060200120145b80610e9a57508160600151604051602001610e3991906139e0565b60408051601f19818403018152828252805160209182012060a086015160ff16600090815260018352838120875182526005018352929092209192610e829260030191016139fc565b60405160208183030381529060405280519060200120145b610eb65760405162461bcd60e51b815260040161079690613c33565b60a082015160ff16600090815260016020908152604080832085518452600590810190925290912001541580610f17575060208083015160a084015160ff166000908152600183526040808220865183526005908101909452902090910154145b610f335760405162461bcd60e51b815260040161079690613be8565b60a08201805160ff908116600090815260016020818152604080842088518552600590810183528185203386526002018352818520805460ff1990811686179091558751871686528484528286208a518752820184528286208501805480891687018916921691909117905560608901519651909516845291815281832087518452909301835290208251610fd2936004909201929190910190613000565b5060208083015160a08401805160ff9081166000908152600180865260408083208951845260059081018852818420810196909655845184168352818752808320338452600401875280832060020180548301905584518416835281875280832054945184168352808320895184529095019095529290922090920154610100909204811691161061115657608082015160a083015160ff166000908152600160209081526040808320865184526005019091528082205490516001600160a01b039093169281156108fc0292818181858888f193505050501580156110bc573d6000803e3d6000fd5b5060a082015160ff166000908152600160208181526040808420865185526005019091528220828155908101805460ff19169055906110fe600383018261307e565b61110c60048301600061307e565b50600060059190910155815160808301516040517f8820cd26b97e4df882d1d4d25c269e58fe0f1c3eb05a864665c1d9b0cfd9e59f9261114d929091613afa565b60405180910390a15b5050565b60ff8116600090815260016020818152604092839020600d0180548451600294821615610100026000190190911693909304601f810183900483028401830190945283835260609390918301828280156111f55780601f106111ca576101008083540402835291602001916111f5565b820191906000526020600020905b8154815290600101906020018083116111d857829003601f168201915b50505050509050919050565b60ff166000908152600160205260409020600c015490565b6000546001600160a01b031633146112435760405162461bcd60e51b815260040161079690613e25565b60ff808316600090815260016020526040902054166112745760405162461bcd60e51b815260040161079690613d02565b60ff81166112945760405162461bcd60e51b815260040161079690613c53565b60ff80831660009081526001602052604090206003015490821611156112cc5760405162461bcd60e51b815260040161079690613d27565b60ff91821660009081526001602052604090208054929091166101000261ff0019909216919091179055565b6000546001600160a01b031633146113225760405162461bcd60e51b815260040161079690613e25565b60ff9091166000908152600160205260409020600b0155565b60ff9190911660009081526001602081815260408084206001600160a01b03909516845260049094019052919020908101546002909101549091565b6000546001600160a01b031681565b4690565b6001602081815260009283526040928390208054818401546002808401546008850154600a860154600b870154600c880154600d890180548d516101009d8216158e026000190190911697909704601f81018c90048c0288018c01909d528c875260ff808a169d9c90990489169b979a6001600160401b039687169a9690951698909316969195909493909291908301828280156114695780601f1061143e57610100808354040283529160200191611469565b820191906000526020600020905b81548152906001019060200180831161144c57829003601f168201915b5050505050905089565b6000546001600160a01b0316331461149d5760405162461bcd60e51b815260040161079690613e25565b806115d9576000805b60ff80861660009081526001602052604090206003015490821610156115365760ff808616600090815260016020526040812060038101805460049092019390919085169081106114f357fe5b60009182526020808320909101546001600160a01b0316835282019290925260400190205460ff62010000909104161561152e576001909101905b6001016114a6565b5060ff808516600090815260016020526040902054610100900481169082161161155f57600080fd5b60ff841660009081526001602090815260408083206001600160a01b038716845260040190915290819020805462ff000019166201000085151502179055517fa69c14bd53cc09f8b4dae64e98dfc4d104189d6f16261c3803c34ff03a906f6d906115cb908690613edb565b60405180910390a150611645565b60ff831660009081526001602090815260408083206001600160a01b038616845260040190915290819020805462ff000019166201000084151502179055517fa69c14bd53cc09f8b4dae64e98dfc4d104189d6f16261c3803c34ff03a906f6d90610b0e908590613edb565b505050565b8261016001515183610140015151146116755760405162461bcd60e51b815260040161079690613cbb565b8261018001515183610140015151146116a05760405162461bcd60e51b815260040161079690613cbb565b60c08301516001600160a01b031615806116c6575060c08301516001600160a01b031633145b806116dd575060a08301516001600160a01b031633145b6116f95760405162461bcd60e51b815260040161079690613c33565b60e083015160ff166000908152600160209081526040808320865184526006019091529020541580611763575060a083015160e084015160ff1660009081526001602081815260408084208851855260060190915290912001546001600160a01b03908116911614155b61177f5760405162461bcd60e51b815260040161079690613d6f565b8260a001516001600160a01b0316336001600160a01b0316141561191157600160008460e0015160ff1660ff16815260200190815260200160002060000160019054906101000a900460ff1660ff16611850846000015185602001518660400151876060015188608001518960a001518a60c001518b60e001518c61010001518d610120015160405160200161181e9a99989796959493929190613959565b604051602081830303815290604052805190602001208560e00151866101400151876101600151886101800151612865565b60ff1610156118715760405162461bcd60e51b815260040161079690613e48565b6040805180820182526060850151815260a0850180516001600160a01b03908116602080850191825260e089015160ff16600090815260018083528782208b518352600601909252868120955186559151940180549483166001600160a01b031990951694909417909355905192519216913480156108fc0292909190818181858888f1935050505015801561190b573d6000803e3d6000fd5b50611aa5565b82608001513410156119355760405162461bcd60e51b815260040161079690613e02565b600160008460e0015160ff1660ff16815260200190815260200160002060000160019054906101000a900460ff1660ff166119e8846000015185602001518660400151876060015188608001518960a001518a60c001518b60e001518c61010001518d61012001516040516020016119b69a99989796959493929190613959565b604051602081830303815290604052805190602001208560e00151866101400151876101600151886101800151612af1565b60ff161015611a095760405162461bcd60e51b815260040161079690613e48565b6040805180820182526060850151815260a0850180516001600160a01b03908116602080850191825260e089015160ff16600090815260018083528782208b518352600601909252868120955186559151940180549483166001600160a01b031990951694909417909355905192519216913480156108fc0292909190818181858888f19350505050158015611aa3573d6000803e3d6000fd5b505b7fd2af117ecd2e6b00222af8d3b9f9dfc2f1299d71f0e0c7cd558108cb2c724c6383600001518460a00151848660e0015185604051610b0e959493929190613b11565b6000546001600160a01b03163314611b125760405162461bcd60e51b815260040161079690613e25565b60ff80831660009081526001602052604090205416611b435760405162461bcd60e51b815260040161079690613d02565b80604051602001611b5491906139e0565b60408051601f19818403018152828252805160209182012060ff8616600090815260018352929092209192611b8d92600d0191016139fc565b604051602081830303815290604052805190602001201415611bc15760405162461bcd60e51b815260040161079690613c0c565b60ff821660009081526001602090815260409091208251611bea92600d90920191840190613000565b507f08d46a49dc7084588d216b2d5136c7234b8ef63aced14f79b27d177cacc47a0b8260405161114d9190613edb565b60ff16600090815260016020908152604080832093835260059093019052205490565b6000546001600160a01b03163314611c675760405162461bcd60e51b815260040161079690613e25565b60ff80831660009081526001602052604090205416611c985760405162461bcd60e51b815260040161079690613d02565b80611cb55760405162461bcd60e51b815260040161079690613c97565b60ff82166000908152600160205260409020600b01548111611ce95760405162461bcd60e51b815260040161079690613e8e565b60ff90911660009081526001602081905260409091200155565b6000546001600160a01b03163314611d2d5760405162461bcd60e51b815260040161079690613e25565b60ff80831660009081526001602052604090205416611d5e5760405162461bcd60e51b815260040161079690613d02565b6000815111611d7f5760405162461bcd60e51b815260040161079690613c0c565b805160ff8084166000908152600160205260409020805460039091015461010090910490911690031015611dc55760405162461bcd60e51b815260040161079690613cdc565b805160ff831660009081526001602052604080822060038101546002909101805467ffffffffffffffff19166001600160401b039290930390940181901c1617909155805b82518160ff1610156120b3576000600160008660ff1660ff1681526020019081526020016000206004016000858460ff1681518110611e4557fe5b6020908102919091018101516001600160a01b031682528101919091526040016000205460ff161115611e7757600080fd5b6000600160008660ff1660ff1681526020019081526020016000206004016000858460ff1681518110611ea657fe5b6020908102919091018101516001600160a01b0316825281810192909252604090810160009081205460ff89811683526001909452919020600301546101009091049091169150600019018110156120515760ff8516600090815260016020526040902060030180546000198101908110611f1d57fe5b600091825260208083209091015460ff80891684526001909252604090922060030180546001600160a01b039093169290918416908110611f5a57fe5b600091825260208083209190910180546001600160a01b0319166001600160a01b03949094169390931790925560ff87811682526001909252604081206003810180548594600490930193928516908110611fb157fe5b6000918252602080832091909101546001600160a01b031683528281019390935260409182018120805461ff00191661010060ff9687160217905592881683526001909152902060030180548061200457fe5b6001900381819060005260206000200160006101000a8154906001600160a01b030219169055905561204c85858460ff168151811061203f57fe5b6020026020010151612cfc565b6120aa565b60ff8516600090815260016020526040902060030180548061206f57fe5b6001900381819060005260206000200160006101000a8154906001600160a01b03021916905590556120aa85858460ff168151811061203f57fe5b50600101611e0a565b507f83d618b28572a37f185aa3e2cd22a8b30a020a9fce5f724734be159ed58351f083604051610b0e9190613edb565b6000546001600160a01b0316331461210d5760405162461bcd60e51b815260040161079690613e25565b60ff808316600081815260016020526040902054909116146121415760405162461bcd60e51b815260040161079690613d02565b60008151118015612153575060408151105b61216f5760405162461bcd60e51b815260040161079690613c0c565b805160ff8316600090815260016020526040908190206003015490910111156121aa5760405162461bcd60e51b815260040161079690613cdc565b805160ff831660009081526001602052604080822060038101546002909101805467ffffffffffffffff19166001600160401b039290930390940181901c1617909155805b82518160ff161015612402576000600160008660ff1660ff1681526020019081526020016000206004016000858460ff168151811061222a57fe5b6020908102919091018101516001600160a01b031682528101919091526040016000205460ff16111561225c57600080fd5b6000600160008660ff1660ff1681526020019081526020016000206007016000858460ff168151811061228b57fe5b6020908102919091018101516001600160a01b031682528101919091526040016000205460ff1611156122bd57600080fd5b6040805160a08101825260ff8087168083526000818152600160208181528683206003810154861682880152968601829052606086018390526080860183905292825290915286519293600401929091879190861690811061231b57fe5b6020908102919091018101516001600160a01b03168252818101929092526040908101600090812084518154868601518786015160ff1990921660ff9384161761ff001916610100918416919091021762ff00001916620100009115159190910217825560608601516001808401919091556080909601516002909201919091558881168252939092529020845160039091019185919084169081106123bd57fe5b60209081029190910181015182546001808201855560009485529290932090920180546001600160a01b0319166001600160a01b0390931692909217909155016121ef565b507fbac577832a30a726fbfc3595b4b4d665dfdc2bfadd2dfd798beb566a28f3cc4983604051610b0e9190613edb565b60ff166000908152600160205260409020600b015490565b60ff166000908152600160208190526040909120015490565b600080546001600160a01b0316331461248e5760405162461bcd60e51b815260040161079690613e25565b60ff80871660009081526001602052604090205416156124c05760405162461bcd60e51b815260040161079690613d02565b6040835111156124e25760405162461bcd60e51b815260040161079690613cdc565b604080516101608101825260ff88811680835287821660208085019182528486018b815289516001600160401b0390880381901c8116606080890191825260808901818152600060a08b0181905260c08b0183905260e08b01819052610100808c018290526101208c018290526101408c018f905298815260018088529b81208b518154995160ff199a909a16908c161761ff00191698909a16909802969096178855925198870198909855965160028601805467ffffffffffffffff19169190981617909655905180519295948594909390926125c79260038501929101906130c5565b5060a082015160088201805467ffffffffffffffff19166001600160401b0390921691909117905560c0820151805161260a9160098401916020909101906130c5565b5060e0820151600a8201805460ff191660ff909216919091179055610100820151600b820155610120820151600c820155610140820151805161265791600d840191602090910190613000565b5060009150505b86518160ff161015612828576000600160008c60ff1660ff1681526020019081526020016000206004016000898460ff168151811061269957fe5b6020908102919091018101516001600160a01b031682528101919091526040016000205460ff1611156126cb57600080fd5b600160008b60ff1660ff168152602001908152602001600020600301878260ff16815181106126f657fe5b60200260200101519080600181540180825580915050600190039060005260206000200160009091909190916101000a8154816001600160a01b0302191690836001600160a01b031602179055506040518060a001604052808b60ff1681526020018260ff168152602001600115158152602001600081526020016000815250600160008c60ff1660ff1681526020019081526020016000206004016000898460ff16815181106127a357fe5b6020908102919091018101516001600160a01b031682528181019290925260409081016000208351815493850151928501511515620100000262ff00001960ff9485166101000261ff00199590931660ff199096169590951793909316179290921617815560608201516001808301919091556080909201516002909101550161265e565b5060019998505050505050505050565b60008282111561284457fe5b50900390565b600082820183811080159061285f5750828110155b610b7b57fe5b60008060018181815b8851811015612acf5761287f611386565b60020289828151811061288e57fe5b60200260200101818151039150818152505060088982815181106128ae57fe5b602002602001018181510391508181525050600060018c8b84815181106128d157fe5b60200260200101518b85815181106128e557fe5b60200260200101518b86815181106128f957fe5b60200260200101516040516000815260200160405260405161291e9493929190613bb7565b6020604051602081039080840390855afa158015612940573d6000803e3d6000fd5b505060408051601f19015160ff808f166000818152600160209081528582206001600160a01b03861683526004019052939093205491945016141590506129d25760ff8b811660009081526001602081815260408084206001600160a01b03871685526004019091529091208082018054909201909155546001600160401b0387166101009091049091161b95909517945b8a6040516020016129e39190613a6c565b60405160208183030381529060405280519060200120600160008d60ff1660ff1681526020019081526020016000206007016000836001600160a01b03166001600160a01b0316815260200190815260200160002060000160009054906101000a900460ff16604051602001612a599190613a6c565b604051602081830303815290604052805190602001201415612ac65760ff8b811660009081526001602081815260408084206001600160a01b03871685526007019091529091208082018054909201909155546001600160401b0385166101009091049091161b93909317925b5060010161286e565b50612ad9826106e9565b612ae2856106e9565b019a9950505050505050505050565b6000806001815b8651811015612ce657612b09611386565b600202878281518110612b1857fe5b6020026020010181815103915081815250506008878281518110612b3857fe5b602002602001018181510391508181525050600060018a898481518110612b5b57fe5b6020026020010151898581518110612b6f57fe5b6020026020010151898681518110612b8357fe5b602002602001015160405160008152602001604052604051612ba89493929190613bb7565b6020604051602081039080840390855afa158015612bca573d6000803e3d6000fd5b5050604051601f198101519250612be691508a90602001613a6c565b60408051601f19818403018152828252805160209182012060ff808e166000908152600184528481206001600160a01b0388168252600401845293909320549093612c3693919091169101613a6c565b60405160208183030381529060405280519060200120148015612c87575060ff808a1660009081526001602090815260408083206001600160a01b0386168452600401909152902054620100009004165b15612cdd5760ff89811660009081526001602081815260408084206001600160a01b03871685526004019091529091208082018054909201909155546001600160401b0385166101009091049091161b93909317925b50600101612af8565b50612cf0826106e9565b98975050505050505050565b60ff82811660009081526001602081815260408084206001600160a01b0387168552600481018352818520805462ffffff191681559384018590556002909301849055600790920190529020541615612d5457600080fd5b60ff821660009081526001602052604090819020600901541015612e655760ff80831660008181526001602081815260408084206009810180546008830180546001600160401b036000198488030181901c1667ffffffffffffffff19909116179055835160a08101855297885288168785019081528784018781526060890188815260808a018981526001600160a01b038d16808b526007909601885295892099518a54935192511515620100000262ff000019938d166101000261ff001992909d1660ff1990951694909417169a909a171617875596518685015590516002909501949094558181528454918201855593825292902090910180546001600160a01b0319169091179055611156565b60ff8083166000908152600160205260408120600a81015460098201805460079093019490929116908110612e9657fe5b60009182526020808320909101546001600160a01b031683528281019390935260409182018120805462ffffff191681556001818101839055600290910182905560ff8681168352935220600a8101546009909101805484939192909116908110612efd57fe5b6000918252602080832090910180546001600160a01b039485166001600160a01b03199091161790556040805160a08101825260ff8088168083528086526001808652848720600a8101805485168689019081528688018a8152606088018b8152608089018c81529c8e168c5260079094018a52888b2097518854925191511515620100000262ff0000199289166101000261ff0019928a1660ff199586161792909216919091179190911617875591518684015598516002909501949094559552928490528454808416909401831693169290921792839055919091161415611156575060ff166000908152600160205260409020600a01805460ff19169055565b828054600181600116156101000203166002900490600052602060002090601f016020900481019282601f1061304157805160ff191683800117855561306e565b8280016001018555821561306e579182015b8281111561306e578251825591602001919060010190613053565b5061307a929150613126565b5090565b50805460018160011615610100020316600290046000825580601f106130a457506130c2565b601f0160209004906000526020600020908101906130c29190613126565b50565b82805482825590600052602060002090810192821561311a579160200282015b8281111561311a57825182546001600160a01b0319166001600160a01b039091161782556020909201916001909101906130e5565b5061307a929150613143565b61314091905b8082111561307a576000815560010161312c565b90565b61314091905b8082111561307a5780546001600160a01b0319168155600101613149565b803561075b81613fed565b600082601f830112613182578081fd5b813561319561319082613f9e565b613f78565b8181529150602080830190848101818402860182018710156131b657600080fd5b60005b848110156131de5781356131cc81613fed565b845292820192908201906001016131b9565b505050505092915050565b600082601f8301126131f9578081fd5b813561320761319082613f9e565b81815291506020808301908481018184028601820187101561322857600080fd5b60005b848110156131de5781358452928201929082019060010161322b565b600082601f830112613257578081fd5b81356001600160401b0381111561326c578182fd5b61327f601f8201601f1916602001613f78565b915080825283602082850101111561329657600080fd5b8060208401602084013760009082016020015292915050565b803560ff8116811461075b57600080fd5b6000806000606084860312156132d4578283fd5b8335925060208401356132e681613fed565b91506132f585604086016132af565b90509250925092565b60008060408385031215613310578182fd5b82359150602083013561332281614002565b809150509250929050565b600080600060608486031215613341578081fd5b83356001600160401b0380821115613357578283fd5b6101a091860180880383131561336b578384fd5b61337483613f78565b81358152602082013560208201526040820135604082015260608201356060820152608082013560808201526133ad8960a08401613167565b60a08201526133bf8960c08401613167565b60c08201526133d18960e084016132af565b60e082015261010093506133e7898584016132af565b8482015261012093508382013583811115613400578586fd5b61340c8a828501613247565b858301525061014093508382013583811115613426578586fd5b6134328a8285016131e9565b85830152506101609350838201358381111561344c578586fd5b6134588a8285016131e9565b858301525061018093508382013583811115613472578586fd5b61347e8a8285016131e9565b85830152508096505050602086013591508082111561349b578283fd5b6134a787838801613247565b935060408601359150808211156134bc578283fd5b506134c986828701613247565b9150509250925092565b6000602082840312156134e4578081fd5b81356001600160401b03808211156134fa578283fd5b61010091840180860383131561350e578384fd5b61351783613f78565b81358152602082013560208201526040820135935082841115613538578485fd5b61354487858401613247565b6040820152606082013593508284111561355c578485fd5b61356887858401613247565b606082015261357a8760808401613167565b608082015261358c8760a084016132af565b60a082015261359e8760c084016132af565b60c082015260e08201359350828411156135b6578485fd5b6135c287858401613247565b60e08201529695505050505050565b600080600080608085870312156135e6578182fd5b8435935060206135f8878288016132af565b935060408601356001600160401b0380821115613613578485fd5b81880189601f820112613624578586fd5b61362e6040613f78565b92508281875b600281101561365c5761364a8d83358601613247565b86529486019490860190600101613634565b5090965050506060880135925080831115613675578384fd5b505061368387828801613247565b91505092959194509250565b6000602082840312156136a0578081fd5b81356001600160401b0381168114610b7b578182fd5b6000602082840312156136c7578081fd5b610b7b83836132af565b600080604083850312156136e3578182fd5b82356136ee81614002565b9150602083013561332281613fed565b600080600060608486031215613712578081fd5b61371c85856132af565b9250602084013561372c81613fed565b929592945050506040919091013590565b600080600060608486031215613751578081fd5b61375b85856132af565b9250602084013561376b81613fed565b91506040840135801515811461377f578182fd5b809150509250925092565b6000806040838503121561379c578182fd5b6137a684846132af565b915060208301356001600160401b038111156137c0578182fd5b6137cc85828601613172565b9150509250929050565b600080604083850312156137e8578182fd5b6137f284846132af565b915060208301356001600160401b0381111561380c578182fd5b6137cc85828601613247565b6000806040838503121561382a578182fd5b61383484846132af565b946020939093013593505050565b600080600080600060a08688031215613859578283fd5b61386387876132af565b94506020860135935061387987604088016132af565b925060608601356001600160401b0380821115613894578283fd5b6138a089838a01613172565b935060808801359150808211156138b5578283fd5b506138c288828901613247565b9150509295509295909350565b600080604083850312156138e1578182fd5b6138eb84846132af565b91506138fa84602085016132af565b90509250929050565b6000815180845261391b816020860160208601613fbd565b601f01601f19169290920160200192915050565b60609390931b6bffffffffffffffffffffffff191683526014830191909152603482015260540190565b60008b82528a60208301528960408301528860608301528760808301526bffffffffffffffffffffffff19808860601b1660a0840152808760601b1660b48401525060ff60f81b808660f81b1660c8840152808560f81b1660c98401525082516139ca8160ca850160208701613fbd565b9190910160ca019b9a5050505050505050505050565b600082516139f2818460208701613fbd565b9190910192915050565b6000808354600180821660008114613a1b5760018114613a3257613a61565b60ff198316865260028304607f1686019350613a61565b600283048786526020808720875b83811015613a595781548a820152908501908201613a40565b505050860193505b509195945050505050565b60f89190911b6001600160f81b031916815260010190565b6001600160a01b0391909116815260200190565b604080825283519082018190526000906020906060840190828701845b82811015613ada5781516001600160a01b031684529284019290840190600101613ab5565b50505060ff9490941692019190915250919050565b901515815260200190565b9182526001600160a01b0316602082015260400190565b8581526001600160a01b038516602082015260a060408201819052600090613b3b90830186613903565b60ff851660608401528281036080840152612cf08185613903565b600088825287602083015286604083015260e06060830152613b7b60e0830187613903565b8281036080840152613b8d8187613903565b60ff861660a085015283810360c0850152613ba88186613903565b9b9a5050505050505050505050565b93845260ff9290921660208401526040830152606082015260800190565b600060208252610b7b6020830184613903565b6020808252600a90820152693a3c2430b9b41032b93960b11b604082015260600190565b6020808252600d908201526c6e656564205f616e63686f727360981b604082015260600190565b6020808252600690820152653a379032b93960d11b604082015260600190565b6020808252600790820152660636f756e7420360cc1b604082015260600190565b6020808252600990820152683b30b63ab29032b93960b91b604082015260600190565b6020808252600a908201526906d617856616c756520360b41b604082015260600190565b6020808252600790820152663b39399032b93960c91b604082015260600190565b6020808252600c908201526b2fb0b731b437b9399032b93960a11b604082015260600190565b6020808252600b908201526a383ab93837b9b29032b93960a91b604082015260600190565b60208082526009908201526831b7bab73a1032b93960b91b604082015260600190565b6020808252600b908201526a6e6f7420616e63686f727360a81b604082015260600190565b6020808252600890820152673a3c24b21032b93960c11b604082015260600190565b6020808252600e908201526d34b63632b3b0b61030b731b437b960911b604082015260600190565b6020808252600a90820152693932bbb0b9321032b93960b11b604082015260600190565b6020808252600b908201526a31b430b4b724b21032b93960a91b604082015260600190565b602080825260099082015268383934b1b29032b93960b91b604082015260600190565b6020808252600990820152683737ba1037bbb732b960b91b604082015260600190565b6020808252600a908201526939b4b3b71032b93937b960b11b604082015260600190565b602080825260089082015267333937b69032b93960c11b604082015260600190565b602080825260089082015267746f6f206c65737360c01b604082015260600190565b90815260200190565b918252602082015260400190565b6001600160401b0391909116815260200190565b60ff91909116815260200190565b60ff9390931683526001600160a01b03919091166020830152604082015260600190565b600061012060ff8c16835260ff8b1660208401528960408401526001600160401b03808a16606085015280891660808501525060ff871660a08401528560c08401528460e084015280610100840152613f6881840185613903565b9c9b505050505050505050505050565b6040518181016001600160401b0381118282101715613f9657600080fd5b604052919050565b60006001600160401b03821115613fb3578081fd5b5060209081020190565b60005b83811015613fd8578181015183820152602001613fc0565b83811115613fe7576000848401525b50505050565b6001600160a01b03811681146130c257600080fd5b60ff811681146130c257600080fdfea26469706673582212201f852ff0e63fdeec7eb62ac046156dae8ed4bc89387cdbf0cb0b87d0fc0637ef64736f6c63782b302e362e332d646576656c6f702e323032302e352e31352b636f6d6d69742e30623361346231322e6d6f64005c"
+
+// DeployCrossDemo deploys a new Ethereum contract, binding an instance of CrossDemo to it.
+func DeployCrossDemo(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *CrossDemo, error) {
+	parsed, err := abi.JSON(strings.NewReader(CrossDemoABI))
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, parsed, common.FromHex(CrossDemoBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &CrossDemo{CrossDemoCaller: CrossDemoCaller{contract: contract}, CrossDemoTransactor: CrossDemoTransactor{contract: contract}, CrossDemoFilterer: CrossDemoFilterer{contract: contract}}, nil
+}
+
+// CrossDemo is an auto generated Go binding around an Ethereum contract.
+type CrossDemo struct {
+	CrossDemoCaller     // Read-only binding to the contract
+	CrossDemoTransactor // Write-only binding to the contract
+	CrossDemoFilterer   // Log filterer for contract events
+}
+
+// CrossDemoCaller is an auto generated read-only Go binding around an Ethereum contract.
+type CrossDemoCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CrossDemoTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type CrossDemoTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CrossDemoFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type CrossDemoFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CrossDemoSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type CrossDemoSession struct {
+	Contract     *CrossDemo        // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// CrossDemoCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type CrossDemoCallerSession struct {
+	Contract *CrossDemoCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts    // Call options to use throughout this session
+}
+
+// CrossDemoTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type CrossDemoTransactorSession struct {
+	Contract     *CrossDemoTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts    // Transaction auth options to use throughout this session
+}
+
+// CrossDemoRaw is an auto generated low-level Go binding around an Ethereum contract.
+type CrossDemoRaw struct {
+	Contract *CrossDemo // Generic contract binding to access the raw methods on
+}
+
+// CrossDemoCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type CrossDemoCallerRaw struct {
+	Contract *CrossDemoCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// CrossDemoTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type CrossDemoTransactorRaw struct {
+	Contract *CrossDemoTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewCrossDemo creates a new instance of CrossDemo, bound to a specific deployed contract.
+func NewCrossDemo(address common.Address, backend bind.ContractBackend) (*CrossDemo, error) {
+	contract, err := bindCrossDemo(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemo{CrossDemoCaller: CrossDemoCaller{contract: contract}, CrossDemoTransactor: CrossDemoTransactor{contract: contract}, CrossDemoFilterer: CrossDemoFilterer{contract: contract}}, nil
+}
+
+// NewCrossDemoCaller creates a new read-only instance of CrossDemo, bound to a specific deployed contract.
+func NewCrossDemoCaller(address common.Address, caller bind.ContractCaller) (*CrossDemoCaller, error) {
+	contract, err := bindCrossDemo(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoCaller{contract: contract}, nil
+}
+
+// NewCrossDemoTransactor creates a new write-only instance of CrossDemo, bound to a specific deployed contract.
+func NewCrossDemoTransactor(address common.Address, transactor bind.ContractTransactor) (*CrossDemoTransactor, error) {
+	contract, err := bindCrossDemo(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoTransactor{contract: contract}, nil
+}
+
+// NewCrossDemoFilterer creates a new log filterer instance of CrossDemo, bound to a specific deployed contract.
+func NewCrossDemoFilterer(address common.Address, filterer bind.ContractFilterer) (*CrossDemoFilterer, error) {
+	contract, err := bindCrossDemo(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoFilterer{contract: contract}, nil
+}
+
+// bindCrossDemo binds a generic wrapper to an already deployed contract.
+func bindCrossDemo(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(CrossDemoABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_CrossDemo *CrossDemoRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _CrossDemo.Contract.CrossDemoCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_CrossDemo *CrossDemoRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _CrossDemo.Contract.CrossDemoTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_CrossDemo *CrossDemoRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _CrossDemo.Contract.CrossDemoTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_CrossDemo *CrossDemoCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _CrossDemo.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_CrossDemo *CrossDemoTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _CrossDemo.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_CrossDemo *CrossDemoTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _CrossDemo.Contract.contract.Transact(opts, method, params...)
+}
+
+// BitCount is a free data retrieval call binding the contract method 0x121c439d.
+//
+// Solidity: function bitCount(uint64 n) constant returns(uint64)
+func (_CrossDemo *CrossDemoCaller) BitCount(opts *bind.CallOpts, n uint64) (uint64, error) {
+	var (
+		ret0 = new(uint64)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "bitCount", n)
+	return *ret0, err
+}
+
+// BitCount is a free data retrieval call binding the contract method 0x121c439d.
+//
+// Solidity: function bitCount(uint64 n) constant returns(uint64)
+func (_CrossDemo *CrossDemoSession) BitCount(n uint64) (uint64, error) {
+	return _CrossDemo.Contract.BitCount(&_CrossDemo.CallOpts, n)
+}
+
+// BitCount is a free data retrieval call binding the contract method 0x121c439d.
+//
+// Solidity: function bitCount(uint64 n) constant returns(uint64)
+func (_CrossDemo *CrossDemoCallerSession) BitCount(n uint64) (uint64, error) {
+	return _CrossDemo.Contract.BitCount(&_CrossDemo.CallOpts, n)
+}
+
+// ChainId is a free data retrieval call binding the contract method 0x9a8a0592.
+//
+// Solidity: function chainId() constant returns(uint256 id)
+func (_CrossDemo *CrossDemoCaller) ChainId(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "chainId")
+	return *ret0, err
+}
+
+// ChainId is a free data retrieval call binding the contract method 0x9a8a0592.
+//
+// Solidity: function chainId() constant returns(uint256 id)
+func (_CrossDemo *CrossDemoSession) ChainId() (*big.Int, error) {
+	return _CrossDemo.Contract.ChainId(&_CrossDemo.CallOpts)
+}
+
+// ChainId is a free data retrieval call binding the contract method 0x9a8a0592.
+//
+// Solidity: function chainId() constant returns(uint256 id)
+func (_CrossDemo *CrossDemoCallerSession) ChainId() (*big.Int, error) {
+	return _CrossDemo.Contract.ChainId(&_CrossDemo.CallOpts)
+}
+
+// CrossChains is a free data retrieval call binding the contract method 0xa9a7c9cb.
+//
+// Solidity: function crossChains(uint8 ) constant returns(uint8 purpose, uint8 signConfirmCount, uint256 maxValue, uint64 anchorsPositionBit, uint64 delsPositionBit, uint8 delId, uint256 reward, uint256 totalReward, string router)
+func (_CrossDemo *CrossDemoCaller) CrossChains(opts *bind.CallOpts, arg0 uint8) (struct {
+	Purpose            uint8
+	SignConfirmCount   uint8
+	MaxValue           *big.Int
+	AnchorsPositionBit uint64
+	DelsPositionBit    uint64
+	DelId              uint8
+	Reward             *big.Int
+	TotalReward        *big.Int
+	Router             string
+}, error) {
+	ret := new(struct {
+		Purpose            uint8
+		SignConfirmCount   uint8
+		MaxValue           *big.Int
+		AnchorsPositionBit uint64
+		DelsPositionBit    uint64
+		DelId              uint8
+		Reward             *big.Int
+		TotalReward        *big.Int
+		Router             string
+	})
+	out := ret
+	err := _CrossDemo.contract.Call(opts, out, "crossChains", arg0)
+	return *ret, err
+}
+
+// CrossChains is a free data retrieval call binding the contract method 0xa9a7c9cb.
+//
+// Solidity: function crossChains(uint8 ) constant returns(uint8 purpose, uint8 signConfirmCount, uint256 maxValue, uint64 anchorsPositionBit, uint64 delsPositionBit, uint8 delId, uint256 reward, uint256 totalReward, string router)
+func (_CrossDemo *CrossDemoSession) CrossChains(arg0 uint8) (struct {
+	Purpose            uint8
+	SignConfirmCount   uint8
+	MaxValue           *big.Int
+	AnchorsPositionBit uint64
+	DelsPositionBit    uint64
+	DelId              uint8
+	Reward             *big.Int
+	TotalReward        *big.Int
+	Router             string
+}, error) {
+	return _CrossDemo.Contract.CrossChains(&_CrossDemo.CallOpts, arg0)
+}
+
+// CrossChains is a free data retrieval call binding the contract method 0xa9a7c9cb.
+//
+// Solidity: function crossChains(uint8 ) constant returns(uint8 purpose, uint8 signConfirmCount, uint256 maxValue, uint64 anchorsPositionBit, uint64 delsPositionBit, uint8 delId, uint256 reward, uint256 totalReward, string router)
+func (_CrossDemo *CrossDemoCallerSession) CrossChains(arg0 uint8) (struct {
+	Purpose            uint8
+	SignConfirmCount   uint8
+	MaxValue           *big.Int
+	AnchorsPositionBit uint64
+	DelsPositionBit    uint64
+	DelId              uint8
+	Reward             *big.Int
+	TotalReward        *big.Int
+	Router             string
+}, error) {
+	return _CrossDemo.Contract.CrossChains(&_CrossDemo.CallOpts, arg0)
+}
+
+// GetAnchorWorkCount is a free data retrieval call binding the contract method 0x848afae8.
+//
+// Solidity: function getAnchorWorkCount(uint8 purpose, address _anchor) constant returns(uint256, uint256)
+func (_CrossDemo *CrossDemoCaller) GetAnchorWorkCount(opts *bind.CallOpts, purpose uint8, _anchor common.Address) (*big.Int, *big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+		ret1 = new(*big.Int)
+	)
+	out := &[]interface{}{
+		ret0,
+		ret1,
+	}
+	err := _CrossDemo.contract.Call(opts, out, "getAnchorWorkCount", purpose, _anchor)
+	return *ret0, *ret1, err
+}
+
+// GetAnchorWorkCount is a free data retrieval call binding the contract method 0x848afae8.
+//
+// Solidity: function getAnchorWorkCount(uint8 purpose, address _anchor) constant returns(uint256, uint256)
+func (_CrossDemo *CrossDemoSession) GetAnchorWorkCount(purpose uint8, _anchor common.Address) (*big.Int, *big.Int, error) {
+	return _CrossDemo.Contract.GetAnchorWorkCount(&_CrossDemo.CallOpts, purpose, _anchor)
+}
+
+// GetAnchorWorkCount is a free data retrieval call binding the contract method 0x848afae8.
+//
+// Solidity: function getAnchorWorkCount(uint8 purpose, address _anchor) constant returns(uint256, uint256)
+func (_CrossDemo *CrossDemoCallerSession) GetAnchorWorkCount(purpose uint8, _anchor common.Address) (*big.Int, *big.Int, error) {
+	return _CrossDemo.Contract.GetAnchorWorkCount(&_CrossDemo.CallOpts, purpose, _anchor)
+}
+
+// GetAnchors is a free data retrieval call binding the contract method 0x028acd48.
+//
+// Solidity: function getAnchors(uint8 purpose) constant returns(address[] _anchors, uint8)
+func (_CrossDemo *CrossDemoCaller) GetAnchors(opts *bind.CallOpts, purpose uint8) ([]common.Address, uint8, error) {
+	var (
+		ret0 = new([]common.Address)
+		ret1 = new(uint8)
+	)
+	out := &[]interface{}{
+		ret0,
+		ret1,
+	}
+	err := _CrossDemo.contract.Call(opts, out, "getAnchors", purpose)
+	return *ret0, *ret1, err
+}
+
+// GetAnchors is a free data retrieval call binding the contract method 0x028acd48.
+//
+// Solidity: function getAnchors(uint8 purpose) constant returns(address[] _anchors, uint8)
+func (_CrossDemo *CrossDemoSession) GetAnchors(purpose uint8) ([]common.Address, uint8, error) {
+	return _CrossDemo.Contract.GetAnchors(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetAnchors is a free data retrieval call binding the contract method 0x028acd48.
+//
+// Solidity: function getAnchors(uint8 purpose) constant returns(address[] _anchors, uint8)
+func (_CrossDemo *CrossDemoCallerSession) GetAnchors(purpose uint8) ([]common.Address, uint8, error) {
+	return _CrossDemo.Contract.GetAnchors(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetChainReward is a free data retrieval call binding the contract method 0xdfbfed8a.
+//
+// Solidity: function getChainReward(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCaller) GetChainReward(opts *bind.CallOpts, purpose uint8) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "getChainReward", purpose)
+	return *ret0, err
+}
+
+// GetChainReward is a free data retrieval call binding the contract method 0xdfbfed8a.
+//
+// Solidity: function getChainReward(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoSession) GetChainReward(purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetChainReward(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetChainReward is a free data retrieval call binding the contract method 0xdfbfed8a.
+//
+// Solidity: function getChainReward(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCallerSession) GetChainReward(purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetChainReward(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetDelAnchorSignCount is a free data retrieval call binding the contract method 0x21524012.
+//
+// Solidity: function getDelAnchorSignCount(uint8 purpose, address _anchor) constant returns(uint256)
+func (_CrossDemo *CrossDemoCaller) GetDelAnchorSignCount(opts *bind.CallOpts, purpose uint8, _anchor common.Address) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "getDelAnchorSignCount", purpose, _anchor)
+	return *ret0, err
+}
+
+// GetDelAnchorSignCount is a free data retrieval call binding the contract method 0x21524012.
+//
+// Solidity: function getDelAnchorSignCount(uint8 purpose, address _anchor) constant returns(uint256)
+func (_CrossDemo *CrossDemoSession) GetDelAnchorSignCount(purpose uint8, _anchor common.Address) (*big.Int, error) {
+	return _CrossDemo.Contract.GetDelAnchorSignCount(&_CrossDemo.CallOpts, purpose, _anchor)
+}
+
+// GetDelAnchorSignCount is a free data retrieval call binding the contract method 0x21524012.
+//
+// Solidity: function getDelAnchorSignCount(uint8 purpose, address _anchor) constant returns(uint256)
+func (_CrossDemo *CrossDemoCallerSession) GetDelAnchorSignCount(purpose uint8, _anchor common.Address) (*big.Int, error) {
+	return _CrossDemo.Contract.GetDelAnchorSignCount(&_CrossDemo.CallOpts, purpose, _anchor)
+}
+
+// GetMakerTx is a free data retrieval call binding the contract method 0xcb2db4ee.
+//
+// Solidity: function getMakerTx(bytes32 txId, uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCaller) GetMakerTx(opts *bind.CallOpts, txId [32]byte, purpose uint8) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "getMakerTx", txId, purpose)
+	return *ret0, err
+}
+
+// GetMakerTx is a free data retrieval call binding the contract method 0xcb2db4ee.
+//
+// Solidity: function getMakerTx(bytes32 txId, uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoSession) GetMakerTx(txId [32]byte, purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetMakerTx(&_CrossDemo.CallOpts, txId, purpose)
+}
+
+// GetMakerTx is a free data retrieval call binding the contract method 0xcb2db4ee.
+//
+// Solidity: function getMakerTx(bytes32 txId, uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCallerSession) GetMakerTx(txId [32]byte, purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetMakerTx(&_CrossDemo.CallOpts, txId, purpose)
+}
+
+// GetMaxValue is a free data retrieval call binding the contract method 0xe0db6ff6.
+//
+// Solidity: function getMaxValue(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCaller) GetMaxValue(opts *bind.CallOpts, purpose uint8) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "getMaxValue", purpose)
+	return *ret0, err
+}
+
+// GetMaxValue is a free data retrieval call binding the contract method 0xe0db6ff6.
+//
+// Solidity: function getMaxValue(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoSession) GetMaxValue(purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetMaxValue(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetMaxValue is a free data retrieval call binding the contract method 0xe0db6ff6.
+//
+// Solidity: function getMaxValue(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCallerSession) GetMaxValue(purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetMaxValue(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetRouter is a free data retrieval call binding the contract method 0x58351746.
+//
+// Solidity: function getRouter(uint8 purpose) constant returns(string)
+func (_CrossDemo *CrossDemoCaller) GetRouter(opts *bind.CallOpts, purpose uint8) (string, error) {
+	var (
+		ret0 = new(string)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "getRouter", purpose)
+	return *ret0, err
+}
+
+// GetRouter is a free data retrieval call binding the contract method 0x58351746.
+//
+// Solidity: function getRouter(uint8 purpose) constant returns(string)
+func (_CrossDemo *CrossDemoSession) GetRouter(purpose uint8) (string, error) {
+	return _CrossDemo.Contract.GetRouter(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetRouter is a free data retrieval call binding the contract method 0x58351746.
+//
+// Solidity: function getRouter(uint8 purpose) constant returns(string)
+func (_CrossDemo *CrossDemoCallerSession) GetRouter(purpose uint8) (string, error) {
+	return _CrossDemo.Contract.GetRouter(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetTakerTx is a free data retrieval call binding the contract method 0x46a90a15.
+//
+// Solidity: function getTakerTx(bytes32 txId, address _from, uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCaller) GetTakerTx(opts *bind.CallOpts, txId [32]byte, _from common.Address, purpose uint8) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "getTakerTx", txId, _from, purpose)
+	return *ret0, err
+}
+
+// GetTakerTx is a free data retrieval call binding the contract method 0x46a90a15.
+//
+// Solidity: function getTakerTx(bytes32 txId, address _from, uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoSession) GetTakerTx(txId [32]byte, _from common.Address, purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetTakerTx(&_CrossDemo.CallOpts, txId, _from, purpose)
+}
+
+// GetTakerTx is a free data retrieval call binding the contract method 0x46a90a15.
+//
+// Solidity: function getTakerTx(bytes32 txId, address _from, uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCallerSession) GetTakerTx(txId [32]byte, _from common.Address, purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetTakerTx(&_CrossDemo.CallOpts, txId, _from, purpose)
+}
+
+// GetTotalReward is a free data retrieval call binding the contract method 0x5f4288e1.
+//
+// Solidity: function getTotalReward(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCaller) GetTotalReward(opts *bind.CallOpts, purpose uint8) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "getTotalReward", purpose)
+	return *ret0, err
+}
+
+// GetTotalReward is a free data retrieval call binding the contract method 0x5f4288e1.
+//
+// Solidity: function getTotalReward(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoSession) GetTotalReward(purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetTotalReward(&_CrossDemo.CallOpts, purpose)
+}
+
+// GetTotalReward is a free data retrieval call binding the contract method 0x5f4288e1.
+//
+// Solidity: function getTotalReward(uint8 purpose) constant returns(uint256)
+func (_CrossDemo *CrossDemoCallerSession) GetTotalReward(purpose uint8) (*big.Int, error) {
+	return _CrossDemo.Contract.GetTotalReward(&_CrossDemo.CallOpts, purpose)
+}
+
+// List is a free data retrieval call binding the contract method 0x0f560cd7.
+//
+// Solidity: function list() constant returns(uint256 ll)
+func (_CrossDemo *CrossDemoCaller) List(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "list")
+	return *ret0, err
+}
+
+// List is a free data retrieval call binding the contract method 0x0f560cd7.
+//
+// Solidity: function list() constant returns(uint256 ll)
+func (_CrossDemo *CrossDemoSession) List() (*big.Int, error) {
+	return _CrossDemo.Contract.List(&_CrossDemo.CallOpts)
+}
+
+// List is a free data retrieval call binding the contract method 0x0f560cd7.
+//
+// Solidity: function list() constant returns(uint256 ll)
+func (_CrossDemo *CrossDemoCallerSession) List() (*big.Int, error) {
+	return _CrossDemo.Contract.List(&_CrossDemo.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_CrossDemo *CrossDemoCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _CrossDemo.contract.Call(opts, out, "owner")
+	return *ret0, err
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_CrossDemo *CrossDemoSession) Owner() (common.Address, error) {
+	return _CrossDemo.Contract.Owner(&_CrossDemo.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_CrossDemo *CrossDemoCallerSession) Owner() (common.Address, error) {
+	return _CrossDemo.Contract.Owner(&_CrossDemo.CallOpts)
+}
+
+// AccumulateRewards is a paid mutator transaction binding the contract method 0x388e7e35.
+//
+// Solidity: function accumulateRewards(uint8 purpose, address anchor, uint256 reward) returns()
+func (_CrossDemo *CrossDemoTransactor) AccumulateRewards(opts *bind.TransactOpts, purpose uint8, anchor common.Address, reward *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "accumulateRewards", purpose, anchor, reward)
+}
+
+// AccumulateRewards is a paid mutator transaction binding the contract method 0x388e7e35.
+//
+// Solidity: function accumulateRewards(uint8 purpose, address anchor, uint256 reward) returns()
+func (_CrossDemo *CrossDemoSession) AccumulateRewards(purpose uint8, anchor common.Address, reward *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.Contract.AccumulateRewards(&_CrossDemo.TransactOpts, purpose, anchor, reward)
+}
+
+// AccumulateRewards is a paid mutator transaction binding the contract method 0x388e7e35.
+//
+// Solidity: function accumulateRewards(uint8 purpose, address anchor, uint256 reward) returns()
+func (_CrossDemo *CrossDemoTransactorSession) AccumulateRewards(purpose uint8, anchor common.Address, reward *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.Contract.AccumulateRewards(&_CrossDemo.TransactOpts, purpose, anchor, reward)
+}
+
+// AddAnchors is a paid mutator transaction binding the contract method 0xd4151446.
+//
+// Solidity: function addAnchors(uint8 purpose, address[] _anchors) returns()
+func (_CrossDemo *CrossDemoTransactor) AddAnchors(opts *bind.TransactOpts, purpose uint8, _anchors []common.Address) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "addAnchors", purpose, _anchors)
+}
+
+// AddAnchors is a paid mutator transaction binding the contract method 0xd4151446.
+//
+// Solidity: function addAnchors(uint8 purpose, address[] _anchors) returns()
+func (_CrossDemo *CrossDemoSession) AddAnchors(purpose uint8, _anchors []common.Address) (*types.Transaction, error) {
+	return _CrossDemo.Contract.AddAnchors(&_CrossDemo.TransactOpts, purpose, _anchors)
+}
+
+// AddAnchors is a paid mutator transaction binding the contract method 0xd4151446.
+//
+// Solidity: function addAnchors(uint8 purpose, address[] _anchors) returns()
+func (_CrossDemo *CrossDemoTransactorSession) AddAnchors(purpose uint8, _anchors []common.Address) (*types.Transaction, error) {
+	return _CrossDemo.Contract.AddAnchors(&_CrossDemo.TransactOpts, purpose, _anchors)
+}
+
+// ChainRegister is a paid mutator transaction binding the contract method 0xeb16432e.
+//
+// Solidity: function chainRegister(uint8 purpose, uint256 maxValue, uint8 signConfirmCount, address[] _anchors, string router) returns(bool)
+func (_CrossDemo *CrossDemoTransactor) ChainRegister(opts *bind.TransactOpts, purpose uint8, maxValue *big.Int, signConfirmCount uint8, _anchors []common.Address, router string) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "chainRegister", purpose, maxValue, signConfirmCount, _anchors, router)
+}
+
+// ChainRegister is a paid mutator transaction binding the contract method 0xeb16432e.
+//
+// Solidity: function chainRegister(uint8 purpose, uint256 maxValue, uint8 signConfirmCount, address[] _anchors, string router) returns(bool)
+func (_CrossDemo *CrossDemoSession) ChainRegister(purpose uint8, maxValue *big.Int, signConfirmCount uint8, _anchors []common.Address, router string) (*types.Transaction, error) {
+	return _CrossDemo.Contract.ChainRegister(&_CrossDemo.TransactOpts, purpose, maxValue, signConfirmCount, _anchors, router)
+}
+
+// ChainRegister is a paid mutator transaction binding the contract method 0xeb16432e.
+//
+// Solidity: function chainRegister(uint8 purpose, uint256 maxValue, uint8 signConfirmCount, address[] _anchors, string router) returns(bool)
+func (_CrossDemo *CrossDemoTransactorSession) ChainRegister(purpose uint8, maxValue *big.Int, signConfirmCount uint8, _anchors []common.Address, router string) (*types.Transaction, error) {
+	return _CrossDemo.Contract.ChainRegister(&_CrossDemo.TransactOpts, purpose, maxValue, signConfirmCount, _anchors, router)
+}
+
+// MakerFinish is a paid mutator transaction binding the contract method 0x4af4ce3c.
+//
+// Solidity: function makerFinish(CrossStructRecept rtx) returns()
+func (_CrossDemo *CrossDemoTransactor) MakerFinish(opts *bind.TransactOpts, rtx CrossStructRecept) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "makerFinish", rtx)
+}
+
+// MakerFinish is a paid mutator transaction binding the contract method 0x4af4ce3c.
+//
+// Solidity: function makerFinish(CrossStructRecept rtx) returns()
+func (_CrossDemo *CrossDemoSession) MakerFinish(rtx CrossStructRecept) (*types.Transaction, error) {
+	return _CrossDemo.Contract.MakerFinish(&_CrossDemo.TransactOpts, rtx)
+}
+
+// MakerFinish is a paid mutator transaction binding the contract method 0x4af4ce3c.
+//
+// Solidity: function makerFinish(CrossStructRecept rtx) returns()
+func (_CrossDemo *CrossDemoTransactorSession) MakerFinish(rtx CrossStructRecept) (*types.Transaction, error) {
+	return _CrossDemo.Contract.MakerFinish(&_CrossDemo.TransactOpts, rtx)
+}
+
+// MakerStart is a paid mutator transaction binding the contract method 0x2feb60e6.
+//
+// Solidity: function makerStart(uint256 destValue, uint8 purpose, string[2] arg, bytes data) returns()
+func (_CrossDemo *CrossDemoTransactor) MakerStart(opts *bind.TransactOpts, destValue *big.Int, purpose uint8, arg [2]string, data []byte) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "makerStart", destValue, purpose, arg, data)
+}
+
+// MakerStart is a paid mutator transaction binding the contract method 0x2feb60e6.
+//
+// Solidity: function makerStart(uint256 destValue, uint8 purpose, string[2] arg, bytes data) returns()
+func (_CrossDemo *CrossDemoSession) MakerStart(destValue *big.Int, purpose uint8, arg [2]string, data []byte) (*types.Transaction, error) {
+	return _CrossDemo.Contract.MakerStart(&_CrossDemo.TransactOpts, destValue, purpose, arg, data)
+}
+
+// MakerStart is a paid mutator transaction binding the contract method 0x2feb60e6.
+//
+// Solidity: function makerStart(uint256 destValue, uint8 purpose, string[2] arg, bytes data) returns()
+func (_CrossDemo *CrossDemoTransactorSession) MakerStart(destValue *big.Int, purpose uint8, arg [2]string, data []byte) (*types.Transaction, error) {
+	return _CrossDemo.Contract.MakerStart(&_CrossDemo.TransactOpts, destValue, purpose, arg, data)
+}
+
+// RemoveAnchors is a paid mutator transaction binding the contract method 0xd0bcc16f.
+//
+// Solidity: function removeAnchors(uint8 purpose, address[] _anchors) returns()
+func (_CrossDemo *CrossDemoTransactor) RemoveAnchors(opts *bind.TransactOpts, purpose uint8, _anchors []common.Address) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "removeAnchors", purpose, _anchors)
+}
+
+// RemoveAnchors is a paid mutator transaction binding the contract method 0xd0bcc16f.
+//
+// Solidity: function removeAnchors(uint8 purpose, address[] _anchors) returns()
+func (_CrossDemo *CrossDemoSession) RemoveAnchors(purpose uint8, _anchors []common.Address) (*types.Transaction, error) {
+	return _CrossDemo.Contract.RemoveAnchors(&_CrossDemo.TransactOpts, purpose, _anchors)
+}
+
+// RemoveAnchors is a paid mutator transaction binding the contract method 0xd0bcc16f.
+//
+// Solidity: function removeAnchors(uint8 purpose, address[] _anchors) returns()
+func (_CrossDemo *CrossDemoTransactorSession) RemoveAnchors(purpose uint8, _anchors []common.Address) (*types.Transaction, error) {
+	return _CrossDemo.Contract.RemoveAnchors(&_CrossDemo.TransactOpts, purpose, _anchors)
+}
+
+// SetAnchorStatus is a paid mutator transaction binding the contract method 0xaa8a65ac.
+//
+// Solidity: function setAnchorStatus(uint8 purpose, address _anchor, bool status) returns()
+func (_CrossDemo *CrossDemoTransactor) SetAnchorStatus(opts *bind.TransactOpts, purpose uint8, _anchor common.Address, status bool) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "setAnchorStatus", purpose, _anchor, status)
+}
+
+// SetAnchorStatus is a paid mutator transaction binding the contract method 0xaa8a65ac.
+//
+// Solidity: function setAnchorStatus(uint8 purpose, address _anchor, bool status) returns()
+func (_CrossDemo *CrossDemoSession) SetAnchorStatus(purpose uint8, _anchor common.Address, status bool) (*types.Transaction, error) {
+	return _CrossDemo.Contract.SetAnchorStatus(&_CrossDemo.TransactOpts, purpose, _anchor, status)
+}
+
+// SetAnchorStatus is a paid mutator transaction binding the contract method 0xaa8a65ac.
+//
+// Solidity: function setAnchorStatus(uint8 purpose, address _anchor, bool status) returns()
+func (_CrossDemo *CrossDemoTransactorSession) SetAnchorStatus(purpose uint8, _anchor common.Address, status bool) (*types.Transaction, error) {
+	return _CrossDemo.Contract.SetAnchorStatus(&_CrossDemo.TransactOpts, purpose, _anchor, status)
+}
+
+// SetMaxValue is a paid mutator transaction binding the contract method 0xcb93af0d.
+//
+// Solidity: function setMaxValue(uint8 purpose, uint256 maxValue) returns()
+func (_CrossDemo *CrossDemoTransactor) SetMaxValue(opts *bind.TransactOpts, purpose uint8, maxValue *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "setMaxValue", purpose, maxValue)
+}
+
+// SetMaxValue is a paid mutator transaction binding the contract method 0xcb93af0d.
+//
+// Solidity: function setMaxValue(uint8 purpose, uint256 maxValue) returns()
+func (_CrossDemo *CrossDemoSession) SetMaxValue(purpose uint8, maxValue *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.Contract.SetMaxValue(&_CrossDemo.TransactOpts, purpose, maxValue)
+}
+
+// SetMaxValue is a paid mutator transaction binding the contract method 0xcb93af0d.
+//
+// Solidity: function setMaxValue(uint8 purpose, uint256 maxValue) returns()
+func (_CrossDemo *CrossDemoTransactorSession) SetMaxValue(purpose uint8, maxValue *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.Contract.SetMaxValue(&_CrossDemo.TransactOpts, purpose, maxValue)
+}
+
+// SetReward is a paid mutator transaction binding the contract method 0x80033daf.
+//
+// Solidity: function setReward(uint8 purpose, uint256 _reward) returns()
+func (_CrossDemo *CrossDemoTransactor) SetReward(opts *bind.TransactOpts, purpose uint8, _reward *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "setReward", purpose, _reward)
+}
+
+// SetReward is a paid mutator transaction binding the contract method 0x80033daf.
+//
+// Solidity: function setReward(uint8 purpose, uint256 _reward) returns()
+func (_CrossDemo *CrossDemoSession) SetReward(purpose uint8, _reward *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.Contract.SetReward(&_CrossDemo.TransactOpts, purpose, _reward)
+}
+
+// SetReward is a paid mutator transaction binding the contract method 0x80033daf.
+//
+// Solidity: function setReward(uint8 purpose, uint256 _reward) returns()
+func (_CrossDemo *CrossDemoTransactorSession) SetReward(purpose uint8, _reward *big.Int) (*types.Transaction, error) {
+	return _CrossDemo.Contract.SetReward(&_CrossDemo.TransactOpts, purpose, _reward)
+}
+
+// SetSignConfirmCount is a paid mutator transaction binding the contract method 0x6af2dbe3.
+//
+// Solidity: function setSignConfirmCount(uint8 purpose, uint8 count) returns()
+func (_CrossDemo *CrossDemoTransactor) SetSignConfirmCount(opts *bind.TransactOpts, purpose uint8, count uint8) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "setSignConfirmCount", purpose, count)
+}
+
+// SetSignConfirmCount is a paid mutator transaction binding the contract method 0x6af2dbe3.
+//
+// Solidity: function setSignConfirmCount(uint8 purpose, uint8 count) returns()
+func (_CrossDemo *CrossDemoSession) SetSignConfirmCount(purpose uint8, count uint8) (*types.Transaction, error) {
+	return _CrossDemo.Contract.SetSignConfirmCount(&_CrossDemo.TransactOpts, purpose, count)
+}
+
+// SetSignConfirmCount is a paid mutator transaction binding the contract method 0x6af2dbe3.
+//
+// Solidity: function setSignConfirmCount(uint8 purpose, uint8 count) returns()
+func (_CrossDemo *CrossDemoTransactorSession) SetSignConfirmCount(purpose uint8, count uint8) (*types.Transaction, error) {
+	return _CrossDemo.Contract.SetSignConfirmCount(&_CrossDemo.TransactOpts, purpose, count)
+}
+
+// Taker is a paid mutator transaction binding the contract method 0xb7df03cc.
+//
+// Solidity: function taker(CrossStructOrder ctx, string to, bytes data) returns()
+func (_CrossDemo *CrossDemoTransactor) Taker(opts *bind.TransactOpts, ctx CrossStructOrder, to string, data []byte) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "taker", ctx, to, data)
+}
+
+// Taker is a paid mutator transaction binding the contract method 0xb7df03cc.
+//
+// Solidity: function taker(CrossStructOrder ctx, string to, bytes data) returns()
+func (_CrossDemo *CrossDemoSession) Taker(ctx CrossStructOrder, to string, data []byte) (*types.Transaction, error) {
+	return _CrossDemo.Contract.Taker(&_CrossDemo.TransactOpts, ctx, to, data)
+}
+
+// Taker is a paid mutator transaction binding the contract method 0xb7df03cc.
+//
+// Solidity: function taker(CrossStructOrder ctx, string to, bytes data) returns()
+func (_CrossDemo *CrossDemoTransactorSession) Taker(ctx CrossStructOrder, to string, data []byte) (*types.Transaction, error) {
+	return _CrossDemo.Contract.Taker(&_CrossDemo.TransactOpts, ctx, to, data)
+}
+
+// UpdateRouter is a paid mutator transaction binding the contract method 0xba0193d4.
+//
+// Solidity: function updateRouter(uint8 purpose, string _router) returns()
+func (_CrossDemo *CrossDemoTransactor) UpdateRouter(opts *bind.TransactOpts, purpose uint8, _router string) (*types.Transaction, error) {
+	return _CrossDemo.contract.Transact(opts, "updateRouter", purpose, _router)
+}
+
+// UpdateRouter is a paid mutator transaction binding the contract method 0xba0193d4.
+//
+// Solidity: function updateRouter(uint8 purpose, string _router) returns()
+func (_CrossDemo *CrossDemoSession) UpdateRouter(purpose uint8, _router string) (*types.Transaction, error) {
+	return _CrossDemo.Contract.UpdateRouter(&_CrossDemo.TransactOpts, purpose, _router)
+}
+
+// UpdateRouter is a paid mutator transaction binding the contract method 0xba0193d4.
+//
+// Solidity: function updateRouter(uint8 purpose, string _router) returns()
+func (_CrossDemo *CrossDemoTransactorSession) UpdateRouter(purpose uint8, _router string) (*types.Transaction, error) {
+	return _CrossDemo.Contract.UpdateRouter(&_CrossDemo.TransactOpts, purpose, _router)
+}
+
+// CrossDemoAccumulateRewardsIterator is returned from FilterAccumulateRewards and is used to iterate over the raw logs and unpacked data for AccumulateRewards events raised by the CrossDemo contract.
+type CrossDemoAccumulateRewardsIterator struct {
+	Event *CrossDemoAccumulateRewards // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CrossDemoAccumulateRewardsIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CrossDemoAccumulateRewards)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CrossDemoAccumulateRewards)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CrossDemoAccumulateRewardsIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CrossDemoAccumulateRewardsIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CrossDemoAccumulateRewards represents a AccumulateRewards event raised by the CrossDemo contract.
+type CrossDemoAccumulateRewards struct {
+	Purpose uint8
+	Anchor  common.Address
+	Reward  *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterAccumulateRewards is a free log retrieval operation binding the contract event 0xa85c9df2b080602d65330640cfb34e3ee866e77820f350d723f8ffbc33e459e5.
+//
+// Solidity: event AccumulateRewards(uint8 purpose, address anchor, uint256 reward)
+func (_CrossDemo *CrossDemoFilterer) FilterAccumulateRewards(opts *bind.FilterOpts) (*CrossDemoAccumulateRewardsIterator, error) {
+
+	logs, sub, err := _CrossDemo.contract.FilterLogs(opts, "AccumulateRewards")
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoAccumulateRewardsIterator{contract: _CrossDemo.contract, event: "AccumulateRewards", logs: logs, sub: sub}, nil
+}
+
+// WatchAccumulateRewards is a free log subscription operation binding the contract event 0xa85c9df2b080602d65330640cfb34e3ee866e77820f350d723f8ffbc33e459e5.
+//
+// Solidity: event AccumulateRewards(uint8 purpose, address anchor, uint256 reward)
+func (_CrossDemo *CrossDemoFilterer) WatchAccumulateRewards(opts *bind.WatchOpts, sink chan<- *CrossDemoAccumulateRewards) (event.Subscription, error) {
+
+	logs, sub, err := _CrossDemo.contract.WatchLogs(opts, "AccumulateRewards")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CrossDemoAccumulateRewards)
+				if err := _CrossDemo.contract.UnpackLog(event, "AccumulateRewards", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAccumulateRewards is a log parse operation binding the contract event 0xa85c9df2b080602d65330640cfb34e3ee866e77820f350d723f8ffbc33e459e5.
+//
+// Solidity: event AccumulateRewards(uint8 purpose, address anchor, uint256 reward)
+func (_CrossDemo *CrossDemoFilterer) ParseAccumulateRewards(log types.Log) (*CrossDemoAccumulateRewards, error) {
+	event := new(CrossDemoAccumulateRewards)
+	if err := _CrossDemo.contract.UnpackLog(event, "AccumulateRewards", log); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CrossDemoAddAnchorsIterator is returned from FilterAddAnchors and is used to iterate over the raw logs and unpacked data for AddAnchors events raised by the CrossDemo contract.
+type CrossDemoAddAnchorsIterator struct {
+	Event *CrossDemoAddAnchors // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CrossDemoAddAnchorsIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CrossDemoAddAnchors)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CrossDemoAddAnchors)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CrossDemoAddAnchorsIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CrossDemoAddAnchorsIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CrossDemoAddAnchors represents a AddAnchors event raised by the CrossDemo contract.
+type CrossDemoAddAnchors struct {
+	Purpose uint8
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterAddAnchors is a free log retrieval operation binding the contract event 0xbac577832a30a726fbfc3595b4b4d665dfdc2bfadd2dfd798beb566a28f3cc49.
+//
+// Solidity: event AddAnchors(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) FilterAddAnchors(opts *bind.FilterOpts) (*CrossDemoAddAnchorsIterator, error) {
+
+	logs, sub, err := _CrossDemo.contract.FilterLogs(opts, "AddAnchors")
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoAddAnchorsIterator{contract: _CrossDemo.contract, event: "AddAnchors", logs: logs, sub: sub}, nil
+}
+
+// WatchAddAnchors is a free log subscription operation binding the contract event 0xbac577832a30a726fbfc3595b4b4d665dfdc2bfadd2dfd798beb566a28f3cc49.
+//
+// Solidity: event AddAnchors(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) WatchAddAnchors(opts *bind.WatchOpts, sink chan<- *CrossDemoAddAnchors) (event.Subscription, error) {
+
+	logs, sub, err := _CrossDemo.contract.WatchLogs(opts, "AddAnchors")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CrossDemoAddAnchors)
+				if err := _CrossDemo.contract.UnpackLog(event, "AddAnchors", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseAddAnchors is a log parse operation binding the contract event 0xbac577832a30a726fbfc3595b4b4d665dfdc2bfadd2dfd798beb566a28f3cc49.
+//
+// Solidity: event AddAnchors(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) ParseAddAnchors(log types.Log) (*CrossDemoAddAnchors, error) {
+	event := new(CrossDemoAddAnchors)
+	if err := _CrossDemo.contract.UnpackLog(event, "AddAnchors", log); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CrossDemoMakerFinishIterator is returned from FilterMakerFinish and is used to iterate over the raw logs and unpacked data for MakerFinish events raised by the CrossDemo contract.
+type CrossDemoMakerFinishIterator struct {
+	Event *CrossDemoMakerFinish // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CrossDemoMakerFinishIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CrossDemoMakerFinish)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CrossDemoMakerFinish)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CrossDemoMakerFinishIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CrossDemoMakerFinishIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CrossDemoMakerFinish represents a MakerFinish event raised by the CrossDemo contract.
+type CrossDemoMakerFinish struct {
+	TxId [32]byte
+	To   common.Address
+	Raw  types.Log // Blockchain specific contextual infos
+}
+
+// FilterMakerFinish is a free log retrieval operation binding the contract event 0x8820cd26b97e4df882d1d4d25c269e58fe0f1c3eb05a864665c1d9b0cfd9e59f.
+//
+// Solidity: event MakerFinish(bytes32 txId, address to)
+func (_CrossDemo *CrossDemoFilterer) FilterMakerFinish(opts *bind.FilterOpts) (*CrossDemoMakerFinishIterator, error) {
+
+	logs, sub, err := _CrossDemo.contract.FilterLogs(opts, "MakerFinish")
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoMakerFinishIterator{contract: _CrossDemo.contract, event: "MakerFinish", logs: logs, sub: sub}, nil
+}
+
+// WatchMakerFinish is a free log subscription operation binding the contract event 0x8820cd26b97e4df882d1d4d25c269e58fe0f1c3eb05a864665c1d9b0cfd9e59f.
+//
+// Solidity: event MakerFinish(bytes32 txId, address to)
+func (_CrossDemo *CrossDemoFilterer) WatchMakerFinish(opts *bind.WatchOpts, sink chan<- *CrossDemoMakerFinish) (event.Subscription, error) {
+
+	logs, sub, err := _CrossDemo.contract.WatchLogs(opts, "MakerFinish")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CrossDemoMakerFinish)
+				if err := _CrossDemo.contract.UnpackLog(event, "MakerFinish", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseMakerFinish is a log parse operation binding the contract event 0x8820cd26b97e4df882d1d4d25c269e58fe0f1c3eb05a864665c1d9b0cfd9e59f.
+//
+// Solidity: event MakerFinish(bytes32 txId, address to)
+func (_CrossDemo *CrossDemoFilterer) ParseMakerFinish(log types.Log) (*CrossDemoMakerFinish, error) {
+	event := new(CrossDemoMakerFinish)
+	if err := _CrossDemo.contract.UnpackLog(event, "MakerFinish", log); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CrossDemoMakerTxIterator is returned from FilterMakerTx and is used to iterate over the raw logs and unpacked data for MakerTx events raised by the CrossDemo contract.
+type CrossDemoMakerTxIterator struct {
+	Event *CrossDemoMakerTx // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CrossDemoMakerTxIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CrossDemoMakerTx)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CrossDemoMakerTx)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CrossDemoMakerTxIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CrossDemoMakerTxIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CrossDemoMakerTx represents a MakerTx event raised by the CrossDemo contract.
+type CrossDemoMakerTx struct {
+	TxId      [32]byte
+	Value     *big.Int
+	DestValue *big.Int
+	From      string
+	To        string
+	Purpose   uint8
+	Payload   []byte
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterMakerTx is a free log retrieval operation binding the contract event 0x7077d55391204330e8e9f714f632753f266f6bbdaa441a2b922c8ae84a4355b5.
+//
+// Solidity: event MakerTx(bytes32 txId, uint256 value, uint256 destValue, string from, string to, uint8 purpose, bytes payload)
+func (_CrossDemo *CrossDemoFilterer) FilterMakerTx(opts *bind.FilterOpts) (*CrossDemoMakerTxIterator, error) {
+
+	logs, sub, err := _CrossDemo.contract.FilterLogs(opts, "MakerTx")
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoMakerTxIterator{contract: _CrossDemo.contract, event: "MakerTx", logs: logs, sub: sub}, nil
+}
+
+// WatchMakerTx is a free log subscription operation binding the contract event 0x7077d55391204330e8e9f714f632753f266f6bbdaa441a2b922c8ae84a4355b5.
+//
+// Solidity: event MakerTx(bytes32 txId, uint256 value, uint256 destValue, string from, string to, uint8 purpose, bytes payload)
+func (_CrossDemo *CrossDemoFilterer) WatchMakerTx(opts *bind.WatchOpts, sink chan<- *CrossDemoMakerTx) (event.Subscription, error) {
+
+	logs, sub, err := _CrossDemo.contract.WatchLogs(opts, "MakerTx")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CrossDemoMakerTx)
+				if err := _CrossDemo.contract.UnpackLog(event, "MakerTx", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseMakerTx is a log parse operation binding the contract event 0x7077d55391204330e8e9f714f632753f266f6bbdaa441a2b922c8ae84a4355b5.
+//
+// Solidity: event MakerTx(bytes32 txId, uint256 value, uint256 destValue, string from, string to, uint8 purpose, bytes payload)
+func (_CrossDemo *CrossDemoFilterer) ParseMakerTx(log types.Log) (*CrossDemoMakerTx, error) {
+	event := new(CrossDemoMakerTx)
+	if err := _CrossDemo.contract.UnpackLog(event, "MakerTx", log); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CrossDemoRemoveAnchorsIterator is returned from FilterRemoveAnchors and is used to iterate over the raw logs and unpacked data for RemoveAnchors events raised by the CrossDemo contract.
+type CrossDemoRemoveAnchorsIterator struct {
+	Event *CrossDemoRemoveAnchors // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CrossDemoRemoveAnchorsIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CrossDemoRemoveAnchors)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CrossDemoRemoveAnchors)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CrossDemoRemoveAnchorsIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CrossDemoRemoveAnchorsIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CrossDemoRemoveAnchors represents a RemoveAnchors event raised by the CrossDemo contract.
+type CrossDemoRemoveAnchors struct {
+	Purpose uint8
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterRemoveAnchors is a free log retrieval operation binding the contract event 0x83d618b28572a37f185aa3e2cd22a8b30a020a9fce5f724734be159ed58351f0.
+//
+// Solidity: event RemoveAnchors(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) FilterRemoveAnchors(opts *bind.FilterOpts) (*CrossDemoRemoveAnchorsIterator, error) {
+
+	logs, sub, err := _CrossDemo.contract.FilterLogs(opts, "RemoveAnchors")
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoRemoveAnchorsIterator{contract: _CrossDemo.contract, event: "RemoveAnchors", logs: logs, sub: sub}, nil
+}
+
+// WatchRemoveAnchors is a free log subscription operation binding the contract event 0x83d618b28572a37f185aa3e2cd22a8b30a020a9fce5f724734be159ed58351f0.
+//
+// Solidity: event RemoveAnchors(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) WatchRemoveAnchors(opts *bind.WatchOpts, sink chan<- *CrossDemoRemoveAnchors) (event.Subscription, error) {
+
+	logs, sub, err := _CrossDemo.contract.WatchLogs(opts, "RemoveAnchors")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CrossDemoRemoveAnchors)
+				if err := _CrossDemo.contract.UnpackLog(event, "RemoveAnchors", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRemoveAnchors is a log parse operation binding the contract event 0x83d618b28572a37f185aa3e2cd22a8b30a020a9fce5f724734be159ed58351f0.
+//
+// Solidity: event RemoveAnchors(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) ParseRemoveAnchors(log types.Log) (*CrossDemoRemoveAnchors, error) {
+	event := new(CrossDemoRemoveAnchors)
+	if err := _CrossDemo.contract.UnpackLog(event, "RemoveAnchors", log); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CrossDemoSetAnchorStatusIterator is returned from FilterSetAnchorStatus and is used to iterate over the raw logs and unpacked data for SetAnchorStatus events raised by the CrossDemo contract.
+type CrossDemoSetAnchorStatusIterator struct {
+	Event *CrossDemoSetAnchorStatus // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CrossDemoSetAnchorStatusIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CrossDemoSetAnchorStatus)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CrossDemoSetAnchorStatus)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CrossDemoSetAnchorStatusIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CrossDemoSetAnchorStatusIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CrossDemoSetAnchorStatus represents a SetAnchorStatus event raised by the CrossDemo contract.
+type CrossDemoSetAnchorStatus struct {
+	Purpose uint8
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterSetAnchorStatus is a free log retrieval operation binding the contract event 0xa69c14bd53cc09f8b4dae64e98dfc4d104189d6f16261c3803c34ff03a906f6d.
+//
+// Solidity: event SetAnchorStatus(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) FilterSetAnchorStatus(opts *bind.FilterOpts) (*CrossDemoSetAnchorStatusIterator, error) {
+
+	logs, sub, err := _CrossDemo.contract.FilterLogs(opts, "SetAnchorStatus")
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoSetAnchorStatusIterator{contract: _CrossDemo.contract, event: "SetAnchorStatus", logs: logs, sub: sub}, nil
+}
+
+// WatchSetAnchorStatus is a free log subscription operation binding the contract event 0xa69c14bd53cc09f8b4dae64e98dfc4d104189d6f16261c3803c34ff03a906f6d.
+//
+// Solidity: event SetAnchorStatus(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) WatchSetAnchorStatus(opts *bind.WatchOpts, sink chan<- *CrossDemoSetAnchorStatus) (event.Subscription, error) {
+
+	logs, sub, err := _CrossDemo.contract.WatchLogs(opts, "SetAnchorStatus")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CrossDemoSetAnchorStatus)
+				if err := _CrossDemo.contract.UnpackLog(event, "SetAnchorStatus", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseSetAnchorStatus is a log parse operation binding the contract event 0xa69c14bd53cc09f8b4dae64e98dfc4d104189d6f16261c3803c34ff03a906f6d.
+//
+// Solidity: event SetAnchorStatus(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) ParseSetAnchorStatus(log types.Log) (*CrossDemoSetAnchorStatus, error) {
+	event := new(CrossDemoSetAnchorStatus)
+	if err := _CrossDemo.contract.UnpackLog(event, "SetAnchorStatus", log); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CrossDemoTakerTxIterator is returned from FilterTakerTx and is used to iterate over the raw logs and unpacked data for TakerTx events raised by the CrossDemo contract.
+type CrossDemoTakerTxIterator struct {
+	Event *CrossDemoTakerTx // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CrossDemoTakerTxIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CrossDemoTakerTx)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CrossDemoTakerTx)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CrossDemoTakerTxIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CrossDemoTakerTxIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CrossDemoTakerTx represents a TakerTx event raised by the CrossDemo contract.
+type CrossDemoTakerTx struct {
+	TxId    [32]byte
+	From    common.Address
+	To      common.Address
+	Taker   string
+	Purpose uint8
+	Payload []byte
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterTakerTx is a free log retrieval operation binding the contract event 0x92dda5a44ae36a76961e9f0804469e7f2efd552c94882fa10f4b4677a475530c.
+//
+// Solidity: event TakerTx(bytes32 txId, address from, address to, string taker, uint8 purpose, bytes payload)
+func (_CrossDemo *CrossDemoFilterer) FilterTakerTx(opts *bind.FilterOpts) (*CrossDemoTakerTxIterator, error) {
+
+	logs, sub, err := _CrossDemo.contract.FilterLogs(opts, "TakerTx")
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoTakerTxIterator{contract: _CrossDemo.contract, event: "TakerTx", logs: logs, sub: sub}, nil
+}
+
+// WatchTakerTx is a free log subscription operation binding the contract event 0x92dda5a44ae36a76961e9f0804469e7f2efd552c94882fa10f4b4677a475530c.
+//
+// Solidity: event TakerTx(bytes32 txId, address from, address to, string taker, uint8 purpose, bytes payload)
+func (_CrossDemo *CrossDemoFilterer) WatchTakerTx(opts *bind.WatchOpts, sink chan<- *CrossDemoTakerTx) (event.Subscription, error) {
+
+	logs, sub, err := _CrossDemo.contract.WatchLogs(opts, "TakerTx")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CrossDemoTakerTx)
+				if err := _CrossDemo.contract.UnpackLog(event, "TakerTx", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseTakerTx is a log parse operation binding the contract event 0x92dda5a44ae36a76961e9f0804469e7f2efd552c94882fa10f4b4677a475530c.
+//
+// Solidity: event TakerTx(bytes32 txId, address from, address to, string taker, uint8 purpose, bytes payload)
+func (_CrossDemo *CrossDemoFilterer) ParseTakerTx(log types.Log) (*CrossDemoTakerTx, error) {
+	event := new(CrossDemoTakerTx)
+	if err := _CrossDemo.contract.UnpackLog(event, "TakerTx", log); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// CrossDemoUpdateRouterIterator is returned from FilterUpdateRouter and is used to iterate over the raw logs and unpacked data for UpdateRouter events raised by the CrossDemo contract.
+type CrossDemoUpdateRouterIterator struct {
+	Event *CrossDemoUpdateRouter // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *CrossDemoUpdateRouterIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(CrossDemoUpdateRouter)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(CrossDemoUpdateRouter)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *CrossDemoUpdateRouterIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *CrossDemoUpdateRouterIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// CrossDemoUpdateRouter represents a UpdateRouter event raised by the CrossDemo contract.
+type CrossDemoUpdateRouter struct {
+	Purpose uint8
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterUpdateRouter is a free log retrieval operation binding the contract event 0x08d46a49dc7084588d216b2d5136c7234b8ef63aced14f79b27d177cacc47a0b.
+//
+// Solidity: event UpdateRouter(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) FilterUpdateRouter(opts *bind.FilterOpts) (*CrossDemoUpdateRouterIterator, error) {
+
+	logs, sub, err := _CrossDemo.contract.FilterLogs(opts, "UpdateRouter")
+	if err != nil {
+		return nil, err
+	}
+	return &CrossDemoUpdateRouterIterator{contract: _CrossDemo.contract, event: "UpdateRouter", logs: logs, sub: sub}, nil
+}
+
+// WatchUpdateRouter is a free log subscription operation binding the contract event 0x08d46a49dc7084588d216b2d5136c7234b8ef63aced14f79b27d177cacc47a0b.
+//
+// Solidity: event UpdateRouter(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) WatchUpdateRouter(opts *bind.WatchOpts, sink chan<- *CrossDemoUpdateRouter) (event.Subscription, error) {
+
+	logs, sub, err := _CrossDemo.contract.WatchLogs(opts, "UpdateRouter")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(CrossDemoUpdateRouter)
+				if err := _CrossDemo.contract.UnpackLog(event, "UpdateRouter", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseUpdateRouter is a log parse operation binding the contract event 0x08d46a49dc7084588d216b2d5136c7234b8ef63aced14f79b27d177cacc47a0b.
+//
+// Solidity: event UpdateRouter(uint8 purpose)
+func (_CrossDemo *CrossDemoFilterer) ParseUpdateRouter(log types.Log) (*CrossDemoUpdateRouter, error) {
+	event := new(CrossDemoUpdateRouter)
+	if err := _CrossDemo.contract.UnpackLog(event, "UpdateRouter", log); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
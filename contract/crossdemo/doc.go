@@ -0,0 +1,5 @@
+// Package crossdemo contains the Go bindings of the crossdemo cross chain
+// contract, generated from crossDemo.abi and crossDemo.bin.
+package crossdemo
+
+//go:generate go run gen.go
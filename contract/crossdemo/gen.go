@@ -0,0 +1,56 @@
+//go:build ignore
+// +build ignore
+
+// gen generates the Go bindings of the crossdemo contract from crossDemo.abi
+// and crossDemo.bin. The abi parser of simplechain still reads the legacy
+// "constant" field, view and pure functions are marked constant first so they
+// are bound as calls instead of transactions.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/simplechain-org/go-simplechain/accounts/abi/bind"
+)
+
+func main() {
+	if err := generate("crossDemo.abi", "crossDemo.bin", "crossdemo.go"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func generate(abiFile, binFile, out string) error {
+	abiData, err := ioutil.ReadFile(abiFile)
+	if err != nil {
+		return err
+	}
+	var fields []map[string]interface{}
+	if err := json.Unmarshal(abiData, &fields); err != nil {
+		return fmt.Errorf("%s: %w", abiFile, err)
+	}
+	for _, field := range fields {
+		switch field["stateMutability"] {
+		case "view", "pure":
+			field["constant"] = true
+		}
+	}
+	abiJSON, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	bin, err := ioutil.ReadFile(binFile)
+	if err != nil {
+		return err
+	}
+	code, err := bind.Bind([]string{"CrossDemo"}, []string{string(abiJSON)}, []string{strings.TrimSpace(string(bin))},
+		nil, "crossdemo", bind.LangGo, nil, nil)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out, []byte(code), 0644)
+}
@@ -1,3 +1,7 @@
+//go:build ignore
+// +build ignore
+
+// readAbi prints crossDemo.abi hex encoded.
 package main
 
 import (
@@ -0,0 +1,57 @@
+package crossdemo
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/simplechain-org/go-simplechain/accounts/abi"
+	"github.com/simplechain-org/go-simplechain/accounts/abi/bind"
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/common/hexutil"
+	"github.com/simplechain-org/go-simplechain/core/types"
+	"github.com/simplechain-org/go-simplechain/rlp"
+	"github.com/simplechain-org/go-simplechain/rpc"
+)
+
+// NodeTransactOpts returns the options of transactions sent by from, which are
+// signed by the node with eth_signTransaction. from must be unlocked on the node.
+func NodeTransactOpts(client *rpc.Client, from common.Address) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(_ types.Signer, addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != from {
+				return nil, errors.New("not authorized to sign this account")
+			}
+			args := map[string]interface{}{
+				"from":     from,
+				"to":       tx.To(),
+				"gas":      hexutil.Uint64(tx.Gas()),
+				"gasPrice": (*hexutil.Big)(tx.GasPrice()),
+				"value":    (*hexutil.Big)(tx.Value()),
+				"nonce":    hexutil.Uint64(tx.Nonce()),
+				"input":    hexutil.Bytes(tx.Data()),
+			}
+			var result struct {
+				Raw hexutil.Bytes `json:"raw"`
+			}
+			if err := client.CallContext(context.Background(), &result, "eth_signTransaction", args); err != nil {
+				return nil, err
+			}
+			signed := new(types.Transaction)
+			if err := rlp.DecodeBytes(result.Raw, signed); err != nil {
+				return nil, err
+			}
+			return signed, nil
+		},
+	}
+}
+
+// PackMakerFinish returns the input data of the makerFinish call finishing rtx.
+func PackMakerFinish(rtx CrossStructRecept) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(CrossDemoABI))
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack("makerFinish", rtx)
+}
@@ -17,6 +17,9 @@
 package core
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/simplechain-org/go-simplechain"
 )
 
@@ -101,3 +104,98 @@ func (s *CtxStatus) UnmarshalText(input []byte) error {
 	}
 	return simplechain.NotFound
 }
+
+var ErrInvalidTransition = errors.New("invalid ctx status transition")
+
+// ErrStatusTransition is returned when a ctx status change is not allowed by
+// the state synchronization table above, or by the extra reorg steps.
+type ErrStatusTransition struct {
+	From CtxStatus
+	To   CtxStatus
+	Mod  ModType
+}
+
+func (e ErrStatusTransition) Error() string {
+	return fmt.Sprintf("%s: %s -> %s (%s)", ErrInvalidTransition, e.From, e.To, e.Mod)
+}
+
+func (e ErrStatusTransition) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// storeSyncTransitions lists the legal [S] steps, the key is the source status.
+var storeSyncTransitions = map[CtxStatus][]CtxStatus{
+	CtxStatusPending:   {CtxStatusWaiting},
+	CtxStatusWaiting:   {CtxStatusIllegal, CtxStatusExecuting},
+	CtxStatusIllegal:   {CtxStatusExecuting},
+	CtxStatusExecuting: {CtxStatusExecuted},
+	CtxStatusExecuted:  {CtxStatusFinishing},
+	CtxStatusFinishing: {CtxStatusFinished},
+}
+
+// reorgTransitions lists the legal [R] steps, the key is the source status.
+// A reorg drops the taker or the finish, the ctx returns to the status before it.
+// Besides the [R] steps of the table, a reorg may drop a confirmed step too,
+// the confirmation depth of a chain does not make its blocks final: Eed -> W
+// when the taker is dropped with its receipt, and Fed -> Eed when the finish
+// is dropped after it was confirmed.
+var reorgTransitions = map[CtxStatus][]CtxStatus{
+	CtxStatusExecuting: {CtxStatusWaiting},
+	CtxStatusExecuted:  {CtxStatusWaiting},
+	CtxStatusFinishing: {CtxStatusExecuted},
+	CtxStatusFinished:  {CtxStatusExecuted},
+}
+
+// ValidateTransition checks a single status step of a ctx. Keeping the same
+// status is always allowed, it happens when signatures are added to a ctx.
+func ValidateTransition(from, to CtxStatus, mod ModType) error {
+	if from == to {
+		return nil
+	}
+	table := storeSyncTransitions
+	if mod == Reorg {
+		table = reorgTransitions
+	}
+	for _, next := range table[from] {
+		if next == to {
+			return nil
+		}
+	}
+	return ErrStatusTransition{From: from, To: to, Mod: mod}
+}
+
+// TransitionPath returns the store sync steps leading from one status to another,
+// excluding from itself. It is used when an event on chain implies the steps
+// before it, e.g. a MakerFinish of a ctx whose receipt has not been seen.
+func TransitionPath(from, to CtxStatus) ([]CtxStatus, error) {
+	var (
+		path    []CtxStatus
+		current = from
+	)
+	for current != to {
+		next, ok := nextStatus(current, to)
+		if !ok {
+			return nil, ErrStatusTransition{From: from, To: to, Mod: Normal}
+		}
+		path = append(path, next)
+		current = next
+	}
+	return path, nil
+}
+
+// nextStatus picks the store sync step from current towards to, preferring the
+// direct step. Illegal is a dead end unless it is the target.
+func nextStatus(current, to CtxStatus) (CtxStatus, bool) {
+	steps := storeSyncTransitions[current]
+	for _, next := range steps {
+		if next == to {
+			return next, true
+		}
+	}
+	for _, next := range steps {
+		if next != CtxStatusIllegal {
+			return next, true
+		}
+	}
+	return current, false
+}
@@ -0,0 +1,69 @@
+// Copyright 2016 The go-simplechain Authors
+// This file is part of the go-simplechain library.
+//
+// The go-simplechain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-simplechain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-simplechain library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to CtxStatus
+		mod      ModType
+		ok       bool
+	}{
+		{CtxStatusPending, CtxStatusWaiting, Normal, true},
+		{CtxStatusWaiting, CtxStatusPending, Normal, false},
+		{CtxStatusWaiting, CtxStatusExecuting, Normal, true},
+		{CtxStatusExecuting, CtxStatusWaiting, Normal, false},
+		{CtxStatusExecuting, CtxStatusWaiting, Reorg, true},
+		{CtxStatusFinishing, CtxStatusExecuted, Reorg, true},
+		{CtxStatusFinished, CtxStatusExecuted, Reorg, true},
+		{CtxStatusFinished, CtxStatusWaiting, Reorg, false},
+		{CtxStatusFinished, CtxStatusFinishing, Normal, false},
+		{CtxStatusExecuted, CtxStatusExecuted, Normal, true},
+	}
+	for i, tt := range tests {
+		err := ValidateTransition(tt.from, tt.to, tt.mod)
+		if (err == nil) != tt.ok {
+			t.Errorf("test %d: %s -> %s (%s), want ok %v, got err %v", i, tt.from, tt.to, tt.mod, tt.ok, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("test %d: want ErrInvalidTransition, got %v", i, err)
+		}
+	}
+}
+
+func TestTransitionPath(t *testing.T) {
+	path, err := TransitionPath(CtxStatusWaiting, CtxStatusFinished)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CtxStatus{CtxStatusExecuting, CtxStatusExecuted, CtxStatusFinishing, CtxStatusFinished}
+	if len(path) != len(want) {
+		t.Fatalf("path length, want: %d, got: %d", len(want), len(path))
+	}
+	for i := range want {
+		if path[i] != want[i] {
+			t.Fatalf("path[%d], want: %s, got: %s", i, want[i], path[i])
+		}
+	}
+	if _, err := TransitionPath(CtxStatusFinished, CtxStatusWaiting); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("want ErrInvalidTransition, got %v", err)
+	}
+}
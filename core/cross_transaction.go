@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"errors"
 	"math/big"
+	"sync"
 	"sync/atomic"
 
 	"github.com/simplechain-org/go-simplechain/common"
@@ -234,215 +235,282 @@ func (s *CTxByPrice) Pop() interface{} {
 	return x
 }
 
-//type CrossTransactionWithSignatures struct {
-//	Data     CtxDatas
-//	Status   CtxStatus `json:"status" gencodec:"required"` // default = pending
-//	BlockNum uint64    `json:"blockNum" gencodec:"required"`
-//
-//	// caches
-//	hash atomic.Value
-//	size atomic.Value
-//	from atomic.Value
-//	lock sync.RWMutex
-//}
-//
-//type CtxDatas struct {
-//	CTxId     common.Hash `json:"ctxId" gencodec:"required"` //cross_transaction ID
-//	TxHash    common.Hash `json:"txHash" gencodec:"required"`
-//	BlockHash common.Hash `json:"blockHash" gencodec:"required"`        //The Hash of block in which the message resides
-//	Value     *big.Int    `json:"value" gencodec:"required"` //Token for sell
-//	Charge    *big.Int    `json:"charge" gencodec:"required"`
-//	From      string      `json:"from" gencodec:"required"`             //Token owner
-//	To        string      `json:"to" gencodec:"required"`               //Token to
-//	Origin    uint8       `json:"origin" gencodec:"required"`
-//	Purpose   uint8       `json:"purpose" gencodec:"required"` //Message destination networkId
-//	Payload   []byte      `json:"payload"    gencodec:"required"`
-//
-//	// Signature values
-//	V []*big.Int `json:"v" gencodec:"required"` //chainId
-//	R []*big.Int `json:"r" gencodec:"required"`
-//	S []*big.Int `json:"s" gencodec:"required"`
-//}
-//
-//func NewCrossTransactionWithSignatures(ctx *CrossTransaction, num uint64) *CrossTransactionWithSignatures {
-//	d := CtxDatas{
-//		Value:     ctx.Data.Value,
-//		CTxId:     ctx.Data.CTxId,
-//		TxHash:    ctx.Data.TxHash,
-//		From:      ctx.Data.From,
-//		To:        ctx.Data.To,
-//		BlockHash: ctx.Data.BlockHash,
-//		Origin:    ctx.Data.Origin,
-//		Purpose:   ctx.Data.Purpose,
-//		Charge:    ctx.Data.Charge,
-//		Payload:   ctx.Data.Payload,
-//	}
-//
-//	if ctx.Data.V != nil && ctx.Data.R != nil && ctx.Data.S != nil {
-//		d.V = append(d.V, ctx.Data.V)
-//		d.R = append(d.R, ctx.Data.R)
-//		d.S = append(d.S, ctx.Data.S)
-//	}
-//
-//	return &CrossTransactionWithSignatures{Data: d, BlockNum: num}
-//}
-//
-//func (cws *CrossTransactionWithSignatures) ID() common.Hash {
-//	return cws.Data.CTxId
-//}
-//
-//func (cws *CrossTransactionWithSignatures) ChainId() *big.Int {
-//	cws.lock.RLock()
-//	defer cws.lock.RUnlock()
-//	if cws.signaturesLength() > 0 {
-//		return types.DeriveChainId(cws.Data.V[0])
-//	}
-//	return big.NewInt(0)
-//}
-//func (cws *CrossTransactionWithSignatures) Destination() uint8 {
-//	return cws.Data.Purpose
-//}
-//
-//func (cws *CrossTransactionWithSignatures) Hash() (h common.Hash) {
-//	if hash := cws.hash.Load(); hash != nil {
-//		return hash.(common.Hash)
-//	}
-//	hash := sha3.NewKeccak256()
-//	var b []byte
-//	b = append(b, cws.Data.CTxId.Bytes()...)
-//	b = append(b, cws.Data.TxHash.Bytes()...)
-//	b = append(b, cws.Data.BlockHash.Bytes()...)
-//	b = append(b, common.LeftPadBytes(cws.Data.Value.Bytes(), 32)...)
-//	b = append(b, common.LeftPadBytes(cws.Data.Charge.Bytes(), 32)...)
-//	b = append(b, cws.Data.From...)
-//	b = append(b, cws.Data.To...)
-//	b = append(b, cws.Data.Origin)
-//	b = append(b, cws.Data.Purpose)
-//	b = append(b, cws.Data.Payload...)
-//	hash.Write(b)
-//	hash.Sum(h[:0])
-//	cws.hash.RemoteStore(h)
-//	return h
-//}
-//
-//func (cws *CrossTransactionWithSignatures) BlockHash() common.Hash {
-//	return cws.Data.BlockHash
-//}
-//
-//func (cws *CrossTransactionWithSignatures) From() string {
-//	return cws.Data.From
-//}
-//
-//func (cws *CrossTransactionWithSignatures) SetStatus(status CtxStatus) {
-//	cws.Status = status
-//}
-//
-//func (cws *CrossTransactionWithSignatures) AddSignature(ctx *CrossTransaction) error {
-//	if cws.Hash() != ctx.Hash() {
-//		return ErrInvalidSign
-//	}
-//	cws.lock.Lock()
-//	defer cws.lock.Unlock()
-//	for _, r := range cws.Data.R {
-//		if r.Cmp(ctx.Data.R) == 0 {
-//			return ErrDuplicateSign
-//		}
-//	}
-//	cws.Data.V = append(cws.Data.V, ctx.Data.V)
-//	cws.Data.R = append(cws.Data.R, ctx.Data.R)
-//	cws.Data.S = append(cws.Data.S, ctx.Data.S)
-//	return nil
-//}
-//func (cws *CrossTransactionWithSignatures) RemoveSignature(index int) {
-//	cws.lock.Lock()
-//	defer cws.lock.Unlock()
-//	if index < cws.signaturesLength() {
-//		cws.Data.V = append(cws.Data.V[:index], cws.Data.V[index+1:]...)
-//		cws.Data.R = append(cws.Data.R[:index], cws.Data.R[index+1:]...)
-//		cws.Data.S = append(cws.Data.S[:index], cws.Data.S[index+1:]...)
-//	}
-//}
-//
-//func (cws *CrossTransactionWithSignatures) SignaturesLength() int {
-//	cws.lock.RLock()
-//	defer cws.lock.RUnlock()
-//	return cws.signaturesLength()
-//}
-//func (cws *CrossTransactionWithSignatures) signaturesLength() int {
-//	l := len(cws.Data.V)
-//	if l == len(cws.Data.R) && l == len(cws.Data.V) {
-//		return l
-//	}
-//	return 0
-//}
-//
-//func (cws *CrossTransactionWithSignatures) CrossTransaction() *CrossTransaction {
-//	return &CrossTransaction{
-//		Data: ctxdata{
-//			Value:     cws.Data.Value,
-//			CTxId:     cws.Data.CTxId,
-//			TxHash:    cws.Data.TxHash,
-//			From:      cws.Data.From,
-//			To:        cws.Data.To,
-//			BlockHash: cws.Data.BlockHash,
-//			Origin:    cws.Data.Origin,
-//			Purpose:   cws.Data.Purpose,
-//			Charge:    cws.Data.Charge,
-//			Payload:   cws.Data.Payload,
-//		},
-//	}
-//}
-//
-//func (cws *CrossTransactionWithSignatures) Resolution() []*CrossTransaction {
-//	cws.lock.RLock()
-//	defer cws.lock.RUnlock()
-//	l := cws.signaturesLength()
-//	var ctxs []*CrossTransaction
-//	for i := 0; i < l; i++ {
-//		ctxs = append(ctxs, &CrossTransaction{
-//			Data: ctxdata{
-//				Value:     cws.Data.Value,
-//				CTxId:     cws.Data.CTxId,
-//				TxHash:    cws.Data.TxHash,
-//				From:      cws.Data.From,
-//				To:        cws.Data.To,
-//				BlockHash: cws.Data.BlockHash,
-//				Origin:    cws.Data.Origin,
-//				Purpose:   cws.Data.Purpose,
-//				Charge:    cws.Data.Charge,
-//				Payload:   cws.Data.Payload,
-//				V:         cws.Data.V[i],
-//				R:         cws.Data.R[i],
-//				S:         cws.Data.S[i],
-//			},
-//		})
-//	}
-//	return ctxs
-//}
-//
-//func (cws *CrossTransactionWithSignatures) Price() *big.Rat {
-//	if cws.Data.Value.Cmp(common.Big0) == 0 {
-//		return new(big.Rat).SetUint64(math.MaxUint64) // set a max rat
-//	}
-//	return new(big.Rat).SetFrac(cws.Data.Charge, cws.Data.Value)
-//}
-//
-//func (cws *CrossTransactionWithSignatures) Size() common.StorageSize {
-//	if size := cws.size.Load(); size != nil {
-//		return size.(common.StorageSize)
-//	}
-//	c := types.WriteCounter(0)
-//	rlp.Encode(&c, &cws.Data)
-//	cws.size.RemoteStore(common.StorageSize(c))
-//	return common.StorageSize(c)
-//}
-//
-//type RemoteChainInfo struct {
-//	RemoteChainId uint64
-//	BlockNumber   uint64
-//}
-//
-//type OwnerCrossTransactionWithSignatures struct {
-//	Cws  *CrossTransactionWithSignatures
-//	Time uint64
-//}
+
+type CrossTransactionWithSignatures struct {
+	Data     CtxDatas
+	Status   CtxStatus `json:"status" gencodec:"required"` // default = pending
+	BlockNum uint64    `json:"blockNum" gencodec:"required"`
+
+	// caches
+	hash atomic.Value
+	size atomic.Value
+	lock sync.RWMutex
+}
+
+type CtxDatas struct {
+	CTxId     common.Hash `json:"ctxId" gencodec:"required"` //cross_transaction ID
+	TxHash    common.Hash `json:"txHash" gencodec:"required"`
+	BlockHash common.Hash `json:"blockHash" gencodec:"required"` //The Hash of block in which the message resides
+	Value     *big.Int    `json:"value" gencodec:"required"`     //Token for sell
+	Charge    *big.Int    `json:"charge" gencodec:"required"`
+	From      string      `json:"from" gencodec:"required"` //Token owner
+	To        string      `json:"to" gencodec:"required"`   //Token to
+	Origin    uint8       `json:"origin" gencodec:"required"`
+	Purpose   uint8       `json:"purpose" gencodec:"required"` //Message destination networkId
+	Payload   []byte      `json:"payload"    gencodec:"required"`
+
+	// Signature values, ordered by arrival
+	V []*big.Int `json:"v" gencodec:"required"` //chainId
+	R []*big.Int `json:"r" gencodec:"required"`
+	S []*big.Int `json:"s" gencodec:"required"`
+}
+
+func NewCrossTransactionWithSignatures(ctx *CrossTransaction, num uint64) *CrossTransactionWithSignatures {
+	d := CtxDatas{
+		CTxId:     ctx.Data.CTxId,
+		TxHash:    ctx.Data.TxHash,
+		BlockHash: ctx.Data.BlockHash,
+		Value:     ctx.Data.Value,
+		Charge:    ctx.Data.Charge,
+		From:      ctx.Data.From,
+		To:        ctx.Data.To,
+		Origin:    ctx.Data.Origin,
+		Purpose:   ctx.Data.Purpose,
+		Payload:   ctx.Data.Payload,
+	}
+
+	if isSigned(ctx) {
+		d.V = append(d.V, ctx.Data.V)
+		d.R = append(d.R, ctx.Data.R)
+		d.S = append(d.S, ctx.Data.S)
+	}
+
+	return &CrossTransactionWithSignatures{Data: d, BlockNum: num}
+}
+
+// isSigned reports whether the ctx carries a signature, unsigned ctxs are
+// created with zero V, R and S.
+func isSigned(ctx *CrossTransaction) bool {
+	return ctx.Data.V != nil && ctx.Data.R != nil && ctx.Data.S != nil && ctx.Data.R.Sign() != 0
+}
+
+func (cws *CrossTransactionWithSignatures) ID() common.Hash {
+	return cws.Data.CTxId
+}
+
+func (cws *CrossTransactionWithSignatures) ChainId() *big.Int {
+	cws.lock.RLock()
+	defer cws.lock.RUnlock()
+	if cws.signaturesLength() > 0 {
+		return types.DeriveChainId(cws.Data.V[0])
+	}
+	return big.NewInt(0)
+}
+
+func (cws *CrossTransactionWithSignatures) Destination() uint8 {
+	return cws.Data.Purpose
+}
+
+// Hash returns the hash of the ctx content without signatures, it equals to
+// CrossTransaction.Hash of every signed ctx this set is built from.
+func (cws *CrossTransactionWithSignatures) Hash() (h common.Hash) {
+	if hash := cws.hash.Load(); hash != nil {
+		return hash.(common.Hash)
+	}
+	hash := sha3.NewKeccak256()
+	var b []byte
+	b = append(b, cws.Data.CTxId.Bytes()...)
+	b = append(b, cws.Data.TxHash.Bytes()...)
+	b = append(b, cws.Data.BlockHash.Bytes()...)
+	b = append(b, common.LeftPadBytes(cws.Data.Value.Bytes(), 32)...)
+	b = append(b, common.LeftPadBytes(cws.Data.Charge.Bytes(), 32)...)
+	b = append(b, cws.Data.From...)
+	b = append(b, cws.Data.To...)
+	b = append(b, cws.Data.Origin)
+	b = append(b, cws.Data.Purpose)
+	b = append(b, cws.Data.Payload...)
+	hash.Write(b)
+	hash.Sum(h[:0])
+	cws.hash.Store(h)
+	return h
+}
+
+func (cws *CrossTransactionWithSignatures) BlockHash() common.Hash {
+	return cws.Data.BlockHash
+}
+
+func (cws *CrossTransactionWithSignatures) From() string {
+	return cws.Data.From
+}
+
+func (cws *CrossTransactionWithSignatures) SetStatus(status CtxStatus) {
+	cws.Status = status
+}
+
+// AddSignature appends the signature of ctx to the set. It returns ErrInvalidSign
+// if ctx is not the same cross transaction, and ErrDuplicateSign if the signature
+// has already been collected.
+func (cws *CrossTransactionWithSignatures) AddSignature(ctx *CrossTransaction) error {
+	if cws.Hash() != ctx.Hash() {
+		return ErrInvalidSign
+	}
+	if !isSigned(ctx) {
+		return ErrInvalidSign
+	}
+	cws.lock.Lock()
+	defer cws.lock.Unlock()
+	for _, r := range cws.Data.R {
+		if r.Cmp(ctx.Data.R) == 0 {
+			return ErrDuplicateSign
+		}
+	}
+	cws.Data.V = append(cws.Data.V, ctx.Data.V)
+	cws.Data.R = append(cws.Data.R, ctx.Data.R)
+	cws.Data.S = append(cws.Data.S, ctx.Data.S)
+	cws.size.Store(common.StorageSize(0))
+	return nil
+}
+
+// Merge adds every signature of other that is not yet in the set, keeping the
+// existing order. It returns the number of signatures added.
+func (cws *CrossTransactionWithSignatures) Merge(other *CrossTransactionWithSignatures) (int, error) {
+	if cws.Hash() != other.Hash() {
+		return 0, ErrInvalidSign
+	}
+	var added int
+	for _, ctx := range other.Resolution() {
+		switch err := cws.AddSignature(ctx); err {
+		case nil:
+			added++
+		case ErrDuplicateSign:
+		default:
+			return added, err
+		}
+	}
+	return added, nil
+}
+
+func (cws *CrossTransactionWithSignatures) RemoveSignature(index int) {
+	cws.lock.Lock()
+	defer cws.lock.Unlock()
+	if index < cws.signaturesLength() {
+		cws.Data.V = append(cws.Data.V[:index], cws.Data.V[index+1:]...)
+		cws.Data.R = append(cws.Data.R[:index], cws.Data.R[index+1:]...)
+		cws.Data.S = append(cws.Data.S[:index], cws.Data.S[index+1:]...)
+		cws.size.Store(common.StorageSize(0))
+	}
+}
+
+func (cws *CrossTransactionWithSignatures) SignaturesLength() int {
+	cws.lock.RLock()
+	defer cws.lock.RUnlock()
+	return cws.signaturesLength()
+}
+
+func (cws *CrossTransactionWithSignatures) signaturesLength() int {
+	l := len(cws.Data.V)
+	if l == len(cws.Data.R) && l == len(cws.Data.S) {
+		return l
+	}
+	return 0
+}
+
+// Signers recovers the signer of every signature with sender and returns the
+// distinct addresses in signing order. Signatures that can not be recovered
+// are skipped.
+func (cws *CrossTransactionWithSignatures) Signers(sender func(*CrossTransaction) (common.Address, error)) []common.Address {
+	var (
+		signers []common.Address
+		seen    = make(map[common.Address]struct{})
+	)
+	for _, ctx := range cws.Resolution() {
+		addr, err := sender(ctx)
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		seen[addr] = struct{}{}
+		signers = append(signers, addr)
+	}
+	return signers
+}
+
+// SignerCount returns how many distinct anchors signed the cross transaction.
+func (cws *CrossTransactionWithSignatures) SignerCount(sender func(*CrossTransaction) (common.Address, error)) int {
+	return len(cws.Signers(sender))
+}
+
+// SignatureValues returns the signatures in the layout expected by the cross
+// contract, R and S are left padded to 32 bytes.
+func (cws *CrossTransactionWithSignatures) SignatureValues() (v []*big.Int, r [][32]byte, s [][32]byte) {
+	cws.lock.RLock()
+	defer cws.lock.RUnlock()
+	l := cws.signaturesLength()
+	v, r, s = make([]*big.Int, 0, l), make([][32]byte, 0, l), make([][32]byte, 0, l)
+	for i := 0; i < l; i++ {
+		var rb, sb [32]byte
+		copy(rb[:], common.LeftPadBytes(cws.Data.R[i].Bytes(), 32))
+		copy(sb[:], common.LeftPadBytes(cws.Data.S[i].Bytes(), 32))
+		v = append(v, cws.Data.V[i])
+		r = append(r, rb)
+		s = append(s, sb)
+	}
+	return v, r, s
+}
+
+func (cws *CrossTransactionWithSignatures) CrossTransaction() *CrossTransaction {
+	return &CrossTransaction{
+		Data: ctxdata{
+			CTxId:     cws.Data.CTxId,
+			TxHash:    cws.Data.TxHash,
+			BlockHash: cws.Data.BlockHash,
+			Value:     cws.Data.Value,
+			Charge:    cws.Data.Charge,
+			From:      cws.Data.From,
+			To:        cws.Data.To,
+			Origin:    cws.Data.Origin,
+			Purpose:   cws.Data.Purpose,
+			Payload:   cws.Data.Payload,
+			V:         new(big.Int),
+			R:         new(big.Int),
+			S:         new(big.Int),
+		},
+	}
+}
+
+// Resolution splits the set into single signed cross transactions.
+func (cws *CrossTransactionWithSignatures) Resolution() []*CrossTransaction {
+	cws.lock.RLock()
+	defer cws.lock.RUnlock()
+	l := cws.signaturesLength()
+	var ctxs []*CrossTransaction
+	for i := 0; i < l; i++ {
+		ctx := cws.CrossTransaction()
+		ctx.Data.V, ctx.Data.R, ctx.Data.S = cws.Data.V[i], cws.Data.R[i], cws.Data.S[i]
+		ctxs = append(ctxs, ctx)
+	}
+	return ctxs
+}
+
+func (cws *CrossTransactionWithSignatures) Price() *big.Rat {
+	if cws.Data.Value.Cmp(common.Big0) == 0 {
+		return new(big.Rat).SetUint64(math.MaxUint64) // set a max rat
+	}
+	return new(big.Rat).SetFrac(cws.Data.Charge, cws.Data.Value)
+}
+
+// Size returns the encoded size of the ctx. A zero size in the cache means it
+// was invalidated by a change of the signatures.
+func (cws *CrossTransactionWithSignatures) Size() common.StorageSize {
+	if size := cws.size.Load(); size != nil && size.(common.StorageSize) != 0 {
+		return size.(common.StorageSize)
+	}
+	cws.lock.RLock()
+	defer cws.lock.RUnlock()
+	c := types.WriteCounter(0)
+	rlp.Encode(&c, &cws.Data)
+	cws.size.Store(common.StorageSize(c))
+	return common.StorageSize(c)
+}
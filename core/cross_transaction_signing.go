@@ -89,6 +89,9 @@ type CtxSigner interface {
 	Equal(CtxSigner) bool
 
 	SimpleHash(tx *CrossTransaction) common.Hash
+	// SimpleSender returns the address of the signature made over SimpleHash,
+	// which is the form verified by the cross contract.
+	SimpleSender(tx *CrossTransaction) (common.Address, error)
 }
 
 // EIP155Transaction implements Signer using the EIP155 rules.
@@ -120,6 +123,15 @@ func (s EIP155CtxSigner) Sender(tx *CrossTransaction) (common.Address, error) {
 	return types.RecoverPlain(s.Hash(tx), tx.Data.R, tx.Data.S, V, true)
 }
 
+func (s EIP155CtxSigner) SimpleSender(tx *CrossTransaction) (common.Address, error) {
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, types.ErrInvalidChainId
+	}
+	V := new(big.Int).Sub(tx.Data.V, s.chainIdMul)
+	V.Sub(V, big8)
+	return types.RecoverPlain(s.SimpleHash(tx), tx.Data.R, tx.Data.S, V, true)
+}
+
 // WithSignature returns a new transaction with the given signature. This signature
 // needs to be in the [R || S || V] format where V is 0 or 1.
 func (s EIP155CtxSigner) SignatureValues(tx *CrossTransaction, sig []byte) (R, S, V *big.Int, err error) {
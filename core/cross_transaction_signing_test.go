@@ -36,12 +36,13 @@ func TestEIP155CtxSigning(t *testing.T) {
 	signer := NewEIP155CtxSigner(big.NewInt(18))
 	tx, err := SignCtx(NewCrossTransaction(big.NewInt(1e18),
 		big.NewInt(2e18),
-		big.NewInt(19),
+		addr.Hex(),
+		"",
+		1,
+		2,
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
-		addr,
-		common.Address{},
 		nil),
 		signer, signHash)
 	if err != nil {
@@ -66,12 +67,13 @@ func TestEIP155CtxChainId(t *testing.T) {
 	signer := NewEIP155CtxSigner(big.NewInt(18))
 	tx, err := SignCtx(NewCrossTransaction(big.NewInt(1e18),
 		big.NewInt(2e18),
-		big.NewInt(19),
+		addr.Hex(),
+		"",
+		1,
+		2,
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
-		addr,
-		common.Address{},
 		nil),
 		signer, signHash)
 	if err != nil {
@@ -91,12 +93,13 @@ func TestCtxChainId(t *testing.T) {
 
 	tx := NewCrossTransaction(big.NewInt(1e18),
 		big.NewInt(2e18),
-		big.NewInt(19),
+		"",
+		"",
+		1,
+		2,
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
-		common.Address{},
-		common.Address{},
 		nil)
 
 	var err error
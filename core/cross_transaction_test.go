@@ -31,37 +31,39 @@ var (
 	emptyCtx = NewCrossTransaction(
 		big.NewInt(0),
 		big.NewInt(0),
-		big.NewInt(1024),
+		"0x095e7baea6a6c7c4c2dfeb977efac326af552d87",
+		"",
+		1,
+		2,
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
-		common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87"),
-		common.Address{},
 		nil,
 	)
 
 	rightvrsCtx, _ = NewCrossTransaction(
 		big.NewInt(1e18),
 		big.NewInt(2e18),
-		big.NewInt(1024),
+		"0x095e7baea6a6c7c4c2dfeb977efac326af552d87",
+		"",
+		1,
+		2,
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
 		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
-		common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87"),
-		common.Address{},
 		nil,
 	).WithSignature(
 		NewEIP155CtxSigner(big.NewInt(1)),
-		common.Hex2Bytes("fff9e65e751407a69c5125a0e0dafd2e0048ce9b60d39bb0b58c251b4a72d3822005750b091faae17b20a0a966b0c40ca44134fb214d0df0fed1c10646141f7000"),
+		common.Hex2Bytes("ac0a1bbca3c9aefc1a9729e25df9a96d0dd20283a3c4550c837106b3c89e9fee35fb7c8515185142ff7aec29f4b2bf7876cf50ed207806643d2ca4c11663a6e600"),
 	)
 )
 
 func TestCrossTransactionSigHash(t *testing.T) {
 	signer := NewEIP155CtxSigner(big.NewInt(1))
-	if signer.Hash(emptyCtx) != common.HexToHash("4f4a4cb2a67de52c0036f3ab5046388144471c13d42d62e2390e0c6eaa7e3d50") {
+	if signer.Hash(emptyCtx) != common.HexToHash("72fdf6f886624b1a2cbb601de982667d1cb00bc46201581549bcf602f02ffbc8") {
 		t.Errorf("empty transaction hash mismatch, got %x", emptyCtx.Hash())
 	}
-	if signer.Hash(rightvrsCtx) != common.HexToHash("ba84e225625b9b69304e867a2c9af871b37086b7238190dd44dbd57e5429304e") {
+	if signer.Hash(rightvrsCtx) != common.HexToHash("5144a88e1e56ee326f2f820fcf678105db11c233ed00c85b7362f8224b5cc6a4") {
 		t.Errorf("RightVRS transaction hash mismatch, got %x", rightvrsCtx.Hash())
 	}
 }
@@ -71,7 +73,7 @@ func TestCrossTransactionEncode(t *testing.T) {
 	if err != nil {
 		t.Fatalf("encode error: %v", err)
 	}
-	should := common.FromHex("f8e8f8e6880de0b6b3a7640000a00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbcaa00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca94095e7baea6a6c7c4c2dfeb977efac326af552d87940000000000000000000000000000000000000000a00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca820400881bc16d674ec800008025a0fff9e65e751407a69c5125a0e0dafd2e0048ce9b60d39bb0b58c251b4a72d382a02005750b091faae17b20a0a966b0c40ca44134fb214d0df0fed1c10646141f70")
+	should := common.FromHex("f8e9f8e7a00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbcaa00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbcaa00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca880de0b6b3a7640000881bc16d674ec80000aa3078303935653762616561366136633763346332646665623937376566616333323661663535326438378001028025a0ac0a1bbca3c9aefc1a9729e25df9a96d0dd20283a3c4550c837106b3c89e9feea035fb7c8515185142ff7aec29f4b2bf7876cf50ed207806643d2ca4c11663a6e6")
 	if !bytes.Equal(ctxb, should) {
 		t.Errorf("encoded RLP mismatch, got %x", ctxb)
 	}
@@ -92,7 +94,7 @@ func defaultTestKey() (*ecdsa.PrivateKey, common.Address) {
 
 func TestCtxRecipient(t *testing.T) {
 	_, addr := defaultTestKey()
-	tx, err := decodeCtx(common.Hex2Bytes("f8e8f8e6880de0b6b3a7640000a00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbcaa00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca94095e7baea6a6c7c4c2dfeb977efac326af552d87940000000000000000000000000000000000000000a00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca820400881bc16d674ec800008025a0fff9e65e751407a69c5125a0e0dafd2e0048ce9b60d39bb0b58c251b4a72d382a02005750b091faae17b20a0a966b0c40ca44134fb214d0df0fed1c10646141f70"))
+	tx, err := decodeCtx(common.Hex2Bytes("f8e9f8e7a00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbcaa00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbcaa00b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca880de0b6b3a7640000881bc16d674ec80000aa3078303935653762616561366136633763346332646665623937376566616333323661663535326438378001028025a0ac0a1bbca3c9aefc1a9729e25df9a96d0dd20283a3c4550c837106b3c89e9feea035fb7c8515185142ff7aec29f4b2bf7876cf50ed207806643d2ca4c11663a6e6"))
 	if err != nil {
 		t.Error(err)
 		t.FailNow()
@@ -106,3 +108,134 @@ func TestCtxRecipient(t *testing.T) {
 		t.Error("derived address doesn't match")
 	}
 }
+
+func newSignedCtx(t *testing.T, signer CtxSigner, key *ecdsa.PrivateKey) *CrossTransaction {
+	tx := NewCrossTransaction(big.NewInt(1e18),
+		big.NewInt(2e18),
+		"0x095e7baea6a6c7c4c2dfeb977efac326af552d87",
+		"",
+		2,
+		5,
+		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
+		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
+		common.HexToHash("0b2aa4c82a3b0187a087e030a26b71fc1a49e74d3776ae8e03876ea9153abbca"),
+		nil)
+	signed, err := SignSimpleCtx(tx, signer, func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestCrossTransactionWithSignatures_AddSignature(t *testing.T) {
+	signer := NewEIP155CtxSigner(big.NewInt(2))
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+
+	ctx1 := newSignedCtx(t, signer, key1)
+	cws := NewCrossTransactionWithSignatures(ctx1, 1)
+	if cws.SignaturesLength() != 1 {
+		t.Fatalf("signatures length mismatch, got %d want 1", cws.SignaturesLength())
+	}
+	if err := cws.AddSignature(ctx1); err != ErrDuplicateSign {
+		t.Errorf("expected error: %v, got %v", ErrDuplicateSign, err)
+	}
+	if err := cws.AddSignature(newSignedCtx(t, signer, key2)); err != nil {
+		t.Fatal(err)
+	}
+
+	other := newSignedCtx(t, signer, key2)
+	other.Data.Value = big.NewInt(1)
+	if err := cws.AddSignature(other); err != ErrInvalidSign {
+		t.Errorf("expected error: %v, got %v", ErrInvalidSign, err)
+	}
+
+	signers := cws.Signers(signer.SimpleSender)
+	if len(signers) != 2 {
+		t.Fatalf("signers mismatch, got %d want 2", len(signers))
+	}
+	if signers[0] != crypto.PubkeyToAddress(key1.PublicKey) || signers[1] != crypto.PubkeyToAddress(key2.PublicKey) {
+		t.Errorf("signers order mismatch, got %v", signers)
+	}
+}
+
+func TestCrossTransactionWithSignatures_Merge(t *testing.T) {
+	signer := NewEIP155CtxSigner(big.NewInt(2))
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	key3, _ := crypto.GenerateKey()
+
+	local := NewCrossTransactionWithSignatures(newSignedCtx(t, signer, key1), 1)
+	local.AddSignature(newSignedCtx(t, signer, key2))
+
+	remote := NewCrossTransactionWithSignatures(newSignedCtx(t, signer, key2), 1)
+	remote.AddSignature(newSignedCtx(t, signer, key3))
+
+	added, err := local.Merge(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 1 {
+		t.Errorf("added mismatch, got %d want 1", added)
+	}
+	if count := local.SignerCount(signer.SimpleSender); count != 3 {
+		t.Errorf("signer count mismatch, got %d want 3", count)
+	}
+}
+
+func TestCrossTransactionWithSignatures_Encode(t *testing.T) {
+	signer := NewEIP155CtxSigner(big.NewInt(2))
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+
+	cws := NewCrossTransactionWithSignatures(newSignedCtx(t, signer, key1), 10)
+	cws.AddSignature(newSignedCtx(t, signer, key2))
+
+	enc, err := rlp.EncodeToBytes(cws)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	var dec CrossTransactionWithSignatures
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if dec.Hash() != cws.Hash() || dec.BlockNum != cws.BlockNum {
+		t.Errorf("decoded ctx mismatch")
+	}
+	if dec.SignerCount(signer.SimpleSender) != 2 {
+		t.Errorf("decoded signer count mismatch, got %d want 2", dec.SignerCount(signer.SimpleSender))
+	}
+	v, r, s := dec.SignatureValues()
+	if len(v) != 2 || len(r) != 2 || len(s) != 2 {
+		t.Errorf("signature values length mismatch")
+	}
+}
+
+func TestCrossTransactionWithSignatures_Size(t *testing.T) {
+	signer := NewEIP155CtxSigner(big.NewInt(2))
+	key1, _ := crypto.GenerateKey()
+	cws := NewCrossTransactionWithSignatures(newSignedCtx(t, signer, key1), 1)
+	size := cws.Size()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			cws.Size()
+		}
+	}()
+	key2, _ := crypto.GenerateKey()
+	if err := cws.AddSignature(newSignedCtx(t, signer, key2)); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	if cws.Size() <= size {
+		t.Errorf("size not updated, got %v want more than %v", cws.Size(), size)
+	}
+	cws.RemoveSignature(1)
+	if cws.Size() != size {
+		t.Errorf("size mismatch, got %v want %v", cws.Size(), size)
+	}
+}
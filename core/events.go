@@ -16,6 +16,12 @@
 
 package core
 
+import (
+	"time"
+
+	"github.com/simplechain-org/go-simplechain/common"
+)
+
 //import (
 //	"math/big"
 //
@@ -56,27 +62,6 @@ package core
 //	ChainInfo []*RemoteChainInfo
 //}
 //
-//type ModType uint8
-//
-//const (
-//	Normal = ModType(iota)
-//	Remote
-//	Reorg
-//)
-//
-//func (t ModType) String() string {
-//	switch t {
-//	case Normal:
-//		return "normal"
-//	case Remote:
-//		return "remote"
-//	case Reorg:
-//		return "reorg"
-//	default:
-//		return "unknown"
-//	}
-//}
-//
 //type CrossTransactionModifier struct {
 //	Type          ModType
 //	ID            common.Hash
@@ -102,3 +87,118 @@ package core
 //		len(e.NewFinish.Finishes)|len(e.NewAnchor.ChainInfo)|
 //		len(e.ReorgTaker.Takers)|len(e.ReorgFinish.Finishes) == 0
 //}
+
+type ModType uint8
+
+const (
+	Normal = ModType(iota)
+	Remote
+	Reorg
+)
+
+func (t ModType) String() string {
+	switch t {
+	case Normal:
+		return "normal"
+	case Remote:
+		return "remote"
+	case Reorg:
+		return "reorg"
+	default:
+		return "unknown"
+	}
+}
+
+// AnchorSet is the anchors of a remote chain trusted to sign its ctxs, read
+// from the cross contract at block Number.
+type AnchorSet struct {
+	Purpose          uint8
+	Anchors          []common.Address
+	SignConfirmCount uint8
+	Number           uint64
+}
+
+// AnchorChange is a change of the anchor set of a remote chain.
+type AnchorChange struct {
+	Purpose          uint8
+	Number           uint64
+	Added            []common.Address
+	Removed          []common.Address
+	SignConfirmCount uint8
+}
+
+// ElectionState is the state of a submitter election.
+type ElectionState string
+
+const (
+	// ElectionStandby waits for the anchors ranked before the local anchor to
+	// submit, the local anchor takes over at the deadline.
+	ElectionStandby ElectionState = "standby"
+	// ElectionSubmitted means the local anchor sent its makerFinish transaction.
+	ElectionSubmitted ElectionState = "submitted"
+	// ElectionLanded means the ctx finished before the local anchor's turn.
+	ElectionLanded ElectionState = "landed"
+	// ElectionFailed means the makerFinish transaction could not be sent.
+	ElectionFailed ElectionState = "failed"
+)
+
+// SubmitElection is the election of the anchors submitting the makerFinish
+// transactions of a ctx, as seen by the local anchor. Candidates are the
+// on-chain anchors of the remote chain Purpose ranked for the ctx, the first
+// Required of them submit and the live others take over in rank order.
+type SubmitElection struct {
+	CtxId      common.Hash
+	Purpose    uint8
+	Submitter  common.Address
+	Candidates []common.Address
+	Rank       int
+	Required   int
+	Deadline   time.Time
+	State      ElectionState
+	TxHash     common.Hash
+}
+
+// PeerState is the liveness of a hub of the swarm.
+type PeerState string
+
+const (
+	// PeerConnecting means the hub is dialed and its certs are verified, it is
+	// retried with a backoff until it succeeds.
+	PeerConnecting PeerState = "connecting"
+	// PeerConnected means the certs of the hub are verified and it answers
+	// the heartbeats.
+	PeerConnected PeerState = "connected"
+	// PeerDisconnected means the connection of the hub closed or it missed
+	// the heartbeats, it is reconnected.
+	PeerDisconnected PeerState = "disconnected"
+)
+
+// PeerStatus is the liveness of a hub of the swarm since Since. LastSeen is
+// the time of its last heartbeat and RTT the round trip time of it.
+type PeerStatus struct {
+	State    PeerState
+	Since    time.Time
+	LastSeen time.Time
+	RTT      time.Duration
+}
+
+// PeerEvent is a change of the state of the hub Id of the swarm.
+type PeerEvent struct {
+	Id     uint64
+	PeerId string
+	State  PeerState
+	Time   time.Time
+	Reason string
+}
+
+// RemovedCtx tells the hubs that the order Ctx left the canonical chain of its
+// origin. Ctx is signed by the anchor reporting it, as the order was.
+type RemovedCtx struct {
+	Ctx *CrossTransaction
+}
+
+// RevertedRtx tells the hubs that the taker Rtx of an order left the canonical
+// chain it was taken on. Rtx is signed by the anchor reporting it.
+type RevertedRtx struct {
+	Rtx *ReceptTransaction
+}
@@ -115,24 +115,24 @@ type CrossTransactionIndexed struct {
 	Payload     []byte
 
 	Price    *big.Float     `storm:"index"`
-	//BlockNum uint64         `storm:"index"`
+	BlockNum uint64         `storm:"index"`
 	// normal field
-	//Status uint8 			`storm:"index"`
+	Status uint8 			`storm:"index"`
 
-	V *big.Int
-	R *big.Int
-	S *big.Int
+	V []*big.Int
+	R []*big.Int
+	S []*big.Int
 }
 
-func NewCrossTransactionIndexed(ctx *core.CrossTransaction) *CrossTransactionIndexed {
+func NewCrossTransactionIndexed(ctx *core.CrossTransactionWithSignatures) *CrossTransactionIndexed {
 	return &CrossTransactionIndexed{
 		CtxId:            ctx.ID(),
 		TxHash:           ctx.Data.TxHash,
 		BlockHash:        ctx.Data.BlockHash,
 		Value:            ctx.Data.Value,
 		Charge:           ctx.Data.Charge,
-		//Status:           uint8(ctx.Status),
-		//BlockNum:         ctx.BlockNum,
+		Status:           uint8(ctx.Status),
+		BlockNum:         ctx.BlockNum,
 		From:             ctx.Data.From,
 		To:               ctx.Data.To,
 		Origin:           ctx.Data.Origin,
@@ -146,23 +146,26 @@ func NewCrossTransactionIndexed(ctx *core.CrossTransaction) *CrossTransactionInd
 
 }
 
-func (c CrossTransactionIndexed) ToCrossTransaction() *core.CrossTransaction {
-	var cts core.CrossTransaction
-	cts.Data.CTxId = c.CtxId
-	cts.Data.TxHash = c.TxHash
-	cts.Data.BlockHash = c.BlockHash
-	cts.Data.Value = c.Value
-	cts.Data.Charge = c.Charge
-	cts.Data.From = c.From
-	cts.Data.To = c.To
-	cts.Data.Origin = c.Origin
-	cts.Data.Purpose = c.Purpose
-	cts.Data.Payload = c.Payload
-	cts.Data.V = c.V
-	cts.Data.R = c.R
-	cts.Data.S = c.S
-
-	return &cts
+func (c CrossTransactionIndexed) ToCrossTransaction() *core.CrossTransactionWithSignatures {
+	return &core.CrossTransactionWithSignatures{
+		Data: core.CtxDatas{
+			CTxId:     c.CtxId,
+			TxHash:    c.TxHash,
+			BlockHash: c.BlockHash,
+			Value:     c.Value,
+			Charge:    c.Charge,
+			From:      c.From,
+			To:        c.To,
+			Origin:    c.Origin,
+			Purpose:   c.Purpose,
+			Payload:   c.Payload,
+			V:         c.V,
+			R:         c.R,
+			S:         c.S,
+		},
+		Status:   core.CtxStatus(c.Status),
+		BlockNum: c.BlockNum,
+	}
 }
 
 type IndexDbCache lru.ARCCache
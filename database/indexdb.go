@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"github.com/simplechain-org/crosshub/core"
 	"math/big"
+	"sort"
 
 	"github.com/simplechain-org/go-simplechain/common"
 	"github.com/simplechain-org/go-simplechain/log"
 
 	"github.com/asdine/storm/v3"
 	"github.com/asdine/storm/v3/q"
+	bolt "go.etcd.io/bbolt"
 )
 
 type IndexDB struct {
@@ -35,6 +37,9 @@ type IndexDB struct {
 	db      storm.Node
 	cache   *IndexDbCache
 	logger  log.Logger
+	inTx    bool // bound to a transaction of the caller
+	watch   func(Change)
+	held    []Change // changes waiting for the commit of the caller
 }
 
 type FieldName = string
@@ -44,11 +49,12 @@ const (
 	CtxIdIndex       FieldName = "CtxId"
 	TxHashIndex      FieldName = "TxHash"
 	PriceIndex       FieldName = "Price"
+	ValueField       FieldName = "Value"
 	StatusField      FieldName = "Status"
 	FromField        FieldName = "From"
 	ToField          FieldName = "To"
 	DestinationValue FieldName = "Charge"
-	//BlockNumField    FieldName = "BlockNum"
+	BlockNumField    FieldName = "BlockNum"
 )
 
 func NewIndexDB(chainID *big.Int, rootDB *storm.DB, cacheSize uint64) *IndexDB {
@@ -64,6 +70,41 @@ func NewIndexDB(chainID *big.Int, rootDB *storm.DB, cacheSize uint64) *IndexDB {
 	}
 }
 
+// WithTransaction returns the store writing through tx, the writes are committed
+// or rolled back by the owner of tx together with its other writes.
+func (d *IndexDB) WithTransaction(tx *bolt.Tx) *IndexDB {
+	return &IndexDB{
+		chainID: d.chainID,
+		root:    d.root,
+		db:      d.db.WithTransaction(tx),
+		cache:   d.cache,
+		logger:  d.logger,
+		inTx:    true,
+		watch:   d.watch,
+	}
+}
+
+// joinedTx is the transaction of a store bound by WithTransaction, commit and
+// rollback are left to the owner of the transaction.
+type joinedTx struct {
+	storm.Node
+}
+
+func (joinedTx) Commit() error   { return nil }
+func (joinedTx) Rollback() error { return nil }
+
+func (d *IndexDB) begin() (storm.Node, error) {
+	if d.inTx {
+		return joinedTx{d.db}, nil
+	}
+	return d.db.Begin(true)
+}
+
+// cacheable reports whether read ctxs can be cached, uncommitted ones can't.
+func (d *IndexDB) cacheable() bool {
+	return d.cache != nil && !d.inTx
+}
+
 func (d *IndexDB) ChainID() *big.Int {
 	return d.chainID
 }
@@ -97,37 +138,45 @@ func (d *IndexDB) Close() error {
 	return d.db.Commit()
 }
 
-func (d *IndexDB) Write(ctx *core.CrossTransaction) error {
-	if err := d.Writes([]*core.CrossTransaction{ctx}, true); err != nil {
+func (d *IndexDB) Write(ctx *core.CrossTransactionWithSignatures) error {
+	if err := d.Writes([]*core.CrossTransactionWithSignatures{ctx}, true); err != nil {
 		return err
 	}
-	if d.cache != nil {
+	if d.cacheable() {
 		d.cache.Put(CtxIdIndex, ctx.ID(), NewCrossTransactionIndexed(ctx))
 	}
 	return nil
 }
 
-func (d *IndexDB) Writes(ctxList []*core.CrossTransaction, replaceable bool) (err error) {
+// Writes stores the ctxs, an existing ctx is replaced only if replaceable is set
+// and its status change is a legal store sync transition. Ctxs with illegal
+// transitions are skipped, the others are committed and the first transition
+// error is returned.
+func (d *IndexDB) Writes(ctxList []*core.CrossTransactionWithSignatures, replaceable bool) (err error) {
 	d.logger.Debug("write cross transaction", "count", len(ctxList), "replaceable", replaceable)
-	tx, err := d.db.Begin(true)
+	tx, err := d.begin()
 	if err != nil {
 		return ErrCtxDbFailure{"begin transaction failed", err}
 	}
 	defer tx.Rollback()
 
+	var (
+		transitionErr error
+		changes       []Change
+	)
 	canReplace := func(old, new *CrossTransactionIndexed) bool {
 		if !replaceable {
 			return false
 		}
-		//if new.Status == uint8(cc.CtxStatusPending) {
-		//	return false
-		//}
-		//if new.BlockNum < old.BlockNum {
-		//	return false
-		//}
-		//if new.Status <= old.Status { //TODO:无法解决同步其他节点时，其他节点回滚的状态
-		//	return false
-		//}
+		if new.BlockNum < old.BlockNum {
+			return false
+		}
+		if err := core.ValidateTransition(core.CtxStatus(old.Status), core.CtxStatus(new.Status), core.Normal); err != nil {
+			if transitionErr == nil {
+				transitionErr = err
+			}
+			return false
+		}
 		return true
 	}
 
@@ -145,6 +194,7 @@ func (d *IndexDB) Writes(ctxList []*core.CrossTransaction, replaceable bool) (er
 			if err = tx.Save(new); err != nil {
 				return err
 			}
+			changes = append(changes, Change{Ctx: ctx, Created: true})
 
 		} else if canReplace(&old, new) {
 			//d.logger.Trace("replace cross transaction", "id", ctx.ID().String(),
@@ -155,25 +205,32 @@ func (d *IndexDB) Writes(ctxList []*core.CrossTransaction, replaceable bool) (er
 			if err = tx.Update(new); err != nil {
 				return err
 			}
+			if old.Status != new.Status {
+				changes = append(changes, Change{Ctx: ctx, From: core.CtxStatus(old.Status)})
+			}
 
 		} else {
-			//d.logger.Trace("can't add or replace cross transaction", "id", ctx.ID().String(),
-			//	"old_status", cc.CtxStatus(old.Status).String(), "new_status", ctx.Status.String(),
-			//	"old_height", old.BlockNum, "new_height", ctx.BlockNum, "replaceable", replaceable)
+			d.logger.Trace("can't add or replace cross transaction", "id", ctx.ID().String(),
+				"old_status", core.CtxStatus(old.Status).String(), "new_status", ctx.Status.String(),
+				"old_height", old.BlockNum, "new_height", ctx.BlockNum, "replaceable", replaceable)
 
 			continue
 		}
 
 		if d.cache != nil {
 			d.cache.Remove(CtxIdIndex, ctx.ID())
-			d.cache.Remove(CtxIdIndex, ctx.Data.TxHash)
+			d.cache.Remove(TxHashIndex, ctx.Data.TxHash)
 		}
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	d.notify(changes)
+	return transitionErr
 }
 
-func (d *IndexDB) Read(ctxId common.Hash) (*core.CrossTransaction, error) {
+func (d *IndexDB) Read(ctxId common.Hash) (*core.CrossTransactionWithSignatures, error) {
 	ctx, err := d.get(ctxId)
 	if err != nil {
 		return nil, err
@@ -181,7 +238,7 @@ func (d *IndexDB) Read(ctxId common.Hash) (*core.CrossTransaction, error) {
 	return ctx.ToCrossTransaction(), nil
 }
 
-func (d *IndexDB) One(field FieldName, key interface{}) *core.CrossTransaction {
+func (d *IndexDB) One(field FieldName, key interface{}) *core.CrossTransactionWithSignatures {
 	if d.cache != nil {
 		ctx := d.cache.Get(field, key)
 		if ctx != nil {
@@ -192,12 +249,25 @@ func (d *IndexDB) One(field FieldName, key interface{}) *core.CrossTransaction {
 	if err := d.db.One(field, key, &ctx); err != nil {
 		return nil
 	}
-	if d.cache != nil {
+	if d.cacheable() {
 		d.cache.Put(field, key, &ctx)
 	}
 	return ctx.ToCrossTransaction()
 }
 
+// Find returns the ctxs whose indexed field equals key in insertion order.
+func (d *IndexDB) Find(field FieldName, key interface{}) []*core.CrossTransactionWithSignatures {
+	var ctxs []*CrossTransactionIndexed
+	if err := d.db.Find(field, key, &ctxs); err != nil {
+		return nil
+	}
+	results := make([]*core.CrossTransactionWithSignatures, len(ctxs))
+	for i, ctx := range ctxs {
+		results[i] = ctx.ToCrossTransaction()
+	}
+	return results
+}
+
 func (d *IndexDB) get(ctxId common.Hash) (*CrossTransactionIndexed, error) {
 	if d.cache != nil {
 		ctx := d.cache.Get(CtxIdIndex, ctxId)
@@ -211,7 +281,7 @@ func (d *IndexDB) get(ctxId common.Hash) (*CrossTransactionIndexed, error) {
 		return nil, ErrCtxDbFailure{fmt.Sprintf("get ctx:%s failed", ctxId.String()), err}
 	}
 
-	if d.cache != nil {
+	if d.cacheable() {
 		d.cache.Put(CtxIdIndex, ctxId, &ctx)
 	}
 
@@ -226,17 +296,19 @@ func (d *IndexDB) Updates(idList []common.Hash, updaters []func(ctx *CrossTransa
 	if len(idList) != len(updaters) {
 		return ErrCtxDbFailure{err: errors.New("invalid updates params")}
 	}
-	tx, err := d.db.Begin(true)
+	tx, err := d.begin()
 	if err != nil {
 		return ErrCtxDbFailure{"begin transaction failed", err}
 	}
 	defer tx.Rollback()
 
+	var changes []Change
 	for i, id := range idList {
 		var ctx CrossTransactionIndexed
 		if err = tx.One(CtxIdIndex, id, &ctx); err != nil {
 			return ErrCtxDbFailure{"transaction want to be updated is not exist", err}
 		}
+		from := ctx.Status
 		updaters[i](&ctx)
 		if err = tx.Update(&ctx); err != nil {
 			return ErrCtxDbFailure{"transaction update failed", err}
@@ -245,16 +317,65 @@ func (d *IndexDB) Updates(idList []common.Hash, updaters []func(ctx *CrossTransa
 			d.cache.Remove(CtxIdIndex, id)
 			d.cache.Remove(TxHashIndex, ctx.TxHash)
 		}
+		if ctx.Status != from {
+			changes = append(changes, Change{Ctx: ctx.ToCrossTransaction(), From: core.CtxStatus(from)})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
 	}
-	return tx.Commit()
+	d.notify(changes)
+	return nil
+}
+
+// SetStatus moves the ctx to status at block number. The change is checked with
+// core.ValidateTransition, an illegal one returns core.ErrStatusTransition.
+func (d *IndexDB) SetStatus(id common.Hash, status core.CtxStatus, number uint64, mod core.ModType) error {
+	tx, err := d.begin()
+	if err != nil {
+		return ErrCtxDbFailure{"begin transaction failed", err}
+	}
+	defer tx.Rollback()
+
+	var ctx CrossTransactionIndexed
+	if err = tx.One(CtxIdIndex, id, &ctx); err != nil {
+		return ErrCtxDbFailure{"transaction want to be updated is not exist", err}
+	}
+	if err = core.ValidateTransition(core.CtxStatus(ctx.Status), status, mod); err != nil {
+		return err
+	}
+	d.logger.Debug("set cross transaction status", "id", id.String(),
+		"old_status", core.CtxStatus(ctx.Status).String(), "new_status", status.String(), "mod", mod.String())
+
+	from := core.CtxStatus(ctx.Status)
+	ctx.Status = uint8(status)
+	if number > ctx.BlockNum || mod == core.Reorg {
+		ctx.BlockNum = number
+	}
+	// Save instead of Update, storm skips zero fields on update
+	if err = tx.Save(&ctx); err != nil {
+		return ErrCtxDbFailure{"transaction update failed", err}
+	}
+	if d.cache != nil {
+		d.cache.Remove(CtxIdIndex, id)
+		d.cache.Remove(TxHashIndex, ctx.TxHash)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if from != status {
+		d.notify([]Change{{Ctx: ctx.ToCrossTransaction(), From: from}})
+	}
+	return nil
 }
 
 func (d *IndexDB) Deletes(idList []common.Hash) (err error) {
-	tx, err := d.db.Begin(true)
+	tx, err := d.begin()
 	if err != nil {
 		return ErrCtxDbFailure{"begin transaction failed", err}
 	}
 	defer tx.Rollback()
+	var changes []Change
 	for _, id := range idList {
 		var ctx CrossTransactionIndexed
 		if err = tx.One(CtxIdIndex, id, &ctx); err != nil {
@@ -267,9 +388,15 @@ func (d *IndexDB) Deletes(idList []common.Hash) (err error) {
 		if err = tx.DeleteStruct(&ctx); err != nil {
 			return ErrCtxDbFailure{"transaction delete failed", err}
 		}
+		cws := ctx.ToCrossTransaction()
+		changes = append(changes, Change{Ctx: cws, From: cws.Status, Removed: true})
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	d.notify(changes)
+	return nil
 }
 
 func (d *IndexDB) Has(id common.Hash) bool {
@@ -277,30 +404,120 @@ func (d *IndexDB) Has(id common.Hash) bool {
 	return err == nil
 }
 
-func (d *IndexDB) Query(pageSize int, startPage int, orderBy []FieldName, reverse bool, filter ...q.Matcher) []*core.CrossTransaction {
+func (d *IndexDB) Query(pageSize int, startPage int, orderBy []FieldName, reverse bool, filter ...q.Matcher) []*core.CrossTransactionWithSignatures {
 	if pageSize > 0 && startPage <= 0 {
 		return nil
 	}
 	var ctxs []*CrossTransactionIndexed
 	query := d.db.Select(filter...)
-	if len(orderBy) > 0 {
-		query.OrderBy(orderBy...)
-	}
-	if reverse {
-		query.Reverse()
-	}
-	if pageSize > 0 {
-		query.Limit(pageSize).Skip(pageSize * (startPage - 1))
+	if numericOrder(orderBy) {
+		// storm orders big numbers by their encoding, sort them in memory
+		query.Find(&ctxs)
+		sort.SliceStable(ctxs, func(i, j int) bool {
+			c := compareIndexed(ctxs[i], ctxs[j], orderBy)
+			if reverse {
+				return c > 0
+			}
+			return c < 0
+		})
+		if pageSize > 0 {
+			begin := pageSize * (startPage - 1)
+			if begin > len(ctxs) {
+				begin = len(ctxs)
+			}
+			end := begin + pageSize
+			if end > len(ctxs) {
+				end = len(ctxs)
+			}
+			ctxs = ctxs[begin:end]
+		}
+	} else {
+		if len(orderBy) > 0 {
+			query.OrderBy(orderBy...)
+		}
+		if reverse {
+			query.Reverse()
+		}
+		if pageSize > 0 {
+			query.Limit(pageSize).Skip(pageSize * (startPage - 1))
+		}
+		query.Find(&ctxs)
 	}
-	query.Find(&ctxs)
 
-	results := make([]*core.CrossTransaction, len(ctxs))
+	results := make([]*core.CrossTransactionWithSignatures, len(ctxs))
 	for i, ctx := range ctxs {
 		results[i] = ctx.ToCrossTransaction()
 	}
 	return results
 }
 
+// numericOrder reports whether orderBy has a big number field.
+func numericOrder(orderBy []FieldName) bool {
+	for _, field := range orderBy {
+		switch field {
+		case PriceIndex, ValueField, DestinationValue:
+			return true
+		}
+	}
+	return false
+}
+
+// compareIndexed compares a and b by the fields of orderBy in turn.
+func compareIndexed(a, b *CrossTransactionIndexed, orderBy []FieldName) int {
+	compareUint := func(x, y uint64) int {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	for _, field := range orderBy {
+		var c int
+		switch field {
+		case PriceIndex:
+			if a.Price != nil && b.Price != nil {
+				c = a.Price.Cmp(b.Price)
+			}
+		case ValueField:
+			if a.Value != nil && b.Value != nil {
+				c = a.Value.Cmp(b.Value)
+			}
+		case DestinationValue:
+			if a.Charge != nil && b.Charge != nil {
+				c = a.Charge.Cmp(b.Charge)
+			}
+		case PK:
+			c = compareUint(a.PK, b.PK)
+		case BlockNumField:
+			c = compareUint(a.BlockNum, b.BlockNum)
+		case StatusField:
+			c = compareUint(uint64(a.Status), uint64(b.Status))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// SortCtxs sorts ctxs by the fields of orderBy in turn, ctxs merged from
+// several stores have no common insertion order and keep theirs under PK.
+func SortCtxs(ctxs []*core.CrossTransactionWithSignatures, orderBy []FieldName, reverse bool) {
+	indexed := make(map[*core.CrossTransactionWithSignatures]*CrossTransactionIndexed, len(ctxs))
+	for _, ctx := range ctxs {
+		indexed[ctx] = NewCrossTransactionIndexed(ctx)
+	}
+	sort.SliceStable(ctxs, func(i, j int) bool {
+		c := compareIndexed(indexed[ctxs[i]], indexed[ctxs[j]], orderBy)
+		if reverse {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
 //func (d *IndexDB) RangeByNumber(begin, end uint64, limit int) []*cc.CrossTransactionWithSignatures {
 //	var (
 //		ctxs    []*CrossTransactionIndexed
@@ -343,3 +560,30 @@ func (d *IndexDB) Get(key string) uint64 {
 
 	return value
 }
+
+// BlockRecord is the hash of a scanned block, used to detect reorgs.
+type BlockRecord struct {
+	Number uint64 `storm:"id"`
+	Hash   common.Hash
+}
+
+// SetBlockHash records the hash of the scanned block number.
+func (d *IndexDB) SetBlockHash(number uint64, hash common.Hash) error {
+	return d.db.Save(&BlockRecord{Number: number, Hash: hash})
+}
+
+// BlockRecords returns the recorded blocks from number begin, the highest first.
+func (d *IndexDB) BlockRecords(begin uint64) []*BlockRecord {
+	var records []*BlockRecord
+	d.db.Select(q.Gte("Number", begin)).OrderBy("Number").Reverse().Find(&records)
+	return records
+}
+
+// DeleteBlockRecords removes the recorded blocks in [begin, end].
+func (d *IndexDB) DeleteBlockRecords(begin, end uint64) error {
+	err := d.db.Select(q.Gte("Number", begin), q.Lte("Number", end)).Delete(&BlockRecord{})
+	if err == storm.ErrNotFound {
+		return nil
+	}
+	return err
+}
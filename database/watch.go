@@ -0,0 +1,47 @@
+package database
+
+import (
+	"github.com/simplechain-org/crosshub/core"
+)
+
+// Change is a committed change of a stored ctx.
+type Change struct {
+	Ctx     *core.CrossTransactionWithSignatures
+	From    core.CtxStatus // status before the change, unset for a created ctx
+	Created bool
+	Removed bool
+}
+
+// Watch calls fn with every committed change of the store, it must not block.
+// Stores bound to a transaction hold their changes until Flush.
+func (d *IndexDB) Watch(fn func(Change)) {
+	d.watch = fn
+}
+
+// Flush reports the changes held by a store bound to a transaction, it is
+// called by the owner of the transaction after the commit.
+func (d *IndexDB) Flush() {
+	held := d.held
+	d.held = nil
+	if d.watch == nil {
+		return
+	}
+	for _, change := range held {
+		d.watch(change)
+	}
+}
+
+// notify reports the changes of a committed write, or holds them until Flush
+// if the commit is left to the owner of the transaction.
+func (d *IndexDB) notify(changes []Change) {
+	if d.watch == nil || len(changes) == 0 {
+		return
+	}
+	if d.inTx {
+		d.held = append(d.held, changes...)
+		return
+	}
+	for _, change := range changes {
+		d.watch(change)
+	}
+}
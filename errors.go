@@ -14,13 +14,18 @@
 //// You should have received a copy of the GNU Lesser General Public License
 //// along with the go-simplechain library. If not, see <http://www.gnu.org/licenses/>.
 //
-package main
-//
-//import (
-//	"errors"
-//	"fmt"
-//)
-//
+package crosshub
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrVerifyCtx = errors.New("verify ctx failed")
+	ErrReorgCtx  = fmt.Errorf("[%w]: ctx is on sidechain", ErrVerifyCtx)
+)
+
 //var (
 //	ErrVerifyCtx       = errors.New("verify ctx failed")
 //	ErrInvalidSignCtx  = fmt.Errorf("[%w]: verify signature failed", ErrVerifyCtx)
@@ -29,7 +34,6 @@ package main
 //	ErrAlreadyExistCtx = fmt.Errorf("[%w]: ctx is already exist", ErrVerifyCtx)
 //	ErrLocalSignCtx    = fmt.Errorf("[%w]: remote ctx signed by local anchor", ErrVerifyCtx)
 //	ErrFinishedCtx     = fmt.Errorf("[%w]: ctx is already finished", ErrVerifyCtx)
-//	ErrReorgCtx        = fmt.Errorf("[%w]: ctx is on sidechain", ErrVerifyCtx)
 //	ErrInternal        = fmt.Errorf("[%w]: internal error", ErrVerifyCtx)
 //	ErrRepetitionCtx   = fmt.Errorf("[%w]: repetition cross transaction", ErrVerifyCtx) // 合约重复接单
 //
@@ -82,6 +82,10 @@ func (s *Store) Save(txList []*CrossTx) error {
 			utils.Logger.Warn("[courier.Store] parse old crossTx failed", "crossID", oldTx.CrossID)
 		} else if newTx.IsFinished() {
 			utils.Logger.Debug("[courier.Store] receive Finished crossTx ", "crossID", newTx.CrossID, "txId", newTx.TxID)
+			if err := checkTransition(oldTx.GetStatus(), contractlib.Completed); err != nil {
+				utils.Logger.Warn("[courier.Store] illegal status transition, skip", "crossID", newTx.CrossID, "err", err)
+				continue
+			}
 			// update old status, discard new
 			oldTx.UpdateStatus(contractlib.Completed)
 			if err = withTransaction.Update(&oldTx); err != nil {
@@ -119,20 +123,29 @@ func (s *Store) Updates(idList []string, updaters []func(c *CrossTx)) error {
 	}
 	defer withTransaction.Rollback()
 
+	var successes int
 	for i, id := range idList {
 		var c CrossTx
 		if err = withTransaction.One(CrossIdIndex, id, &c); err != nil {
 			return fmt.Errorf("db query err: %w", err)
 		}
 
+		old := c.GetStatus()
 		updaters[i](&c)
+		// the status may have moved on already, e.g. a receipt of the tx
+		// stored before this update, the other updates are still committed
+		if err = checkTransition(old, c.GetStatus()); err != nil {
+			utils.Logger.Warn("[courier.RemoteStore] skip update", "crossID", id, "err", err)
+			continue
+		}
 
 		if err = withTransaction.Update(&c); err != nil {
 			return fmt.Errorf("db update err: %w", err)
 		}
+		successes++
 	}
 
-	utils.Logger.Debug("[courier.RemoteStore] update list", "successes", len(idList))
+	utils.Logger.Debug("[courier.RemoteStore] update list", "successes", successes)
 
 	return withTransaction.Commit()
 }
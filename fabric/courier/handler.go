@@ -2,6 +2,7 @@ package courier
 
 import (
 	"github.com/simplechain-org/crosshub/fabric/courier/client"
+	"github.com/simplechain-org/crosshub/registry"
 
 	"github.com/asdine/storm/v3"
 	"github.com/simplechain-org/go-simplechain/crypto/ecdsa"
@@ -54,6 +55,11 @@ func (h *Handler) Stop() {
 	h.rootDB.Close()
 }
 
+// QueueDepths returns the depths of the send and receipt queues of the courier.
+func (h *Handler) QueueDepths() (pending, executed int) {
+	return h.txm.QueueDepths()
+}
+
 func (h *Handler) SetPrivateKey(key *ecdsa.PrivateKey) {
 	h.txm.privateKey = key
 }
@@ -61,3 +67,18 @@ func (h *Handler) SetPrivateKey(key *ecdsa.PrivateKey) {
 func (h *Handler) SetOutChainFlag(flag bool) {
 	h.txm.outchain = flag
 }
+
+// SetChains sets the chain registry, the fabric chain served by the courier
+// and the chain its cross transactions are sent to.
+func (h *Handler) SetChains(reg *registry.Registry, local, target *registry.Chain) {
+	h.txm.registry = reg
+	h.txm.origin = local.Purpose
+	h.txm.purpose = target.Purpose
+}
+
+// SetStartHeight sets the block the sync starts from when no block has been synced past it.
+func (h *Handler) SetStartHeight(number uint64) {
+	if h.blkSync.blockNum < number {
+		h.blkSync.blockNum = number
+	}
+}
@@ -15,9 +15,6 @@ import (
 )
 
 const (
-	SimpleChain uint8 = 2
-	Fabric      uint8 = 5
-
 	testChainCodePrefix    = "outchain"
 	testFabricinvoke       = "invoke"
 	testFabricAccount      = "a"
@@ -56,13 +53,38 @@ func (c *CrossTx) UnmarshalJSON(bytes []byte) (err error) {
 	return nil
 }
 
+// ctxStatusOf maps the courier contract status onto the ctx lifecycle kept by
+// the hub, so that courier updates follow the same state machine as chainview.
+var ctxStatusOf = map[contractlib.CStatus]core.CtxStatus{
+	contractlib.Init:             core.CtxStatusPending,
+	contractlib.Pending:          core.CtxStatusWaiting,
+	contractlib.Executed:         core.CtxStatusExecuted,
+	contractlib.Finished:         core.CtxStatusFinishing,
+	contractlib.Completed:        core.CtxStatusFinished,
+	contractlib.OutOnceCompleted: core.CtxStatusFinished,
+}
+
+// CtxStatus returns the hub lifecycle status of the cross tx.
+func (c *CrossTx) CtxStatus() core.CtxStatus {
+	return ctxStatusOf[c.GetStatus()]
+}
+
+// checkTransition reports whether the cross tx can move from status from to to
+// through legal store sync steps.
+func checkTransition(from, to contractlib.CStatus) error {
+	_, err := core.TransitionPath(ctxStatusOf[from], ctxStatusOf[to])
+	return err
+}
+
 type CrossTxReceipt struct {
 	CrossID  string
 	Receipt  string
 	Sequence int64
 }
 
-func toCrossHubTx(tx *CrossTx) *core.CrossTransaction {
+// toCrossHubTx converts a precommit cross tx of the fabric chain origin to a
+// ctx targeting the chain purpose.
+func toCrossHubTx(tx *CrossTx, origin, purpose uint8) *core.CrossTransaction {
 	pre, ok := tx.IContract.(*contractlib.PrecommitContract)
 	if !ok {
 		return nil
@@ -90,7 +112,7 @@ func toCrossHubTx(tx *CrossTx) *core.CrossTransaction {
 	from := pre.Address
 	to := ""
 
-	return core.NewCrossTransaction(val, charge, from, to, Fabric, SimpleChain, ctxID, txID, blkHash, payload)
+	return core.NewCrossTransaction(val, charge, from, to, origin, purpose, ctxID, txID, blkHash, payload)
 }
 
 type CrossChannel struct {
@@ -3,7 +3,6 @@ package courier
 import (
 	"errors"
 	"fmt"
-	"math/big"
 	"sync"
 
 	"github.com/simplechain-org/crosshub/core"
@@ -11,12 +10,14 @@ import (
 	"github.com/simplechain-org/crosshub/fabric/courier/contractlib"
 	"github.com/simplechain-org/crosshub/fabric/courier/utils"
 	"github.com/simplechain-org/crosshub/fabric/courier/utils/prque"
+	"github.com/simplechain-org/crosshub/registry"
 
 	"github.com/asdine/storm/v3"
 	"github.com/asdine/storm/v3/q"
 	"github.com/simplechain-org/go-simplechain/common"
 	"github.com/simplechain-org/go-simplechain/crypto"
 	"github.com/simplechain-org/go-simplechain/crypto/ecdsa"
+	"github.com/simplechain-org/go-simplechain/metrics"
 )
 
 type Prqueue struct {
@@ -36,10 +37,19 @@ type TxManager struct {
 	pending  Prqueue
 	executed Prqueue
 
+	// invokeFailures counts the failed chaincode invocations
+	invokeFailures metrics.Counter
+
 	//p2p client private key
 	privateKey *ecdsa.PrivateKey
 	//if true, handle cross transaction from outchain, default not handle
 	outchain bool
+
+	registry *registry.Registry
+	// purpose of the fabric chain served by the courier
+	origin uint8
+	// purpose of the chain taking the fabric cross transactions
+	purpose uint8
 }
 
 func NewTxManager(fabCli client.FabricClient, outCli client.OutChainClient, db DB) *TxManager {
@@ -50,11 +60,14 @@ func NewTxManager(fabCli client.FabricClient, outCli client.OutChainClient, db D
 		executed: Prqueue{prq: prque.New(nil), process: make(chan struct{}, 8)},
 		oClient:  outCli,
 		fClient:  fabCli,
+
+		invokeFailures: metrics.NilCounter{},
 	}
 }
 
 func (t *TxManager) Start() {
 	utils.Logger.Info("[courier.TxManager] starting")
+	t.registerMetrics()
 	t.wg.Add(3)
 	go t.ProcessCrossTxs()
 	go t.ProcessCrossTxReceipts()
@@ -78,6 +91,34 @@ func (t *TxManager) Stop() {
 	utils.Logger.Info("[courier.TxManager] stopped")
 }
 
+// registerMetrics registers the queue depths and the chaincode invocation
+// failures of the fabric chain served, they are stubs unless metrics are enabled.
+func (t *TxManager) registerMetrics() {
+	prefix := fmt.Sprintf("courier/%d", t.origin)
+	metrics.NewRegisteredFunctionalGauge(prefix+"/pending", nil, func() int64 {
+		pending, _ := t.QueueDepths()
+		return int64(pending)
+	})
+	metrics.NewRegisteredFunctionalGauge(prefix+"/executed", nil, func() int64 {
+		_, executed := t.QueueDepths()
+		return int64(executed)
+	})
+	t.invokeFailures = metrics.GetOrRegisterCounter(prefix+"/invoke/failures", nil)
+}
+
+// QueueDepths returns the number of cross transactions queued to be sent to
+// the target chain and of the receipts queued to be committed on fabric.
+func (t *TxManager) QueueDepths() (pending, executed int) {
+	t.pending.mu.Lock()
+	pending = t.pending.prq.Size()
+	t.pending.mu.Unlock()
+
+	t.executed.mu.Lock()
+	executed = t.executed.prq.Size()
+	t.executed.mu.Unlock()
+	return pending, executed
+}
+
 func (t *TxManager) reload() {
 	utils.Logger.Debug("[courier.TxManager] reloading")
 	toPending := t.DB.Query(0, 0, []FieldName{TimestampField}, false, q.Eq(StatusField, contractlib.Init))
@@ -163,7 +204,7 @@ func (t *TxManager) ProcessCrossTxs() {
 			updaters := make([]func(c *CrossTx), 0)
 
 			for _, tx := range pending {
-				ctx := toCrossHubTx(tx)
+				ctx := toCrossHubTx(tx, t.origin, t.purpose)
 				if ctx == nil {
 					continue
 				}
@@ -266,6 +307,7 @@ func (t *TxManager) ProcessCrossTxReceipts() {
 					_, err := t.fClient.InvokeChainCode("commit", []string{ctr.CrossID, ctr.Receipt})
 					if err != nil {
 						utils.Logger.Error("[courier.TxManager] send tx to fabric", "InvokeChainCode err", err)
+						t.invokeFailures.Inc(1)
 					}
 
 					t.executed.prq.Push(ctr, -ctr.Sequence)
@@ -281,11 +323,16 @@ func (t *TxManager) ProcessCrossTxReceipts() {
 func (t *TxManager) signTx(ctx interface{}) (interface{}, error) {
 	switch ctx.(type) {
 	case *core.CrossTransaction:
-		return core.SignCtx(ctx.(*core.CrossTransaction), core.MakeCtxSigner(big.NewInt(11)), func(hash []byte) ([]byte, error) {
+		// the anchors sign the ctx in the form verified by the purpose contract
+		purpose, err := t.registry.Chain(ctx.(*core.CrossTransaction).Data.Purpose)
+		if err != nil {
+			return nil, err
+		}
+		return core.SignSimpleCtx(ctx.(*core.CrossTransaction), purpose.CtxSigner(), func(hash []byte) ([]byte, error) {
 			return crypto.Sign(hash, t.privateKey.K)
 		})
 	case *core.ReceptTransaction:
-		return core.SignRtx(ctx.(*core.ReceptTransaction), core.MakeRtxSigner(big.NewInt(11)), func(hash []byte) ([]byte, error) {
+		return core.SignRtx(ctx.(*core.ReceptTransaction), t.registry.HubRtxSigner(), func(hash []byte) ([]byte, error) {
 			return crypto.Sign(hash, t.privateKey.K)
 		})
 	default:
@@ -367,8 +414,8 @@ func (t *TxManager) processOutChainCtxReq(req *core.CrossTransaction) {
 		req.Data.From,
 		req.Data.To,
 		testSimpleChainAddress,
-		Fabric,
-		SimpleChain,
+		t.origin,
+		req.Data.Origin,
 		req.Data.Payload,
 	)
 
@@ -383,6 +430,7 @@ func (t *TxManager) processOutChainCtxReq(req *core.CrossTransaction) {
 	_, err := t.fClient.InvokeChainCode("commit", []string{testChainCodePrefix, req.ID().String(), testFabricinvoke, req.Data.To, req.Data.From, req.Data.Charge.String()})
 	if err != nil {
 		utils.Logger.Error("[courier.TxManager] send processOutChainCtxReq to fabric", "err", err)
+		t.invokeFailures.Inc(1)
 	}
 	//TODO 并发err
 }
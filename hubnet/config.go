@@ -11,6 +11,8 @@ type Config struct {
 	localAddr  string
 	privKey    crypto.PrivKey
 	protocolID protocol.ID
+	maxMsgSize uint32
+	compress   bool
 }
 
 type Option func(*Config)
@@ -33,6 +35,22 @@ func WithProtocolID(id protocol.ID) Option {
 	}
 }
 
+// WithMaxMsgSize limits the size of the messages sent and received, the
+// DefaultMaxMsgSize is used if it is zero.
+func WithMaxMsgSize(size uint32) Option {
+	return func(config *Config) {
+		config.maxMsgSize = size
+	}
+}
+
+// WithCompression compresses the payloads of the messages sent by snappy,
+// compressed messages are read anyway.
+func WithCompression(compress bool) Option {
+	return func(config *Config) {
+		config.compress = compress
+	}
+}
+
 func checkConfig(config *Config) error {
 	if config.localAddr == "" {
 		return fmt.Errorf("empty local address")
@@ -1,10 +1,11 @@
 package hubnet
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"github.com/simplechain-org/go-simplechain/log"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/network"
@@ -12,15 +13,23 @@ import (
 
 // handle newly connected stream
 func (p2p *P2P) handleNewStream(s network.Stream) {
+	defer func() {
+		p2p.rpc.fail(s)
+		p2p.streamMng.removeStream(s.Conn().RemotePeer(), s)
+		p2p.writeLocks.Delete(s)
+	}()
 	if err := s.SetReadDeadline(time.Time{}); err != nil {
 		//p2p.logger.WithField("error", err).Error("Set stream read deadline")
 		return
 	}
-	rw := newp2pRW(s)
+	rw := newp2pRW(s, p2p.config)
 	for {
 		var msg Msg
 		var err error
 		if msg,err = rw.ReadMsg(); err != nil {
+			if errors.Is(err, ErrFrameVersion) || errors.Is(err, ErrMsgTooLarge) || errors.Is(err, ErrChecksum) {
+				log.Warn("Reject frame", "peer", s.Conn().RemotePeer().String(), "err", err)
+			}
 			if err != io.EOF {
 				if err := s.Reset(); err != nil {
 					log.Error("Reset stream",err)
@@ -29,48 +38,36 @@ func (p2p *P2P) handleNewStream(s network.Stream) {
 			return
 		}
 
-		if p2p.handleMessage != nil {
-			p2p.handleMessage(s, &msg)
-		}
-	}
-}
-
-// waitMsg wait the incoming messages within time duration.
-func waitMsg(stream network.Stream, timeout time.Duration) *Msg {
-	rw := newp2pRW(stream)
-	ch := make(chan *Msg)
-	go func() {
-		if msg,err := rw.ReadMsg(); err == nil {
-			ch <- &msg
-		} else {
-			ch <- nil
+		switch msg.Kind {
+		case KindResponse, KindError:
+			p2p.rpc.resolve(&msg)
+		case KindRequest:
+			// requests are handled at once, they may wait for others
+			go p2p.handleRequest(s, &msg)
+		default:
+			if p2p.handleMessage != nil {
+				p2p.handleMessage(s, &msg)
+			}
 		}
-	}()
-
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-
-	select {
-	case r := <-ch:
-		cancel()
-		return r
-	case <-ctx.Done():
-		cancel()
-		return nil
 	}
 }
 
 func (p2p *P2P) send(s network.Stream, msg *Msg) error {
+	lock, _ := p2p.writeLocks.LoadOrStore(s, new(sync.Mutex))
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
 	deadline := time.Now().Add(sendTimeout)
 
 	if err := s.SetWriteDeadline(deadline); err != nil {
 		return fmt.Errorf("set deadline: %w", err)
 	}
 
-	rw := newp2pRW(s)
+	rw := newp2pRW(s, p2p.config)
 
 	if err := rw.WriteMsg(msg); err != nil {
 		return fmt.Errorf("write msg: %w", err)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
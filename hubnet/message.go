@@ -28,14 +28,19 @@ var errorToString = map[int]string{
 // structure, encode the payload into a byte array and create a
 // separate Msg with a bytes.Reader as Payload for each send.
 type Msg struct {
-	Code       uint8
+	Code       uint16
 	Size       uint32 // Size of the raw payload
 	//Payload    io.Reader
 	Bytes      []byte
 	ReceivedAt time.Time
+
+	// Kind and ID correlate the requests and the responses of the rpc layer,
+	// ID is zero for the other messages
+	Kind MsgKind
+	ID   uint64
 }
 
-func NewMsg(msgcode uint8, data interface{}) (*Msg,error) {
+func NewMsg(msgcode uint16, data interface{}) (*Msg,error) {
 	//size, r, err := rlp.EncodeToReader(data)
 	//if err != nil {
 	//	return nil,err
@@ -1,6 +1,8 @@
 package hubnet
 
 import (
+	"context"
+
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
@@ -8,6 +10,9 @@ import (
 
 type ConnectCallback func(*peer.AddrInfo) error
 
+// DisconnectCallback is called with the peers whose last connection closed.
+type DisconnectCallback func(peer.ID)
+
 
 type MessageHandler func(network.Stream, *Msg)
 
@@ -27,6 +32,9 @@ type Network interface {
 	// SetConnectionCallback sets the callback after connecting
 	SetConnectCallback(ConnectCallback)
 
+	// SetDisconnectCallback sets the callback after the peer disconnects
+	SetDisconnectCallback(DisconnectCallback)
+
 	// SetMessageHandler sets message handler
 	SetMessageHandler(MessageHandler)
 
@@ -39,6 +47,12 @@ type Network interface {
 	// Send sends message waiting response
 	Send(*peer.AddrInfo, *Msg) (*Msg, error)
 
+	// Request sends a request waiting its response until the context is done
+	Request(context.Context, *peer.AddrInfo, *Msg) (*Msg, error)
+
+	// RegisterHandler sets the handler of the requests of a message code
+	RegisterHandler(uint16, RequestHandler)
+
 	// Broadcast message to all node
 	Broadcast([]*peer.AddrInfo, *Msg) error
 
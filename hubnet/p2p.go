@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/simplechain-org/go-simplechain/log"
+	"sync"
 	"time"
 
 	"github.com/libp2p/go-libp2p"
@@ -26,7 +27,12 @@ type P2P struct {
 	host            host.Host // manage all connections
 	streamMng       *streamMgr
 	connectCallback ConnectCallback
+	disconnectCallback DisconnectCallback
 	handleMessage   MessageHandler
+	rpc             *rpc
+	// writeLocks keeps a lock by stream, frames written on a stream must not
+	// interleave
+	writeLocks sync.Map
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -49,12 +55,17 @@ func New(opts ...Option) (*P2P, error) {
 	}
 
 	p2p := &P2P{
-		config:    config,
-		host:      h,
-		streamMng: newStreamMng(ctx, h, config.protocolID),
-		ctx:       ctx,
-		cancel:    cancel,
+		config: config,
+		host:   h,
+		rpc:    newRPC(),
+		ctx:    ctx,
+		cancel: cancel,
 	}
+	// the streams opened are read like the streams accepted, the responses
+	// of the requests arrive on them
+	p2p.streamMng = newStreamMng(ctx, h, config.protocolID, func(s network.Stream) {
+		go p2p.handleNewStream(s)
+	})
 
 	return p2p, nil
 }
@@ -62,6 +73,19 @@ func New(opts ...Option) (*P2P, error) {
 // Start start the network service.
 func (p2p *P2P) Start() error {
 	p2p.host.SetStreamHandler(p2p.config.protocolID, p2p.handleNewStream)
+	p2p.host.Network().Notify(&network.NotifyBundle{
+		DisconnectedF: func(n network.Network, conn network.Conn) {
+			id := conn.RemotePeer()
+			if len(n.ConnsToPeer(id)) > 0 {
+				return
+			}
+			// the streams of the peer are dead
+			p2p.streamMng.remove(id)
+			if p2p.disconnectCallback != nil {
+				p2p.disconnectCallback(id)
+			}
+		},
+	})
 
 	return nil
 }
@@ -90,6 +114,10 @@ func (p2p *P2P) SetConnectCallback(callback ConnectCallback) {
 	p2p.connectCallback = callback
 }
 
+func (p2p *P2P) SetDisconnectCallback(callback DisconnectCallback) {
+	p2p.disconnectCallback = callback
+}
+
 func (p2p *P2P) SetMessageHandler(handler MessageHandler) {
 	p2p.handleMessage = handler
 }
@@ -113,23 +141,13 @@ func (p2p *P2P) SendWithStream(s network.Stream, msg *Msg) error {
 	return p2p.send(s, msg)
 }
 
+// Send sends the request msg to the peer and waits for its response, see
+// Request.
 func (p2p *P2P) Send(addr *peer.AddrInfo, msg *Msg) (*Msg, error) {
-	s, err := p2p.streamMng.get(addr.ID)
-	if err != nil {
-		return nil, fmt.Errorf("get stream: %w", err)
-	}
-
-	if err := p2p.send(s, msg); err != nil {
-		p2p.streamMng.remove(addr.ID)
-		return nil, err
-	}
-
-	recvMsg := waitMsg(s, waitTimeout)
-	if recvMsg == nil {
-		return nil, fmt.Errorf("sync send msg to node[%s] timeout", addr.ID)
-	}
+	ctx, cancel := context.WithTimeout(p2p.ctx, waitTimeout)
+	defer cancel()
 
-	return recvMsg, nil
+	return p2p.Request(ctx, addr, msg)
 }
 
 func (p2p *P2P) Broadcast(ids []*peer.AddrInfo, msg *Msg) error {
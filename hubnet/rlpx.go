@@ -1,106 +1,147 @@
 package hubnet
 
 import (
+	"encoding/binary"
 	"errors"
-	"github.com/simplechain-org/go-simplechain/log"
-	"github.com/simplechain-org/go-simplechain/rlp"
+	"fmt"
+	"hash/crc32"
 	"io"
+
+	"github.com/golang/snappy"
 )
 
+// A frame is a header and the payload of a message. The header is
+//
+//	magic   [2]byte  "CH"
+//	version uint8    FrameVersion
+//	flags   uint8    flagSnappy if the payload is compressed
+//	kind    uint8    the MsgKind
+//	        uint8    reserved
+//	code    uint16
+//	id      uint64   the request id
+//	length  uint32   the size of the payload in the frame
+//	crc     uint32   the crc32c of the header before it and the payload
+//
+// in big endian.
 const (
-	maxUint24 = ^uint32(0) >> 8
+	// FrameVersion is the version of the frame format, the major version of
+	// the protocol ids must change with it
+	FrameVersion = 2
+	// DefaultMaxMsgSize is the size limit of the messages if none is set
+	DefaultMaxMsgSize = 4 * 1024 * 1024
+
+	headerSize = 24
+	flagSnappy = 1 << 0
+	// compressThreshold is the payload size from which payloads are compressed
+	compressThreshold = 256
+)
+
+var (
+	frameMagic = [2]byte{'C', 'H'}
+	crcTable   = crc32.MakeTable(crc32.Castagnoli)
+
+	ErrFrameVersion = errors.New("unsupported frame version")
+	ErrMsgTooLarge  = errors.New("message too large")
+	ErrChecksum     = errors.New("frame checksum mismatch")
 )
 
 type p2pRW struct {
 	conn io.ReadWriter
+	// maxSize limits the payloads before and after the compression
+	maxSize  uint32
+	compress bool
 }
 
-func newp2pRW(conn io.ReadWriter) *p2pRW {
+func newp2pRW(conn io.ReadWriter, config *Config) *p2pRW {
+	maxSize := config.maxMsgSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxMsgSize
+	}
 	return &p2pRW{
-		conn:       conn,
+		conn:     conn,
+		maxSize:  maxSize,
+		compress: config.compress,
 	}
 }
 
 func (rw *p2pRW) WriteMsg(msg *Msg) error {
-	ptype, err := rlp.EncodeToBytes(msg.Code)
-	if err != nil {
-		return err
-	}
-	headbuf := make([]byte, 32)
-	fsize := uint32(len(ptype)) + msg.Size
-	if fsize > maxUint24 {
-		return errors.New("message size overflows uint24")
-	}
-	putInt24(fsize, headbuf) // TODO: check overflow
-	if _, err := rw.conn.Write(headbuf); err != nil {
-		log.Info("headbuf","err",err)
-		return err
+	if uint64(len(msg.Bytes)) > uint64(rw.maxSize) {
+		return fmt.Errorf("%w: %d bytes", ErrMsgTooLarge, len(msg.Bytes))
 	}
 
-	if _, err := rw.conn.Write(ptype); err != nil {
-		log.Info("ptype","err",err)
-		return err
+	var flags uint8
+	payload := msg.Bytes
+	if rw.compress && len(payload) >= compressThreshold {
+		// the payloads snappy can't shrink are sent as they are
+		if compressed := snappy.Encode(nil, payload); len(compressed) < len(payload) {
+			flags |= flagSnappy
+			payload = compressed
+		}
 	}
 
-	//payload, err := ioutil.ReadAll(msg.Payload)
-	//if err != nil {
-	//	log.Info("ReadAll","er",err)
-	//	return err
-	//}
-	if _, err := rw.conn.Write(msg.Bytes); err != nil {
-		log.Info("payload","err",err)
+	// the frame is written at once, writes of frames must not interleave
+	frame := make([]byte, headerSize+len(payload))
+	copy(frame, frameMagic[:])
+	frame[2] = FrameVersion
+	frame[3] = flags
+	frame[4] = byte(msg.Kind)
+	binary.BigEndian.PutUint16(frame[6:8], msg.Code)
+	binary.BigEndian.PutUint64(frame[8:16], msg.ID)
+	binary.BigEndian.PutUint32(frame[16:20], uint32(len(payload)))
+	copy(frame[headerSize:], payload)
+	crc := crc32.Update(crc32.Checksum(frame[:20], crcTable), crcTable, payload)
+	binary.BigEndian.PutUint32(frame[20:24], crc)
+
+	if _, err := rw.conn.Write(frame); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (rw *p2pRW) ReadMsg() (msg Msg, err error) {
-	// read the header
-	headbuf := make([]byte, 32)
-	if _, err := io.ReadFull(rw.conn, headbuf); err != nil {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(rw.conn, header); err != nil {
 		return msg, err
 	}
-	fsize := readInt24(headbuf)
-	codebuf := make([]byte, 1)
-	if _, err := io.ReadFull(rw.conn, codebuf); err != nil {
-		return msg, err
+	// the frames of the old format start with their size
+	if header[0] != frameMagic[0] || header[1] != frameMagic[1] || header[2] != FrameVersion {
+		return msg, fmt.Errorf("%w: header %x", ErrFrameVersion, header[:3])
+	}
+	flags := header[3]
+	if flags&^flagSnappy != 0 {
+		return msg, fmt.Errorf("%w: flags %x", ErrFrameVersion, flags)
 	}
-	err = rlp.DecodeBytes(codebuf,&msg.Code)
-	if err != nil {
-		log.Info("DecodeBytes","err",err)
-		return msg,err
+	size := binary.BigEndian.Uint32(header[16:20])
+	if size > rw.maxSize {
+		return msg, fmt.Errorf("%w: %d bytes", ErrMsgTooLarge, size)
 	}
-	msg.Size = fsize - 1
-	framebuf := make([]byte, msg.Size)
-	if _, err := io.ReadFull(rw.conn, framebuf); err != nil {
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(rw.conn, payload); err != nil {
 		return msg, err
 	}
-	msg.Bytes = framebuf
-
-
-	// decode message code
-	//content := bytes.NewReader(framebuf)
-	////msg.Code长度固定
-	//if err := rlp.Decode(content, &msg.Code); err != nil {
-	//	log.Info("Decode","err",err)
-	//	return msg, err
-	//}
-	//content读出去后长度变化
-	//msg.Size = uint32(content.Len())
-	//msg.Payload = content
-	//msg.Bytes,err  = ioutil.ReadAll(content)
-	//if err != nil {
-	//	log.Info("ReadAll","err",err)
-	//}
-	return msg, nil
-}
+	crc := crc32.Update(crc32.Checksum(header[:20], crcTable), crcTable, payload)
+	if crc != binary.BigEndian.Uint32(header[20:24]) {
+		return msg, ErrChecksum
+	}
 
-func readInt24(b []byte) uint32 {
-	return uint32(b[2]) | uint32(b[1])<<8 | uint32(b[0])<<16
-}
+	if flags&flagSnappy != 0 {
+		n, err := snappy.DecodedLen(payload)
+		if err != nil {
+			return msg, fmt.Errorf("decode payload: %w", err)
+		}
+		if uint64(n) > uint64(rw.maxSize) {
+			return msg, fmt.Errorf("%w: %d bytes", ErrMsgTooLarge, n)
+		}
+		if payload, err = snappy.Decode(nil, payload); err != nil {
+			return msg, fmt.Errorf("decode payload: %w", err)
+		}
+	}
 
-func putInt24(v uint32, b []byte) {
-	b[0] = byte(v >> 16)
-	b[1] = byte(v >> 8)
-	b[2] = byte(v)
-}
\ No newline at end of file
+	msg.Kind = MsgKind(header[4])
+	msg.Code = binary.BigEndian.Uint16(header[6:8])
+	msg.ID = binary.BigEndian.Uint64(header[8:16])
+	msg.Size = uint32(len(payload))
+	msg.Bytes = payload
+	return msg, nil
+}
@@ -0,0 +1,57 @@
+package hubnet
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestP2pRW(t *testing.T) {
+	var buf bytes.Buffer
+	rw := newp2pRW(&buf, &Config{maxMsgSize: 1024, compress: true})
+
+	payload := bytes.Repeat([]byte("cross"), 100)
+	msg := &Msg{Code: 0x1234, Size: uint32(len(payload)), Bytes: payload, Kind: KindRequest, ID: 7}
+	if err := rw.WriteMsg(msg); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() >= headerSize+len(payload) || buf.Bytes()[3]&flagSnappy == 0 {
+		t.Fatalf("payload not compressed: frame of %d bytes", buf.Len())
+	}
+	got, err := rw.ReadMsg()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Code != msg.Code || got.Kind != msg.Kind || got.ID != msg.ID || got.Size != msg.Size || !bytes.Equal(got.Bytes, payload) {
+		t.Fatalf("read %+v", got)
+	}
+
+	// the size limit holds for the payloads sent and the payloads inflated
+	if err := rw.WriteMsg(&Msg{Code: 1, Bytes: make([]byte, 1025)}); !errors.Is(err, ErrMsgTooLarge) {
+		t.Fatalf("write too large: %v", err)
+	}
+	if err := newp2pRW(&buf, &Config{compress: true}).WriteMsg(&Msg{Code: 1, Bytes: make([]byte, 2048)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rw.ReadMsg(); !errors.Is(err, ErrMsgTooLarge) {
+		t.Fatalf("read too large: %v", err)
+	}
+
+	buf.Reset()
+	if err := rw.WriteMsg(&Msg{Code: 1, Bytes: []byte("payload")}); err != nil {
+		t.Fatal(err)
+	}
+	buf.Bytes()[buf.Len()-1] ^= 0xff
+	if _, err := rw.ReadMsg(); !errors.Is(err, ErrChecksum) {
+		t.Fatalf("corrupted frame: %v", err)
+	}
+
+	// a frame of the old format, a 32 bytes header starting with the size
+	buf.Reset()
+	old := make([]byte, 32+8)
+	old[2] = 8
+	buf.Write(old)
+	if _, err := rw.ReadMsg(); !errors.Is(err, ErrFrameVersion) {
+		t.Fatalf("old frame: %v", err)
+	}
+}
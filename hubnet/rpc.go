@@ -0,0 +1,173 @@
+package hubnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+// MsgKind tells the requests and the responses of the rpc layer from the
+// other messages.
+type MsgKind uint8
+
+const (
+	KindMessage  MsgKind = iota // a message to the message handler
+	KindRequest                 // a request to the handler of its code
+	KindResponse                // the response to the request of the same id
+	KindError                   // the error of the request of the same id
+)
+
+// RequestHandler handles the requests of a code received on s, the message
+// returned is the response and an error is sent back to the requester. A
+// handler resetting s drops the peer, no response is sent then.
+type RequestHandler func(s network.Stream, req *Msg) (*Msg, error)
+
+// RemoteError is the error returned by the handler of a request of a peer.
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return "remote: " + e.Message
+}
+
+var errStreamClosed = errors.New("stream closed")
+
+// pendingRequest is a request waiting for its response on the stream s.
+type pendingRequest struct {
+	s  network.Stream
+	ch chan *Msg
+}
+
+// rpc keeps the requests in flight by id and the request handlers by code.
+type rpc struct {
+	lastID   uint64
+	pending  map[uint64]*pendingRequest
+	handlers map[uint16]RequestHandler
+	mu       sync.Mutex
+}
+
+func newRPC() *rpc {
+	return &rpc{
+		pending:  make(map[uint64]*pendingRequest),
+		handlers: make(map[uint16]RequestHandler),
+	}
+}
+
+// add returns the id and the response channel of a request sent on s.
+func (r *rpc) add(s network.Stream) (uint64, <-chan *Msg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastID++
+	req := &pendingRequest{s: s, ch: make(chan *Msg, 1)}
+	r.pending[r.lastID] = req
+	return r.lastID, req.ch
+}
+
+func (r *rpc) remove(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, id)
+}
+
+// resolve passes the response msg to its request, responses of requests which
+// are done are dropped.
+func (r *rpc) resolve(msg *Msg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.pending[msg.ID]
+	if !ok {
+		log.Debug("Drop response", "id", msg.ID, "code", msg.Code)
+		return
+	}
+	delete(r.pending, msg.ID)
+	req.ch <- msg
+}
+
+// fail closes the requests waiting on the stream s, which is closed.
+func (r *rpc) fail(s network.Stream) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, req := range r.pending {
+		if req.s == s {
+			delete(r.pending, id)
+			close(req.ch)
+		}
+	}
+}
+
+func (r *rpc) handler(code uint16) RequestHandler {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.handlers[code]
+}
+
+// RegisterHandler sets the handler of the requests of code.
+func (p2p *P2P) RegisterHandler(code uint16, handler RequestHandler) {
+	p2p.rpc.mu.Lock()
+	defer p2p.rpc.mu.Unlock()
+	p2p.rpc.handlers[code] = handler
+}
+
+// Request sends the request msg to the peer and waits for its response until
+// ctx is done. Requests to a peer may be in flight at once, the responses are
+// matched by the request id.
+func (p2p *P2P) Request(ctx context.Context, addr *peer.AddrInfo, msg *Msg) (*Msg, error) {
+	s, err := p2p.streamMng.get(addr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get stream: %w", err)
+	}
+
+	id, ch := p2p.rpc.add(s)
+	defer p2p.rpc.remove(id)
+
+	req := *msg
+	req.Kind, req.ID = KindRequest, id
+	if err := p2p.send(s, &req); err != nil {
+		p2p.streamMng.remove(addr.ID)
+		return nil, err
+	}
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, fmt.Errorf("request %d to node[%s]: %w", id, addr.ID, errStreamClosed)
+		}
+		if resp.Kind == KindError {
+			return nil, &RemoteError{Message: string(resp.Bytes)}
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("request %d to node[%s]: %w", id, addr.ID, ctx.Err())
+	}
+}
+
+// handleRequest answers the request req received on s by the handler of its
+// code.
+func (p2p *P2P) handleRequest(s network.Stream, req *Msg) {
+	var resp Msg
+	handler := p2p.rpc.handler(req.Code)
+	if handler == nil {
+		resp = errorMsg(req.Code, fmt.Errorf("no handler of code %d", req.Code))
+	} else if ret, err := handler(s, req); err != nil {
+		resp = errorMsg(req.Code, err)
+	} else {
+		resp = *ret
+		resp.Kind = KindResponse
+	}
+	resp.ID = req.ID
+
+	if err := p2p.send(s, &resp); err != nil {
+		log.Info("Send response", "id", req.ID, "code", req.Code, "err", err)
+	}
+}
+
+func errorMsg(code uint16, err error) Msg {
+	b := []byte(err.Error())
+	return Msg{Code: code, Size: uint32(len(b)), Bytes: b, Kind: KindError}
+}
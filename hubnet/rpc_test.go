@@ -0,0 +1,86 @@
+package hubnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+)
+
+func TestP2P_Request(t *testing.T) {
+	p1, _ := generateNetwork(t, 6009)
+	p2, addr2 := generateNetwork(t, 6010)
+
+	// the responses of the later requests are sent first
+	p2.RegisterHandler(1, func(s network.Stream, req *Msg) (*Msg, error) {
+		var n uint64
+		if err := req.Decode(&n); err != nil {
+			return nil, err
+		}
+		time.Sleep(time.Duration(20-n) * time.Millisecond)
+		return NewMsg(2, n*n)
+	})
+	p2.RegisterHandler(3, func(s network.Stream, req *Msg) (*Msg, error) {
+		return nil, fmt.Errorf("refused")
+	})
+	p2.RegisterHandler(4, func(s network.Stream, req *Msg) (*Msg, error) {
+		time.Sleep(time.Second)
+		return NewMsg(4, []byte(nil))
+	})
+	if err := p1.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p2.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p1.Connect(addr2); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(n uint64) {
+			defer wg.Done()
+			req, err := NewMsg(1, n)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp, err := p1.Send(addr2, req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var square uint64
+			if err := resp.Decode(&square); err != nil || resp.Code != 2 || square != n*n {
+				t.Errorf("request %d: response %d of code %d, %v", n, square, resp.Code, err)
+			}
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	req, err := NewMsg(3, []byte(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remote *RemoteError
+	if _, err := p1.Send(addr2, req); !errors.As(err, &remote) || remote.Message != "refused" {
+		t.Fatalf("handler error: %v", err)
+	}
+	req.Code = 5
+	if _, err := p1.Send(addr2, req); !errors.As(err, &remote) {
+		t.Fatalf("code without handler: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req.Code = 4
+	if _, err := p1.Request(ctx, addr2, req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("timeout: %v", err)
+	}
+}
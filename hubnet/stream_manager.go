@@ -21,16 +21,19 @@ type streamMgr struct {
 	ctx        context.Context
 	protocolID protocol.ID
 	host       host.Host
+	// opened is called with the streams opened
+	opened func(network.Stream)
 
 	streams map[peer.ID]network.Stream
 	sync.RWMutex
 }
 
-func newStreamMng(ctx context.Context, host host.Host, protocolID protocol.ID) *streamMgr {
+func newStreamMng(ctx context.Context, host host.Host, protocolID protocol.ID, opened func(network.Stream)) *streamMgr {
 	return &streamMgr{
 		ctx:        ctx,
 		protocolID: protocolID,
 		host:       host,
+		opened:     opened,
 		streams:    make(map[peer.ID]network.Stream),
 	}
 }
@@ -54,6 +57,9 @@ func (mng *streamMgr) get(pid peer.ID) (network.Stream, error) {
 	log.Info("NewStream","id",pid,"mng",mng.protocolID)
 
 	mng.streams[pid] = s
+	if mng.opened != nil {
+		mng.opened(s)
+	}
 
 	return s, nil
 }
@@ -63,3 +69,12 @@ func (mng *streamMgr) remove(pid peer.ID) {
 	defer mng.Unlock()
 	delete(mng.streams, pid)
 }
+
+// removeStream removes the stream s of pid if it is still in use.
+func (mng *streamMgr) removeStream(pid peer.ID, s network.Stream) {
+	mng.Lock()
+	defer mng.Unlock()
+	if mng.streams[pid] == s {
+		delete(mng.streams, pid)
+	}
+}
@@ -0,0 +1,237 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/core/types"
+)
+
+// AdapterType is the kind of adapter serving a chain.
+type AdapterType string
+
+const (
+	Simplechain AdapterType = "simplechain"
+	Fabric      AdapterType = "fabric"
+)
+
+const (
+	// defaultHubChainId signs the rtx exchanged between hubs, the ctxs are
+	// signed for the contract of their purpose chain
+	defaultHubChainId = 11
+
+	// chain ids of the demo pair, used when crosshub.toml has no [[chains]]
+	demoSimplechain uint8 = 2
+	demoFabric      uint8 = 5
+
+	// legacy role of a hub serving the simplechain of the demo pair
+	simplechainRole uint8 = 1
+
+	defaultConfirmations = 1
+)
+
+var ErrUnknownChain = errors.New("unknown chain")
+
+// Chain is a chain known by the hub, an adapter serves it unless it is Remote.
+type Chain struct {
+	Purpose       uint8
+	ChainId       *big.Int
+	Type          AdapterType
+	Contract      common.Address
+	Endpoint      string
+	Subscribe     string
+	Confirmations uint64
+	StartHeight   uint64
+	Key           string
+	DataDir       string
+	Remote        bool
+	Sender        repo.Sender
+	Fabric        repo.Fabric
+}
+
+// TxSigner returns the signer of transactions sent to the chain.
+func (c *Chain) TxSigner() types.Signer {
+	return types.NewEIP155Signer(c.ChainId)
+}
+
+// CtxSigner returns the signer of the ctx signatures verified by the chain contract.
+func (c *Chain) CtxSigner() core.CtxSigner {
+	return core.MakeCtxSigner(c.ChainId)
+}
+
+func (c *Chain) String() string {
+	return fmt.Sprintf("%s(purpose=%d,chainId=%s)", c.Type, c.Purpose, c.ChainId)
+}
+
+// Registry maps the Origin/Purpose of cross transactions to the chains served
+// by the hub.
+type Registry struct {
+	hubChainId *big.Int
+	chains     map[uint8]*Chain
+}
+
+// New loads the registry from config. A config without chains falls back to
+// the simplechain(2)/fabric(5) demo pair, serving the chain picked by Role.
+func New(cfg *repo.Config) (*Registry, error) {
+	hubChainId := cfg.Hub.ChainId
+	if hubChainId == 0 {
+		hubChainId = defaultHubChainId
+	}
+	r := &Registry{
+		hubChainId: new(big.Int).SetUint64(hubChainId),
+		chains:     make(map[uint8]*Chain),
+	}
+
+	chains := cfg.Chains
+	if len(chains) == 0 {
+		chains = legacyChains(cfg)
+	}
+	for _, c := range chains {
+		if err := r.add(cfg.RepoRoot, c); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// legacyChains converts the single chain config of the demo pair.
+func legacyChains(cfg *repo.Config) []repo.Chain {
+	var peerUrl string
+	if len(cfg.Fabric.PeerUrl) > 0 {
+		peerUrl = cfg.Fabric.PeerUrl[0]
+	}
+	return []repo.Chain{
+		{
+			Purpose:  demoSimplechain,
+			ChainId:  uint64(demoSimplechain),
+			Type:     string(Simplechain),
+			Contract: cfg.Contract,
+			Endpoint: fmt.Sprintf("http://%s:%s", cfg.RpcIp, cfg.RpcPort),
+			DataDir:  cfg.Fabric.DataDir,
+			Remote:   cfg.Role != simplechainRole,
+		},
+		{
+			Purpose:  demoFabric,
+			ChainId:  uint64(demoFabric),
+			Type:     string(Fabric),
+			Endpoint: peerUrl,
+			DataDir:  cfg.Fabric.DataDir,
+			Remote:   cfg.Role == simplechainRole,
+			Fabric:   cfg.Fabric,
+		},
+	}
+}
+
+func (r *Registry) add(repoRoot string, c repo.Chain) error {
+	if _, ok := r.chains[c.Purpose]; ok {
+		return fmt.Errorf("duplicate chain purpose %d", c.Purpose)
+	}
+	typ := AdapterType(c.Type)
+	switch typ {
+	case Simplechain, Fabric:
+	default:
+		return fmt.Errorf("chain %d: unknown type %q", c.Purpose, c.Type)
+	}
+	if c.ChainId == 0 {
+		return fmt.Errorf("chain %d: chainid not set", c.Purpose)
+	}
+	chain := &Chain{
+		Purpose:       c.Purpose,
+		ChainId:       new(big.Int).SetUint64(c.ChainId),
+		Type:          typ,
+		Contract:      common.HexToAddress(c.Contract),
+		Endpoint:      c.Endpoint,
+		Subscribe:     c.Subscribe,
+		Confirmations: c.Confirmations,
+		StartHeight:   c.StartHeight,
+		Key:           c.Key,
+		DataDir:       c.DataDir,
+		Remote:        c.Remote,
+		Sender:        c.Sender,
+		Fabric:        c.Fabric,
+	}
+	// remote chains are only used to map ids and signers
+	if !chain.Remote {
+		if c.Endpoint == "" {
+			return fmt.Errorf("chain %d: endpoint not set", c.Purpose)
+		}
+		if typ == Simplechain && !common.IsHexAddress(c.Contract) {
+			return fmt.Errorf("chain %d: invalid contract address %q", c.Purpose, c.Contract)
+		}
+		if chain.Confirmations == 0 {
+			chain.Confirmations = defaultConfirmations
+		}
+		if chain.DataDir == "" {
+			chain.DataDir = repo.GetStoragePath(repoRoot, fmt.Sprintf("chain%d", c.Purpose))
+		}
+		if typ == Fabric {
+			if len(chain.Fabric.PeerUrl) == 0 {
+				chain.Fabric.PeerUrl = []string{c.Endpoint}
+			}
+			if chain.Fabric.DataDir == "" {
+				chain.Fabric.DataDir = chain.DataDir
+			}
+		}
+	}
+	r.chains[c.Purpose] = chain
+	return nil
+}
+
+// Chain returns the chain of the Origin/Purpose id.
+func (r *Registry) Chain(purpose uint8) (*Chain, error) {
+	c, ok := r.chains[purpose]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownChain, purpose)
+	}
+	return c, nil
+}
+
+// Chains returns all chains ordered by purpose.
+func (r *Registry) Chains() []*Chain {
+	chains := make([]*Chain, 0, len(r.chains))
+	for _, c := range r.chains {
+		chains = append(chains, c)
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].Purpose < chains[j].Purpose
+	})
+	return chains
+}
+
+// Locals returns the chains served by adapters of this hub, ordered by purpose.
+func (r *Registry) Locals() []*Chain {
+	var chains []*Chain
+	for _, c := range r.Chains() {
+		if !c.Remote {
+			chains = append(chains, c)
+		}
+	}
+	return chains
+}
+
+// Remotes returns the chains other than purpose, ordered by purpose.
+func (r *Registry) Remotes(purpose uint8) []*Chain {
+	var chains []*Chain
+	for _, c := range r.Chains() {
+		if c.Purpose != purpose {
+			chains = append(chains, c)
+		}
+	}
+	return chains
+}
+
+// HubChainId returns the chain id signing messages between hubs.
+func (r *Registry) HubChainId() *big.Int {
+	return r.hubChainId
+}
+
+// HubRtxSigner returns the signer of rtx exchanged between hubs.
+func (r *Registry) HubRtxSigner() core.RtxSigner {
+	return core.MakeRtxSigner(r.hubChainId)
+}
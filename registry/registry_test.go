@@ -0,0 +1,90 @@
+package registry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/simplechain-org/crosshub/repo"
+)
+
+func TestNew_DemoPair(t *testing.T) {
+	reg, err := New(&repo.Config{
+		Role:     1,
+		Contract: "0x737217d6768E96fee112c16a0A9DcF0af5d56979",
+		RpcIp:    "127.0.0.1",
+		RpcPort:  "8545",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.HubChainId().Uint64() != defaultHubChainId {
+		t.Fatalf("HubChainId, want: %d, got: %s", defaultHubChainId, reg.HubChainId())
+	}
+	chains := reg.Chains()
+	if len(chains) != 2 || chains[0].Purpose != 2 || chains[1].Purpose != 5 {
+		t.Fatalf("Chains, want: [2 5], got: %v", chains)
+	}
+	remotes := reg.Remotes(2)
+	if len(remotes) != 1 || remotes[0].Type != Fabric {
+		t.Fatalf("Remotes(2), want: [fabric], got: %v", remotes)
+	}
+	locals := reg.Locals()
+	if len(locals) != 1 || locals[0].Endpoint != "http://127.0.0.1:8545" {
+		t.Fatalf("Locals, want: [simplechain], got: %v", locals)
+	}
+}
+
+func TestNew_Chains(t *testing.T) {
+	reg, err := New(&repo.Config{
+		Hub: repo.Hub{ChainId: 100},
+		Chains: []repo.Chain{
+			{Purpose: 3, ChainId: 1001, Type: "simplechain", Endpoint: "http://127.0.0.1:8545",
+				Contract: "0x737217d6768E96fee112c16a0A9DcF0af5d56979"},
+			{Purpose: 7, ChainId: 1002, Type: "simplechain", Endpoint: "http://127.0.0.1:8546",
+				Contract: "0x71B4B8fd103dcDA2b971b1677ec70a96Ad24FB38", Confirmations: 12},
+			{Purpose: 9, ChainId: 1003, Type: "fabric", Remote: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := reg.Chain(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ChainId.Uint64() != 1002 || c.Confirmations != 12 {
+		t.Fatalf("chain 7, want: chainId 1002 confirmations 12, got: %s %d", c.ChainId, c.Confirmations)
+	}
+	if c, _ := reg.Chain(3); c.Confirmations != defaultConfirmations {
+		t.Fatalf("Confirmations, want: %d, got: %d", defaultConfirmations, c.Confirmations)
+	}
+	if _, err := reg.Chain(2); !errors.Is(err, ErrUnknownChain) {
+		t.Fatalf("want ErrUnknownChain, got %v", err)
+	}
+	if len(reg.Locals()) != 2 {
+		t.Fatalf("Locals, want: 2, got: %d", len(reg.Locals()))
+	}
+	if reg.HubChainId().Uint64() != 100 {
+		t.Fatalf("HubChainId, want: 100, got: %s", reg.HubChainId())
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := [][]repo.Chain{
+		// duplicate purpose
+		{{Purpose: 5, ChainId: 5, Type: "fabric", Remote: true}, {Purpose: 5, ChainId: 6, Type: "fabric", Remote: true}},
+		// unknown type
+		{{Purpose: 5, ChainId: 5, Type: "bitcoin", Remote: true}},
+		// missing chain id
+		{{Purpose: 5, Type: "fabric", Remote: true}},
+		// missing endpoint
+		{{Purpose: 5, ChainId: 5, Type: "fabric"}},
+		// missing contract
+		{{Purpose: 2, ChainId: 2, Type: "simplechain", Endpoint: "http://127.0.0.1:8545"}},
+	}
+	for i, chains := range tests {
+		if _, err := New(&repo.Config{Chains: chains}); err == nil {
+			t.Errorf("test %d: want error, got nil", i)
+		}
+	}
+}
@@ -90,19 +90,94 @@ type Config struct {
 	RpcPort  string `toml:"rpcport" json:"rpc_port"`
 	Port     `toml:"port" json:"port"`
 	Gateway  `toml:"gateway" json:"gateway"`
+	Admin    `toml:"admin" json:"admin"`
+	Auth     `toml:"auth" json:"auth"`
 	Cert     `toml:"cert" json:"cert"`
 	Fabric   `toml:"fabric" json:"fabric"`
+	Hub      `toml:"hub" json:"hub"`
+	P2P      `toml:"p2p" json:"p2p"`
+	Chains   []Chain `toml:"chains" json:"chains"`
+}
+
+// Hub holds the chain id used to sign messages exchanged between hubs.
+type Hub struct {
+	ChainId uint64 `toml:"chainid" json:"chainid"`
+}
+
+// P2P sets the framing of the messages exchanged between hubs.
+type P2P struct {
+	MaxMsgSize uint32 `toml:"max_msg_size" json:"max_msg_size" mapstructure:"max_msg_size"` //消息大小上限（字节），为0时为4MB
+	Compress   bool   `toml:"compress" json:"compress"`                                     //是否用snappy压缩发出的消息
+}
+
+// Chain describes a chain section of crosshub.toml, Purpose is the uint8 id the
+// cross contracts use as Origin/Purpose of a cross transaction. An adapter is
+// started for every section unless it is Remote, i.e. served by another hub.
+type Chain struct {
+	Purpose       uint8  `toml:"purpose" json:"purpose"`
+	ChainId       uint64 `toml:"chainid" json:"chainid"`
+	Type          string `toml:"type" json:"type"`
+	Contract      string `toml:"contract" json:"contract"`
+	Endpoint      string `toml:"endpoint" json:"endpoint"`
+	Subscribe     string `toml:"subscribe" json:"subscribe"` //websocket或ipc地址，用于eth_subscribe推送日志，为空时只轮询
+	Confirmations uint64 `toml:"confirmations" json:"confirmations"`
+	StartHeight   uint64 `toml:"start_height" json:"start_height" mapstructure:"start_height"`
+	Key           string `toml:"key" json:"key"` //签名私钥路径，相对于repo_root，默认使用节点私钥
+	DataDir       string `toml:"datadir" json:"datadir"`
+	Remote        bool   `toml:"remote" json:"remote"`
+	Sender        Sender `toml:"sender" json:"sender"`
+	Fabric        Fabric `toml:"fabric" json:"fabric"`
+}
+
+// Sender is the gas policy of the transactions a simplechain adapter sends.
+type Sender struct {
+	GasPrice    uint64 `toml:"gas_price" json:"gas_price" mapstructure:"gas_price"`             //固定gas价格，为0时使用节点建议价格
+	MinGasPrice uint64 `toml:"min_gas_price" json:"min_gas_price" mapstructure:"min_gas_price"` //建议价格下限
+	MaxGasPrice uint64 `toml:"max_gas_price" json:"max_gas_price" mapstructure:"max_gas_price"` //建议价格和加价的上限，为0时不限
+	GasMargin   uint64 `toml:"gas_margin" json:"gas_margin" mapstructure:"gas_margin"`          //预估gas增加的百分比
+	PriceBump   uint64 `toml:"price_bump" json:"price_bump" mapstructure:"price_bump"`          //重发时gas价格增加的百分比
+	Resubmit    uint64 `toml:"resubmit" json:"resubmit"`                                        //交易未上链多少秒后加价重发
+	Takeover    uint64 `toml:"takeover" json:"takeover"`                                        //选举的提交者超过多少秒未完成时由下一个锚定节点接替
 }
 
 type Port struct {
 	Grpc    int64 `toml:"grpc" json:"grpc"`
+	JsonRpc int64 `toml:"jsonrpc" json:"jsonrpc"` //json-rpc和websocket端口，为0时json-rpc使用grpc端口且不启动grpc
 	Gateway int64 `toml:"gateway" json:"gateway"`
+	Metrics int64 `toml:"metrics" json:"metrics"` //prometheus指标端口，为0时不采集指标
 }
 
 type Gateway struct {
 	AllowedOrigins []string `toml:"allowed_origins" mapstructure:"allowed_origins"`
 }
 
+// Admin enables the admin json-rpc namespace, served at /admin on the jsonrpc
+// port to requests with the bearer token.
+type Admin struct {
+	Token string `toml:"token" json:"-"` //为空时不启动admin接口，也可由环境变量CROSSHUB_ADMIN_TOKEN设置
+}
+
+// Auth holds the credentials of the json-rpc and gateway clients. Once a key
+// or the jwt secret is set, every request needs a credential: an api key or an
+// HS256 jwt with the "sub" and "role" claims. Role is "read" or "admin", Rate
+// is the requests per second allowed to a credential and Burst the requests
+// allowed at once, 0 is unlimited.
+type Auth struct {
+	JwtSecret string   `toml:"jwt_secret" json:"-" mapstructure:"jwt_secret"` //jwt的HS256密钥，为空时不接受jwt
+	Rate      float64  `toml:"rate" json:"rate"`                              //每个凭证的默认限速
+	Burst     int      `toml:"burst" json:"burst"`
+	Keys      []ApiKey `toml:"keys" json:"-"`
+}
+
+// ApiKey is a credential of Auth, unset Rate and Burst default to the ones of Auth.
+type ApiKey struct {
+	Name  string  `toml:"name" json:"name"`
+	Key   string  `toml:"key" json:"-"`
+	Role  string  `toml:"role" json:"role"`
+	Rate  float64 `toml:"rate" json:"rate"`
+	Burst int     `toml:"burst" json:"burst"`
+}
+
 type Cert struct {
 	Verify bool `toml:"verify" json:"verify"`
 }
@@ -117,6 +192,9 @@ type Fabric struct {
 	DataDir     string   `toml:"datadir" json:"datadir"`
 	Outchain    bool     `toml:"outchain" json:"outchain"`
 	LogLevel    string   `toml:"loglevel" json:"loglevel"`
+	// Target is the purpose of the chain taking the cross transactions made on
+	// fabric, the first simplechain is used if not set
+	Target uint8 `toml:"target" json:"target"`
 }
 
 func (c *Config) Bytes() ([]byte, error) {
@@ -135,10 +213,12 @@ func DefaultConfig() (*Config, error) {
 		//RpcUrl: "http://112.124.0.14:58545",
 		Port: Port{
 			Grpc:    60011,
+			JsonRpc: 60010,
 			Gateway: 9091,
 		},
 		Gateway: Gateway{AllowedOrigins: []string{"*"}},
 		Cert:    Cert{Verify: true},
+		Hub:     Hub{ChainId: 11},
 	}, nil
 }
 
@@ -122,3 +122,22 @@ func loadPrivKey(repoRoot string) (*Key, error) {
 		Libp2pPrivKey: libp2pPrivKey,
 	}, nil
 }
+
+// LoadChainKey loads the PEM encoded signing key of a chain section, path is
+// relative to the repo root.
+func LoadChainKey(repoRoot, path string) (*ecdsa.PrivateKey, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stdPriv, err := cert.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", path, err)
+	}
+
+	return &ecdsa.PrivateKey{K: stdPriv}, nil
+}
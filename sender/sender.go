@@ -0,0 +1,400 @@
+// Package sender submits the transactions of a hub account to a simplechain
+// chain. It manages the nonces locally, estimates gas, prices transactions by a
+// gas price policy, tracks their receipts and resubmits stuck transactions with
+// a bumped price. In-flight transactions are persisted so they are tracked
+// again after a restart.
+package sender
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asdine/storm/v3"
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain"
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/core/types"
+	"github.com/simplechain-org/go-simplechain/log"
+	"github.com/simplechain-org/go-simplechain/rlp"
+)
+
+const (
+	defaultGasMargin = 20
+	// txpool replacements need a price at least 10% higher
+	defaultPriceBump = 10
+	defaultResubmit  = time.Minute
+	trackInterval    = 5 * time.Second
+	rpcTimeout       = 30 * time.Second
+)
+
+var (
+	ErrReverted = errors.New("transaction reverted")
+	ErrReplaced = errors.New("transaction nonce used by another transaction")
+)
+
+// Backend is the chain api used by the sender, *ethclient.Client implements it.
+type Backend interface {
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg simplechain.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// SignFn signs the hash of a transaction with the key of the sender account.
+type SignFn func(hash []byte) ([]byte, error)
+
+// DoneFn is called once a transaction is mined or dropped. receipt is nil if
+// the transaction is dropped.
+type DoneFn func(id common.Hash, receipt *types.Receipt, err error)
+
+// record is an in-flight transaction, Txs keeps every submission of the nonce,
+// the last one is the current.
+type record struct {
+	ID    common.Hash `storm:"id"`
+	Nonce uint64
+	Txs   [][]byte
+	Sent  time.Time
+}
+
+func (r *record) current() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(r.Txs[len(r.Txs)-1], tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (r *record) add(tx *types.Transaction) error {
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	r.Txs = append(r.Txs, raw)
+	r.Sent = time.Now()
+	return nil
+}
+
+type Sender struct {
+	backend Backend
+	db      storm.Node
+	from    common.Address
+	signer  types.Signer
+	sign    SignFn
+	done    DoneFn
+
+	gasPrice    *big.Int
+	minGasPrice *big.Int
+	maxGasPrice *big.Int
+	gasMargin   uint64
+	priceBump   uint64
+	resubmit    time.Duration
+
+	mu     sync.Mutex
+	nonce  uint64
+	synced bool
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates the sender of account from, in-flight transactions are kept in db.
+func New(backend Backend, db storm.Node, signer types.Signer, from common.Address, sign SignFn, cfg repo.Sender, done DoneFn) *Sender {
+	s := &Sender{
+		backend:   backend,
+		db:        db,
+		from:      from,
+		signer:    signer,
+		sign:      sign,
+		done:      done,
+		gasMargin: cfg.GasMargin,
+		priceBump: cfg.PriceBump,
+		resubmit:  time.Duration(cfg.Resubmit) * time.Second,
+		quit:      make(chan struct{}),
+	}
+	if cfg.GasPrice != 0 {
+		s.gasPrice = new(big.Int).SetUint64(cfg.GasPrice)
+	}
+	if cfg.MinGasPrice != 0 {
+		s.minGasPrice = new(big.Int).SetUint64(cfg.MinGasPrice)
+	}
+	if cfg.MaxGasPrice != 0 {
+		s.maxGasPrice = new(big.Int).SetUint64(cfg.MaxGasPrice)
+	}
+	if s.gasMargin == 0 {
+		s.gasMargin = defaultGasMargin
+	}
+	if s.priceBump == 0 {
+		s.priceBump = defaultPriceBump
+	}
+	if s.resubmit == 0 {
+		s.resubmit = defaultResubmit
+	}
+	if s.done == nil {
+		s.done = func(common.Hash, *types.Receipt, error) {}
+	}
+	return s
+}
+
+// Start tracks the in-flight transactions, those persisted before a restart included.
+func (s *Sender) Start() {
+	log.Info("sender started", "from", s.from.String(), "inflight", s.Pending())
+	s.wg.Add(1)
+	go s.loop()
+}
+
+func (s *Sender) Stop() {
+	close(s.quit)
+	s.wg.Wait()
+}
+
+// From returns the sender account.
+func (s *Sender) From() common.Address {
+	return s.from
+}
+
+// Pending returns the number of in-flight transactions.
+func (s *Sender) Pending() int {
+	n, err := s.db.Count(&record{})
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Send submits a transaction calling to with data. id identifies the request,
+// the in-flight transaction of an id already sent is returned.
+func (s *Sender) Send(ctx context.Context, id common.Hash, to common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rec record
+	if err := s.db.One("ID", id, &rec); err == nil {
+		return rec.current()
+	} else if err != storm.ErrNotFound {
+		return nil, err
+	}
+
+	if value == nil {
+		value = new(big.Int)
+	}
+	gas, err := s.backend.EstimateGas(ctx, simplechain.CallMsg{From: s.from, To: &to, Value: value, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	gas += gas * s.gasMargin / 100
+	price, err := s.price(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for retry := true; ; retry = false {
+		if err := s.syncNonce(ctx); err != nil {
+			return nil, err
+		}
+		tx, err := s.signTx(types.NewTransaction(s.nonce, to, value, gas, price, data))
+		if err != nil {
+			return nil, err
+		}
+		err = s.backend.SendTransaction(ctx, tx)
+		if err != nil {
+			// the nonce is used outside of the sender, start over from the chain
+			if isNonceTooLow(err) && retry {
+				log.Warn("sender nonce too low, resync", "from", s.from.String(), "nonce", s.nonce)
+				s.synced = false
+				continue
+			}
+			return nil, err
+		}
+
+		rec = record{ID: id, Nonce: tx.Nonce()}
+		if err := rec.add(tx); err != nil {
+			return nil, err
+		}
+		s.nonce++
+		if err := s.db.Save(&rec); err != nil {
+			log.Error("sender persist", "id", id.String(), "tx", tx.Hash().String(), "err", err)
+		}
+		log.Info("sender sent", "id", id.String(), "tx", tx.Hash().String(), "nonce", tx.Nonce(),
+			"gas", gas, "gasPrice", price)
+		return tx, nil
+	}
+}
+
+// syncNonce loads the next nonce from the chain, above the persisted in-flight nonces.
+func (s *Sender) syncNonce(ctx context.Context) error {
+	if s.synced {
+		return nil
+	}
+	nonce, err := s.backend.PendingNonceAt(ctx, s.from)
+	if err != nil {
+		return err
+	}
+	var records []record
+	if err := s.db.All(&records); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		if rec.Nonce >= nonce {
+			nonce = rec.Nonce + 1
+		}
+	}
+	s.nonce, s.synced = nonce, true
+	return nil
+}
+
+// price returns the gas price of a new transaction by the gas price policy.
+func (s *Sender) price(ctx context.Context) (*big.Int, error) {
+	if s.gasPrice != nil {
+		return new(big.Int).Set(s.gasPrice), nil
+	}
+	price, err := s.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.minGasPrice != nil && price.Cmp(s.minGasPrice) < 0 {
+		price = new(big.Int).Set(s.minGasPrice)
+	}
+	return s.capPrice(price), nil
+}
+
+func (s *Sender) capPrice(price *big.Int) *big.Int {
+	if s.maxGasPrice != nil && price.Cmp(s.maxGasPrice) > 0 {
+		return new(big.Int).Set(s.maxGasPrice)
+	}
+	return price
+}
+
+func (s *Sender) signTx(tx *types.Transaction) (*types.Transaction, error) {
+	signature, err := s.sign(s.signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(s.signer, signature)
+}
+
+func (s *Sender) loop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(trackInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+			s.track()
+		}
+	}
+}
+
+// track checks the receipts of the in-flight transactions and resubmits the
+// stuck ones.
+func (s *Sender) track() {
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	var records []record
+	if err := s.db.All(&records); err != nil {
+		log.Error("sender load", "err", err)
+		return
+	}
+	if len(records) == 0 {
+		return
+	}
+	mined, err := s.backend.NonceAt(ctx, s.from, nil)
+	if err != nil {
+		log.Warn("sender nonce", "from", s.from.String(), "err", err)
+		return
+	}
+	for i := range records {
+		if err := s.trackRecord(ctx, &records[i], mined); err != nil {
+			log.Warn("sender track", "id", records[i].ID.String(), "nonce", records[i].Nonce, "err", err)
+		}
+	}
+}
+
+func (s *Sender) trackRecord(ctx context.Context, rec *record, mined uint64) error {
+	// any submission of the nonce may be mined
+	for _, raw := range rec.Txs {
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(raw, tx); err != nil {
+			return err
+		}
+		receipt, err := s.backend.TransactionReceipt(ctx, tx.Hash())
+		if err == simplechain.NotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			err = ErrReverted
+		}
+		log.Info("sender mined", "id", rec.ID.String(), "tx", tx.Hash().String(), "status", receipt.Status)
+		return s.finish(rec, receipt, err)
+	}
+	if rec.Nonce < mined {
+		log.Warn("sender dropped", "id", rec.ID.String(), "nonce", rec.Nonce, "mined", mined)
+		return s.finish(rec, nil, ErrReplaced)
+	}
+	if time.Since(rec.Sent) < s.resubmit {
+		return nil
+	}
+	return s.bump(ctx, rec)
+}
+
+func (s *Sender) finish(rec *record, receipt *types.Receipt, err error) error {
+	if delErr := s.db.DeleteStruct(rec); delErr != nil && delErr != storm.ErrNotFound {
+		return delErr
+	}
+	s.done(rec.ID, receipt, err)
+	return nil
+}
+
+// bump resubmits the stuck transaction of rec with a higher gas price, it is
+// broadcast again as is once the price reached the cap.
+func (s *Sender) bump(ctx context.Context, rec *record) error {
+	cur, err := rec.current()
+	if err != nil {
+		return err
+	}
+	price := new(big.Int).Mul(cur.GasPrice(), new(big.Int).SetUint64(100+s.priceBump))
+	price = s.capPrice(price.Div(price, big.NewInt(100)))
+	if price.Cmp(cur.GasPrice()) <= 0 {
+		if err := s.backend.SendTransaction(ctx, cur); err != nil && !isKnown(err) {
+			return err
+		}
+		rec.Sent = time.Now()
+		log.Info("sender rebroadcast", "id", rec.ID.String(), "tx", cur.Hash().String(), "gasPrice", cur.GasPrice())
+		return s.db.Save(rec)
+	}
+
+	tx, err := s.signTx(types.NewTransaction(cur.Nonce(), *cur.To(), cur.Value(), cur.Gas(), price, cur.Data()))
+	if err != nil {
+		return err
+	}
+	if err := s.backend.SendTransaction(ctx, tx); err != nil && !isKnown(err) {
+		return err
+	}
+	if err := rec.add(tx); err != nil {
+		return err
+	}
+	log.Info("sender resubmit", "id", rec.ID.String(), "tx", tx.Hash().String(), "nonce", tx.Nonce(),
+		"gasPrice", price, "submissions", len(rec.Txs))
+	return s.db.Save(rec)
+}
+
+func isNonceTooLow(err error) bool {
+	return strings.Contains(err.Error(), "nonce too low")
+}
+
+func isKnown(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "known transaction") || strings.Contains(msg, "already known")
+}
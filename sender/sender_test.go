@@ -0,0 +1,230 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/asdine/storm/v3"
+	"github.com/simplechain-org/crosshub/repo"
+
+	"github.com/simplechain-org/go-simplechain"
+	"github.com/simplechain-org/go-simplechain/common"
+	"github.com/simplechain-org/go-simplechain/core/types"
+	"github.com/simplechain-org/go-simplechain/crypto"
+)
+
+type testBackend struct {
+	mu       sync.Mutex
+	pending  uint64
+	mined    uint64
+	price    *big.Int
+	sent     []*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	sendErr  error
+}
+
+func (b *testBackend) NonceAt(context.Context, common.Address, *big.Int) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.mined, nil
+}
+
+func (b *testBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pending, nil
+}
+
+func (b *testBackend) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return new(big.Int).Set(b.price), nil
+}
+
+func (b *testBackend) EstimateGas(context.Context, simplechain.CallMsg) (uint64, error) {
+	return 100000, nil
+}
+
+func (b *testBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.sendErr; err != nil {
+		b.sendErr = nil
+		return err
+	}
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func (b *testBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if receipt, ok := b.receipts[hash]; ok {
+		return receipt, nil
+	}
+	return nil, simplechain.NotFound
+}
+
+type doneCall struct {
+	id      common.Hash
+	receipt *types.Receipt
+	err     error
+}
+
+func newTestSender(t *testing.T, backend *testBackend, cfg repo.Sender) (*Sender, *[]doneCall) {
+	dir, err := ioutil.TempDir("", "sender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := storm.Open(filepath.Join(dir, "sender.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		os.RemoveAll(dir)
+	})
+	key, _ := crypto.GenerateKey()
+	sign := func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	}
+	var calls []doneCall
+	s := New(backend, db.From("sender"), types.NewEIP155Signer(big.NewInt(1)), crypto.PubkeyToAddress(key.PublicKey), sign, cfg,
+		func(id common.Hash, receipt *types.Receipt, err error) {
+			calls = append(calls, doneCall{id, receipt, err})
+		})
+	return s, &calls
+}
+
+func TestSender_Send(t *testing.T) {
+	backend := &testBackend{pending: 7, price: big.NewInt(1e9), receipts: make(map[common.Hash]*types.Receipt)}
+	s, _ := newTestSender(t, backend, repo.Sender{MinGasPrice: 2e9})
+	to := common.HexToAddress("0x01")
+
+	tx1, err := s.Send(context.Background(), common.HexToHash("0x01"), to, nil, []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx1.Nonce() != 7 || tx1.Gas() != 120000 || tx1.GasPrice().Int64() != 2e9 {
+		t.Fatalf("unexpected tx: nonce %d gas %d price %v", tx1.Nonce(), tx1.Gas(), tx1.GasPrice())
+	}
+	// the same id is sent once
+	again, err := s.Send(context.Background(), common.HexToHash("0x01"), to, nil, []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Hash() != tx1.Hash() || len(backend.sent) != 1 {
+		t.Fatalf("duplicate send: %d txs", len(backend.sent))
+	}
+	tx2, err := s.Send(context.Background(), common.HexToHash("0x02"), to, nil, []byte{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx2.Nonce() != 8 {
+		t.Fatalf("nonce = %d, want 8", tx2.Nonce())
+	}
+	if s.Pending() != 2 {
+		t.Fatalf("pending = %d, want 2", s.Pending())
+	}
+}
+
+func TestSender_NonceTooLow(t *testing.T) {
+	backend := &testBackend{pending: 3, price: big.NewInt(1e9), receipts: make(map[common.Hash]*types.Receipt)}
+	s, _ := newTestSender(t, backend, repo.Sender{})
+	if _, err := s.Send(context.Background(), common.HexToHash("0x01"), common.HexToAddress("0x01"), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the account sent a transaction outside of the sender
+	backend.pending = 6
+	backend.sendErr = errors.New("nonce too low")
+	tx, err := s.Send(context.Background(), common.HexToHash("0x02"), common.HexToAddress("0x01"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 6 {
+		t.Fatalf("nonce = %d, want 6", tx.Nonce())
+	}
+}
+
+func TestSender_Track(t *testing.T) {
+	backend := &testBackend{pending: 0, price: big.NewInt(1e9), receipts: make(map[common.Hash]*types.Receipt)}
+	s, calls := newTestSender(t, backend, repo.Sender{PriceBump: 10, MaxGasPrice: 1.15e9, Resubmit: 1})
+	id := common.HexToHash("0x01")
+	tx, err := s.Send(context.Background(), id, common.HexToAddress("0x01"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// not stuck yet
+	s.track()
+	if len(backend.sent) != 1 {
+		t.Fatalf("resubmitted early: %d txs", len(backend.sent))
+	}
+
+	// stuck, resubmitted with the bumped price, then with the capped price
+	s.resubmit = 0
+	s.track()
+	s.track()
+	if len(backend.sent) != 3 {
+		t.Fatalf("sent %d txs, want 3", len(backend.sent))
+	}
+	if price := backend.sent[1].GasPrice().Int64(); price != 1.1e9 {
+		t.Fatalf("bumped price = %d, want 1.1e9", price)
+	}
+	if price := backend.sent[2].GasPrice().Int64(); price != 1.15e9 {
+		t.Fatalf("capped price = %d, want 1.15e9", price)
+	}
+	if backend.sent[2].Nonce() != tx.Nonce() {
+		t.Fatalf("resubmitted nonce %d, want %d", backend.sent[2].Nonce(), tx.Nonce())
+	}
+
+	// the first submission is mined
+	backend.receipts[tx.Hash()] = &types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: tx.Hash()}
+	backend.mined = 1
+	s.track()
+	if len(*calls) != 1 || (*calls)[0].id != id || (*calls)[0].err != nil || (*calls)[0].receipt.TxHash != tx.Hash() {
+		t.Fatalf("unexpected done calls: %+v", *calls)
+	}
+	if s.Pending() != 0 {
+		t.Fatalf("pending = %d, want 0", s.Pending())
+	}
+}
+
+func TestSender_Dropped(t *testing.T) {
+	backend := &testBackend{pending: 0, price: big.NewInt(1e9), receipts: make(map[common.Hash]*types.Receipt)}
+	s, calls := newTestSender(t, backend, repo.Sender{Resubmit: 60})
+	if _, err := s.Send(context.Background(), common.HexToHash("0x01"), common.HexToAddress("0x01"), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the nonce is mined by another transaction
+	backend.mined = 1
+	s.track()
+	if len(*calls) != 1 || (*calls)[0].err != ErrReplaced {
+		t.Fatalf("unexpected done calls: %+v", *calls)
+	}
+}
+
+func TestSender_Resume(t *testing.T) {
+	backend := &testBackend{pending: 0, price: big.NewInt(1e9), receipts: make(map[common.Hash]*types.Receipt)}
+	s, _ := newTestSender(t, backend, repo.Sender{})
+	if _, err := s.Send(context.Background(), common.HexToHash("0x01"), common.HexToAddress("0x01"), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// a restarted sender continues above the persisted nonces, the node may
+	// have lost the pending transaction
+	restarted := New(backend, s.db, s.signer, s.from, s.sign, repo.Sender{}, nil)
+	tx, err := restarted.Send(context.Background(), common.HexToHash("0x02"), common.HexToAddress("0x01"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 1 {
+		t.Fatalf("nonce = %d, want 1", tx.Nonce())
+	}
+}
@@ -0,0 +1,218 @@
+package swarm
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/simplechain-org/crosshub/hubnet"
+
+	"github.com/simplechain-org/go-simplechain/rlp"
+)
+
+const (
+	// maxEnvelopeSkew is how far the time of an envelope may be from the local
+	// clock, older envelopes are rejected as replays
+	maxEnvelopeSkew = 2 * time.Minute
+	// replayWindow is the number of sequence numbers below the highest one
+	// received from a peer that are still accepted once
+	replayWindow = 64
+)
+
+var (
+	errMalformed  = errors.New("malformed envelope")
+	errSender     = errors.New("sender is not the stream peer")
+	errUnverified = errors.New("peer has not completed the certificate handshake")
+	errSignature  = errors.New("invalid envelope signature")
+	errStale      = errors.New("envelope time out of range")
+	errReplay     = errors.New("replayed envelope")
+	errPeerKey    = errors.New("peer key does not match the node cert")
+)
+
+// rejectReasons name the rejections of envelopes in the metrics.
+var rejectReasons = map[error]string{
+	errMalformed:  "malformed",
+	errSender:     "sender",
+	errUnverified: "unverified",
+	errSignature:  "signature",
+	errStale:      "stale",
+	errReplay:     "replay",
+	errPeerKey:    "peerkey",
+}
+
+// Envelope wraps every message of the swarm. It is signed by the node
+// certificate key of the sender, NodeId is its peer id, Seq increases with
+// every message it sends and Time is the unix time in milliseconds it was
+// sealed at.
+type Envelope struct {
+	NodeId    string
+	Seq       uint64
+	Time      uint64
+	Code      uint16
+	Payload   []byte
+	Signature []byte
+}
+
+// hash returns the digest of the signed fields of the envelope.
+func (e *Envelope) hash() ([]byte, error) {
+	data, err := rlp.EncodeToBytes([]interface{}{e.NodeId, e.Seq, e.Time, e.Code, e.Payload})
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(data)
+	return h[:], nil
+}
+
+// sign signs the envelope by key, the signature is r and s padded to the size
+// of the curve.
+func (e *Envelope) sign(key *ecdsa.PrivateKey) error {
+	hash, err := e.hash()
+	if err != nil {
+		return err
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash)
+	if err != nil {
+		return err
+	}
+	size := (key.Curve.Params().BitSize + 7) / 8
+	sig := make([]byte, 2*size)
+	rb, sb := r.Bytes(), s.Bytes()
+	copy(sig[size-len(rb):size], rb)
+	copy(sig[2*size-len(sb):], sb)
+	e.Signature = sig
+	return nil
+}
+
+// verify checks the signature of the envelope by the key pub.
+func (e *Envelope) verify(pub *ecdsa.PublicKey) error {
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	if len(e.Signature) != 2*size {
+		return errSignature
+	}
+	hash, err := e.hash()
+	if err != nil {
+		return errSignature
+	}
+	r := new(big.Int).SetBytes(e.Signature[:size])
+	s := new(big.Int).SetBytes(e.Signature[size:])
+	if !ecdsa.Verify(pub, hash, r, s) {
+		return errSignature
+	}
+	return nil
+}
+
+// sealMsg wraps msg in an envelope of the node nodeID signed by key.
+func sealMsg(key *ecdsa.PrivateKey, nodeID string, seq uint64, now time.Time, msg *hubnet.Msg) (*hubnet.Msg, error) {
+	env := &Envelope{
+		NodeId:  nodeID,
+		Seq:     seq,
+		Time:    uint64(now.UnixNano() / int64(time.Millisecond)),
+		Code:    msg.Code,
+		Payload: msg.Bytes,
+	}
+	if err := env.sign(key); err != nil {
+		return nil, fmt.Errorf("sign envelope: %w", err)
+	}
+	return hubnet.NewMsg(msg.Code, env)
+}
+
+// openMsg returns the envelope of msg, its signature is not checked.
+func openMsg(msg *hubnet.Msg) (*Envelope, error) {
+	var env Envelope
+	if err := msg.Decode(&env); err != nil {
+		return nil, fmt.Errorf("%w: %v", errMalformed, err)
+	}
+	if env.Code != msg.Code {
+		return nil, fmt.Errorf("%w: code %d in a message of code %d", errMalformed, env.Code, msg.Code)
+	}
+	return &env, nil
+}
+
+// message returns the message wrapped by the envelope.
+func (e *Envelope) message() *hubnet.Msg {
+	return &hubnet.Msg{Code: e.Code, Size: uint32(len(e.Payload)), Bytes: e.Payload}
+}
+
+// peerState is the certificate key of a peer that completed the handshake and
+// the sequence numbers received from it.
+type peerState struct {
+	key *ecdsa.PublicKey
+	// top is the highest sequence number received, bit i of seen is set if
+	// top-i was received
+	top  uint64
+	seen uint64
+}
+
+// accept records seq as received, it reports false if it was received before
+// or is too far below the highest one.
+func (p *peerState) accept(seq uint64) bool {
+	switch {
+	case seq > p.top:
+		if shift := seq - p.top; shift < replayWindow {
+			p.seen = p.seen<<shift | 1
+		} else {
+			p.seen = 1
+		}
+		p.top = seq
+		return true
+	case p.top-seq >= replayWindow:
+		return false
+	default:
+		bit := uint64(1) << (p.top - seq)
+		if p.seen&bit != 0 {
+			return false
+		}
+		p.seen |= bit
+		return true
+	}
+}
+
+// peerStates keeps the states of the peers by peer id.
+type peerStates struct {
+	peers map[peer.ID]*peerState
+	mu    sync.Mutex
+}
+
+func newPeerStates() *peerStates {
+	return &peerStates{peers: make(map[peer.ID]*peerState)}
+}
+
+// check verifies the envelope env sent by the peer from, by key if set or by
+// the key of the handshake of the peer otherwise. A handshake sets the key of
+// the peer, its received sequence numbers are kept.
+func (ps *peerStates) check(from peer.ID, env *Envelope, key *ecdsa.PublicKey, now time.Time) error {
+	if env.NodeId != from.String() {
+		return errSender
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	state, ok := ps.peers[from]
+	if key == nil {
+		if !ok {
+			return errUnverified
+		}
+		key = state.key
+	}
+	if err := env.verify(key); err != nil {
+		return err
+	}
+	sealed := time.Unix(0, int64(env.Time)*int64(time.Millisecond))
+	if sealed.Before(now.Add(-maxEnvelopeSkew)) || sealed.After(now.Add(maxEnvelopeSkew)) {
+		return errStale
+	}
+	if !ok {
+		state = new(peerState)
+		ps.peers[from] = state
+	}
+	if !state.accept(env.Seq) {
+		return errReplay
+	}
+	state.key = key
+	return nil
+}
@@ -0,0 +1,105 @@
+package swarm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/simplechain-org/crosshub/hubnet"
+)
+
+func TestEnvelope(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := peer.ID("node1")
+	now := time.Now()
+	seal := func(seq uint64, at time.Time) *Envelope {
+		msg, err := hubnet.NewMsg(CtxSignMsg, []byte("ctx"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sealed, err := sealMsg(key, from.String(), seq, at, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := openMsg(sealed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return env
+	}
+
+	states := newPeerStates()
+	if err := states.check(from, seal(1, now), nil, now); !errors.Is(err, errUnverified) {
+		t.Fatalf("before the handshake: %v", err)
+	}
+	if err := states.check(from, seal(2, now), &other.PublicKey, now); !errors.Is(err, errSignature) {
+		t.Fatalf("handshake with another key: %v", err)
+	}
+	if err := states.check(from, seal(3, now), &key.PublicKey, now); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+
+	env := seal(5, now)
+	if err := states.check(from, env, nil, now); err != nil {
+		t.Fatalf("verified peer: %v", err)
+	}
+	if err := states.check(from, env, nil, now); !errors.Is(err, errReplay) {
+		t.Fatalf("replay: %v", err)
+	}
+	if err := states.check(from, seal(4, now), nil, now); err != nil {
+		t.Fatalf("out of order: %v", err)
+	}
+	if err := states.check(peer.ID("node2"), seal(6, now), nil, now); !errors.Is(err, errSender) {
+		t.Fatalf("other sender: %v", err)
+	}
+	if err := states.check(from, seal(7, now.Add(-time.Hour)), nil, now); !errors.Is(err, errStale) {
+		t.Fatalf("stale: %v", err)
+	}
+
+	tampered := seal(8, now)
+	tampered.Payload = []byte("other")
+	if err := states.check(from, tampered, nil, now); !errors.Is(err, errSignature) {
+		t.Fatalf("tampered: %v", err)
+	}
+
+	msg, err := hubnet.NewMsg(RtxSignMsg, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openMsg(msg); !errors.Is(err, errMalformed) {
+		t.Fatalf("code mismatch: %v", err)
+	}
+}
+
+func TestPeerState_Accept(t *testing.T) {
+	var p peerState
+	for _, test := range []struct {
+		seq uint64
+		ok  bool
+	}{
+		{10, true},
+		{10, false},
+		{9, true},
+		{100, true},
+		{40, true},
+		{36, false},
+		{40, false},
+		{200, true},
+		{100, false},
+	} {
+		if ok := p.accept(test.seq); ok != test.ok {
+			t.Fatalf("seq %d: accepted %v, want %v", test.seq, ok, test.ok)
+		}
+	}
+}
@@ -1,60 +1,59 @@
 package swarm
 
 import (
+	"crypto/ecdsa"
 	"crypto/x509"
+	"errors"
 	"fmt"
+	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/simplechain-org/crosshub/cert"
 	"github.com/simplechain-org/crosshub/core"
 	"github.com/simplechain-org/crosshub/hubnet"
 	"github.com/simplechain-org/go-simplechain/log"
+	"time"
 )
 
 func (swarm *Swarm) handleMessage(s network.Stream, data *hubnet.Msg) {
+	msgCounter("received", data.Code).Inc(1)
+	from := s.Conn().RemotePeer()
+	env, err := openMsg(data)
+	if err != nil {
+		swarm.reject(s, data.Code, err)
+		return
+	}
 	handler := func() error {
+		// the messages are accepted from verified peers only, the certs are
+		// exchanged by the GetCertMsg requests
+		if err := swarm.states.check(from, env, nil, time.Now()); err != nil {
+			swarm.reject(s, data.Code, err)
+			return nil
+		}
 		switch data.Code {
-		case GetCertMsg:
-			var certs CertsMessage
-			if err := data.Decode(&certs); err != nil {
-				log.Info("Decode msg","err",err)
-				return err
-			}
-
-
-			nodeCert, err := cert.ParseCert(certs.NodeCert)
-			if err != nil {
-				log.Info("ParseCert","err",err)
-				return fmt.Errorf("parse node cert: %w", err)
-			}
-
-			agencyCert, err := cert.ParseCert(certs.AgencyCert)
-			if err != nil {
-				log.Info("ParseCert","err",err)
-				return fmt.Errorf("parse agency cert: %w", err)
-			}
-			if err := verifyCerts(nodeCert, agencyCert, swarm.repo.Certs.CACert); err != nil {
-				log.Info("ParseCert","err",err)
-				return fmt.Errorf("verify certs: %w", err)
-			}
-
-			for _, addr := range swarm.peers {
-				if addr.ID.String() ==  certs.Id {
-					swarm.connectedPeers.Store(addr.ID,addr)
-				}
-			}
-			//swarm.connectedPeers.Store()
-			//TODO 网络拓展 swarm.connectedPeers.RemoteStore(certs.Id,addr)
-			return swarm.handleFetchCertMessage(s)
-		case CertMsg:
-
-
 		case CtxSignMsg:
 			var ev core.CrossTransaction
-			data.Decode(&ev)
+			if err := env.message().Decode(&ev); err != nil {
+				return fmt.Errorf("decode ctx: %w", err)
+			}
 			swarm.messageCh <- &ev
 		case RtxSignMsg:
 			var er core.ReceptTransaction
-			data.Decode(&er)
+			if err := env.message().Decode(&er); err != nil {
+				return fmt.Errorf("decode rtx: %w", err)
+			}
+			swarm.messageCh <- &er
+		case CtxRemoveMsg:
+			var ev core.RemovedCtx
+			if err := env.message().Decode(&ev); err != nil {
+				return fmt.Errorf("decode removed ctx: %w", err)
+			}
+			swarm.messageCh <- &ev
+		case RtxRevertMsg:
+			var er core.RevertedRtx
+			if err := env.message().Decode(&er); err != nil {
+				return fmt.Errorf("decode reverted rtx: %w", err)
+			}
 			swarm.messageCh <- &er
 		default:
 			log.Info("can't handle msg","code",data.Code)
@@ -69,27 +68,133 @@ func (swarm *Swarm) handleMessage(s network.Stream, data *hubnet.Msg) {
 	}
 }
 
+// reject drops the message of code received on s for err.
+func (swarm *Swarm) reject(s network.Stream, code uint16, err error) {
+	rejectCounter(err).Inc(1)
+	log.Info("Reject message", "peer", s.Conn().RemotePeer().String(), "code", code, "err", err)
+}
+
+// handleGetCert answers the certs of a peer by the certs of the node. The
+// stream of a peer failing the certificate handshake is closed, a peer
+// presenting the certificate of another is disconnected.
+func (swarm *Swarm) handleGetCert(s network.Stream, req *hubnet.Msg) (*hubnet.Msg, error) {
+	msgCounter("received", req.Code).Inc(1)
+	from := s.Conn().RemotePeer()
+	env, err := openMsg(req)
+	if err == nil {
+		err = swarm.handshake(from, env)
+	}
+	if err != nil {
+		swarm.reject(s, req.Code, err)
+		if err := s.Reset(); err != nil {
+			log.Info("Reset stream", "err", err)
+		}
+		if errors.Is(err, errPeerKey) {
+			swarm.disconnect(from, err)
+		}
+		return nil, err
+	}
+
+	if id, ok := swarm.peerIndex(from); ok {
+		swarm.setState(id, core.PeerConnected, "certs verified by the peer")
+	}
+	//TODO 网络拓展 swarm.connectedPeers.RemoteStore(certs.Id,addr)
+	msg, err := hubnet.NewMsg(CertMsg, swarm.certsMessage())
+	if err != nil {
+		return nil, err
+	}
+	msgCounter("sent", msg.Code).Inc(1)
+	return swarm.seal(msg)
+}
+
+// disconnect closes the connections of the peer id which failed to prove the
+// identity of its certificate for err.
+func (swarm *Swarm) disconnect(id peer.ID, err error) {
+	log.Error("Disconnect peer", "peer", id.String(), "err", err)
+	if i, ok := swarm.peerIndex(id); ok {
+		swarm.setState(i, core.PeerDisconnected, err.Error())
+	}
+	if err := swarm.p2p.Disconnect(&peer.AddrInfo{ID: id}); err != nil {
+		log.Info("Disconnect", "err", err)
+	}
+}
+
+// handshake verifies the certificates in the envelope env of the peer from and
+// the signature of env by its node certificate, the messages of the peer are
+// accepted from then on.
+func (swarm *Swarm) handshake(from peer.ID, env *Envelope) error {
+	var certs CertsMessage
+	if err := env.message().Decode(&certs); err != nil {
+		return fmt.Errorf("%w: %v", errMalformed, err)
+	}
+	if certs.Id != from.String() {
+		return errSender
+	}
+
+	nodeCert, err := cert.ParseCert(certs.NodeCert)
+	if err != nil {
+		return fmt.Errorf("parse node cert: %w", err)
+	}
+
+	agencyCert, err := cert.ParseCert(certs.AgencyCert)
+	if err != nil {
+		return fmt.Errorf("parse agency cert: %w", err)
+	}
+	if err := verifyCerts(nodeCert, agencyCert, swarm.repo.Certs.CACert); err != nil {
+		return fmt.Errorf("verify certs: %w", err)
+	}
+
+	key, ok := nodeCert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("node cert key is not an ecdsa key")
+	}
+	// the connection is authenticated by the libp2p key of the peer, it must
+	// be the key of the certificate presented
+	remote, err := swarm.p2p.GetRemotePubKey(from)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPeerKey, err)
+	}
+	if err := matchPeerKey(from, remote, key); err != nil {
+		return err
+	}
+	return swarm.states.check(from, env, key, time.Now())
+}
+
+// matchPeerKey checks that remote, the libp2p key of the peer id, is the node
+// certificate key.
+func matchPeerKey(id peer.ID, remote crypto.PubKey, key *ecdsa.PublicKey) error {
+	if !id.MatchesPublicKey(remote) {
+		return fmt.Errorf("%w: libp2p key of another peer", errPeerKey)
+	}
+	raw, err := remote.Raw()
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPeerKey, err)
+	}
+	pub, err := x509.ParsePKIXPublicKey(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPeerKey, err)
+	}
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok || ecdsaPub.Curve.Params().Name != key.Curve.Params().Name ||
+		ecdsaPub.X.Cmp(key.X) != 0 || ecdsaPub.Y.Cmp(key.Y) != 0 {
+		return errPeerKey
+	}
+	return nil
+}
+
 type CertsMessage struct {
 	Id         string
 	AgencyCert []byte
 	NodeCert   []byte
 }
 
-func (swarm *Swarm) handleFetchCertMessage(s network.Stream) error {
-	certs := &CertsMessage{
+// certsMessage returns the certs of the node.
+func (swarm *Swarm) certsMessage() *CertsMessage {
+	return &CertsMessage{
 		Id:         swarm.repo.NetworkConfig.PeerId,
 		AgencyCert: swarm.repo.Certs.AgencyCertData,
 		NodeCert:   swarm.repo.Certs.NodeCertData,
 	}
-	msg, err := hubnet.NewMsg(CertMsg,certs)
-	if err != nil {
-		return err
-	}
-	err = swarm.SendWithStream(s, msg)
-	if err != nil {
-		return fmt.Errorf("send msg: %w", err)
-	}
-	return nil
 }
 
 func verifyCerts(nodeCert *x509.Certificate, agencyCert *x509.Certificate, caCert *x509.Certificate) error {
@@ -0,0 +1,51 @@
+package swarm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+func TestMatchPeerKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, remote, err := crypto.ECDSAKeyPairFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPublicKey(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherRemote, err := crypto.ECDSAKeyPairFromKey(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherID, err := peer.IDFromPublicKey(otherRemote)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := matchPeerKey(id, remote, &key.PublicKey); err != nil {
+		t.Fatalf("own key: %v", err)
+	}
+	// a valid certificate of another node
+	if err := matchPeerKey(id, remote, &other.PublicKey); !errors.Is(err, errPeerKey) {
+		t.Fatalf("certificate of another node: %v", err)
+	}
+	// a libp2p key not of the stream peer
+	if err := matchPeerKey(otherID, remote, &key.PublicKey); !errors.Is(err, errPeerKey) {
+		t.Fatalf("key of another peer: %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/simplechain-org/crosshub/core"
+	"github.com/simplechain-org/crosshub/hubnet"
+
+	"github.com/simplechain-org/go-simplechain/event"
+	"github.com/simplechain-org/go-simplechain/log"
+)
+
+var (
+	heartbeatInterval = 5 * time.Second
+	heartbeatTimeout  = 3 * time.Second
+	// maxMissedHeartbeats is the number of heartbeats in a row a peer may miss
+	// before it is disconnected
+	maxMissedHeartbeats = 3
+
+	minBackoff = 1 * time.Second
+	maxBackoff = 1 * time.Minute
+)
+
+// maxPeerEvents is the number of the latest peer events kept.
+const maxPeerEvents = 128
+
+// liveness is the state of a peer of the swarm. down is signalled when the
+// peer disconnects.
+type liveness struct {
+	status core.PeerStatus
+	missed int
+	down   chan struct{}
+}
+
+// PeerStatus returns the liveness of the peer id.
+func (swarm *Swarm) PeerStatus(id uint64) core.PeerStatus {
+	swarm.liveMu.Lock()
+	defer swarm.liveMu.Unlock()
+	if l, ok := swarm.liveness[id]; ok {
+		return l.status
+	}
+	return core.PeerStatus{}
+}
+
+// PeerEvents returns the latest changes of the states of the peers, oldest
+// first.
+func (swarm *Swarm) PeerEvents() []core.PeerEvent {
+	swarm.liveMu.Lock()
+	defer swarm.liveMu.Unlock()
+	return append([]core.PeerEvent(nil), swarm.events...)
+}
+
+// SubscribePeerEvents sends the changes of the states of the peers to ch.
+func (swarm *Swarm) SubscribePeerEvents(ch chan<- core.PeerEvent) event.Subscription {
+	return swarm.peerFeed.Subscribe(ch)
+}
+
+// setState moves the peer id to state for reason. Only connected peers can
+// disconnect, the peers connecting are retried instead.
+func (swarm *Swarm) setState(id uint64, state core.PeerState, reason string) {
+	swarm.liveMu.Lock()
+	l, ok := swarm.liveness[id]
+	if !ok || l.status.State == state ||
+		state == core.PeerDisconnected && l.status.State != core.PeerConnected {
+		swarm.liveMu.Unlock()
+		return
+	}
+	now := time.Now()
+	addr := swarm.peers[id]
+	l.status.State, l.status.Since, l.missed = state, now, 0
+	switch state {
+	case core.PeerConnected:
+		l.status.LastSeen = now
+		swarm.connectedPeers.Store(addr.ID, addr)
+	case core.PeerDisconnected:
+		swarm.connectedPeers.Delete(addr.ID)
+		select {
+		case l.down <- struct{}{}:
+		default:
+		}
+	}
+	ev := core.PeerEvent{Id: id, PeerId: addr.ID.String(), State: state, Time: now, Reason: reason}
+	swarm.events = append(swarm.events, ev)
+	if len(swarm.events) > maxPeerEvents {
+		swarm.events = swarm.events[len(swarm.events)-maxPeerEvents:]
+	}
+	swarm.liveMu.Unlock()
+
+	peerEventCounter(state).Inc(1)
+	log.Info("Peer state", "id", id, "peer", addr.ID.String(), "state", state, "reason", reason)
+	swarm.peerFeed.Send(ev)
+}
+
+// peerIndex returns the id of the peer pid.
+func (swarm *Swarm) peerIndex(pid peer.ID) (uint64, bool) {
+	for id, addr := range swarm.peers {
+		if addr.ID == pid {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// maintain connects the peer id and verifies its certs until it succeeds,
+// with an exponential backoff. It does so again whenever the peer disconnects,
+// until the swarm stops.
+func (swarm *Swarm) maintain(id uint64) {
+	addr := swarm.peers[id]
+	backoff := minBackoff
+	for attempt := 1; ; attempt++ {
+		// the peer may have verified the certs itself
+		if swarm.PeerStatus(id).State != core.PeerConnected {
+			swarm.setState(id, core.PeerConnecting, "")
+			err := swarm.p2p.Connect(addr)
+			if err == nil {
+				err = swarm.verifyCert(id)
+			}
+			if err != nil {
+				if attempt%5 == 0 {
+					log.Error("Connect peer", "id", id, "attempt", attempt, "err", err)
+				}
+				select {
+				case <-time.After(backoff):
+				case <-swarm.ctx.Done():
+					return
+				}
+				if backoff *= 2; backoff > maxBackoff {
+					backoff = maxBackoff
+				}
+				continue
+			}
+			swarm.setState(id, core.PeerConnected, "certs verified")
+		}
+		attempt, backoff = 0, minBackoff
+
+		select {
+		case <-swarm.down(id):
+		case <-swarm.ctx.Done():
+			return
+		}
+	}
+}
+
+func (swarm *Swarm) down(id uint64) <-chan struct{} {
+	swarm.liveMu.Lock()
+	defer swarm.liveMu.Unlock()
+	return swarm.liveness[id].down
+}
+
+// heartbeat pings the connected peers until the swarm stops.
+func (swarm *Swarm) heartbeat() {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for id := range swarm.peers {
+				if swarm.PeerStatus(id).State == core.PeerConnected {
+					go swarm.ping(id)
+				}
+			}
+		case <-swarm.ctx.Done():
+			return
+		}
+	}
+}
+
+// ping sends a heartbeat to the peer id, a peer missing maxMissedHeartbeats
+// in a row is disconnected.
+func (swarm *Swarm) ping(id uint64) {
+	ctx, cancel := context.WithTimeout(swarm.ctx, heartbeatTimeout)
+	defer cancel()
+
+	start := time.Now()
+	msg, err := hubnet.NewMsg(PingMsg, uint64(start.UnixNano()))
+	if err != nil {
+		log.Info("NewMsg", "err", err)
+		return
+	}
+	if _, err = swarm.Request(ctx, id, msg); err != nil {
+		heartbeatFailures().Inc(1)
+		swarm.liveMu.Lock()
+		l := swarm.liveness[id]
+		l.missed++
+		missed := l.missed
+		swarm.liveMu.Unlock()
+		log.Info("Heartbeat", "id", id, "missed", missed, "err", err)
+		if missed >= maxMissedHeartbeats {
+			swarm.setState(id, core.PeerDisconnected, fmt.Sprintf("%d heartbeats missed: %v", missed, err))
+			// the streams of the peer are dead
+			if err := swarm.p2p.Disconnect(swarm.peers[id]); err != nil {
+				log.Info("Disconnect", "err", err)
+			}
+		}
+		return
+	}
+
+	rtt := time.Since(start)
+	heartbeatRTT().Update(rtt)
+	swarm.liveMu.Lock()
+	l := swarm.liveness[id]
+	l.missed = 0
+	l.status.LastSeen, l.status.RTT = time.Now(), rtt
+	swarm.liveMu.Unlock()
+}
+
+// handlePing answers the heartbeats of the peers.
+func (swarm *Swarm) handlePing(s network.Stream, req *hubnet.Msg) (*hubnet.Msg, error) {
+	return req, nil
+}
+
+// handleDisconnect moves the peer pid whose connections closed to
+// disconnected.
+func (swarm *Swarm) handleDisconnect(pid peer.ID) {
+	if id, ok := swarm.peerIndex(pid); ok {
+		swarm.setState(id, core.PeerDisconnected, "connection closed")
+	}
+}
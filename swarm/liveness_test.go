@@ -0,0 +1,73 @@
+package swarm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/simplechain-org/crosshub/core"
+)
+
+func newLivenessSwarm(ids ...uint64) *Swarm {
+	swarm := &Swarm{peers: make(map[uint64]*peer.AddrInfo), liveness: make(map[uint64]*liveness)}
+	for _, id := range ids {
+		swarm.peers[id] = &peer.AddrInfo{ID: peer.ID(string(rune('a' + id)))}
+		swarm.liveness[id] = &liveness{
+			status: core.PeerStatus{State: core.PeerDisconnected},
+			down:   make(chan struct{}, 1),
+		}
+	}
+	return swarm
+}
+
+func TestSwarm_SetState(t *testing.T) {
+	swarm := newLivenessSwarm(1, 2)
+	events := make(chan core.PeerEvent, 10)
+	sub := swarm.SubscribePeerEvents(events)
+	defer sub.Unsubscribe()
+
+	swarm.setState(1, core.PeerConnecting, "")
+	// a peer connecting does not disconnect, it is retried
+	swarm.setState(1, core.PeerDisconnected, "dial failed")
+	if state := swarm.PeerStatus(1).State; state != core.PeerConnecting {
+		t.Fatalf("state %s", state)
+	}
+
+	swarm.setState(1, core.PeerConnected, "certs verified")
+	swarm.setState(1, core.PeerConnected, "certs verified by the peer")
+	if !swarm.Connected(1) || swarm.Connected(2) {
+		t.Fatal("connected peers not updated")
+	}
+	if status := swarm.PeerStatus(1); status.LastSeen.IsZero() || status.Since.IsZero() {
+		t.Fatalf("status %+v", status)
+	}
+
+	swarm.handleDisconnect(swarm.peers[1].ID)
+	if swarm.Connected(1) {
+		t.Fatal("disconnected peer still connected")
+	}
+	select {
+	case <-swarm.down(1):
+	default:
+		t.Fatal("disconnect not signalled")
+	}
+
+	var states []core.PeerState
+	for len(states) < 3 {
+		select {
+		case ev := <-events:
+			if ev.Id != 1 || ev.PeerId != swarm.peers[1].ID.String() {
+				t.Fatalf("event %+v", ev)
+			}
+			states = append(states, ev.State)
+		case <-time.After(time.Second):
+			t.Fatalf("events %v", states)
+		}
+	}
+	if states[0] != core.PeerConnecting || states[1] != core.PeerConnected || states[2] != core.PeerDisconnected {
+		t.Fatalf("events %v", states)
+	}
+	if history := swarm.PeerEvents(); len(history) != 3 || history[2].Reason != "connection closed" {
+		t.Fatalf("history %+v", history)
+	}
+}
@@ -0,0 +1,76 @@
+package swarm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/simplechain-org/crosshub/core"
+
+	"github.com/simplechain-org/go-simplechain/metrics"
+)
+
+// Names of the swarm metrics, the message counters are named by msgCounter.
+const (
+	broadcastFailures = "swarm/broadcast/failures"
+	connectedPeers    = "swarm/peers"
+)
+
+var msgNames = map[uint16]string{
+	GetCertMsg:   "getcert",
+	CertMsg:      "cert",
+	CtxSignMsg:   "ctxsign",
+	RtxSignMsg:   "rtxsign",
+	PingMsg:      "ping",
+	CtxRemoveMsg: "ctxremove",
+	RtxRevertMsg: "rtxrevert",
+}
+
+// msgCounter returns the counter of the messages of code sent or received, it
+// is a stub unless metrics are enabled.
+func msgCounter(direction string, code uint16) metrics.Counter {
+	name, ok := msgNames[code]
+	if !ok {
+		name = fmt.Sprintf("code%d", code)
+	}
+	return metrics.GetOrRegisterCounter(fmt.Sprintf("swarm/%s/%s", direction, name), nil)
+}
+
+// rejectCounter returns the counter of the messages rejected for err, the
+// failures of the certificate handshake are counted as handshake.
+func rejectCounter(err error) metrics.Counter {
+	reason := "handshake"
+	for e, name := range rejectReasons {
+		if errors.Is(err, e) {
+			reason = name
+			break
+		}
+	}
+	return metrics.GetOrRegisterCounter("swarm/rejected/"+reason, nil)
+}
+
+// peerEventCounter returns the counter of the peers moved to state.
+func peerEventCounter(state core.PeerState) metrics.Counter {
+	return metrics.GetOrRegisterCounter("swarm/peer/"+string(state), nil)
+}
+
+// heartbeatFailures returns the counter of the heartbeats not answered.
+func heartbeatFailures() metrics.Counter {
+	return metrics.GetOrRegisterCounter("swarm/heartbeat/failures", nil)
+}
+
+// heartbeatRTT returns the timer of the round trips of the heartbeats.
+func heartbeatRTT() metrics.Timer {
+	return metrics.GetOrRegisterTimer("swarm/heartbeat/rtt", nil)
+}
+
+// registerMetrics registers the count of the connected peers of the swarm.
+func (swarm *Swarm) registerMetrics() {
+	metrics.NewRegisteredFunctionalGauge(connectedPeers, nil, func() int64 {
+		var n int64
+		swarm.connectedPeers.Range(func(key, value interface{}) bool {
+			n++
+			return true
+		})
+		return n
+	})
+}
@@ -5,4 +5,7 @@ const (
 	CertMsg           = 0x02
 	CtxSignMsg        = 0x03
 	RtxSignMsg        = 0x04
+	PingMsg           = 0x05
+	CtxRemoveMsg      = 0x06
+	RtxRevertMsg      = 0x07
 )
@@ -2,35 +2,50 @@ package swarm
 
 import (
 	"context"
+	stdecdsa "crypto/ecdsa"
+	"errors"
 	"fmt"
-	"github.com/simplechain-org/crosshub/cert"
 	"github.com/simplechain-org/crosshub/core"
 
 	//"github.com/meshplus/bitxhub-kit/network"
 	"github.com/simplechain-org/crosshub/hubnet"
+	"github.com/simplechain-org/go-simplechain/crypto/ecdsa"
+	"github.com/simplechain-org/go-simplechain/event"
 	"github.com/simplechain-org/go-simplechain/log"
+	"github.com/simplechain-org/go-simplechain/metrics"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/Rican7/retry"
-	"github.com/Rican7/retry/strategy"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	"github.com/simplechain-org/crosshub/repo"
-	//"github.com/simplechain-org/crosshub/cert"
 )
 
 const (
 	//protocolID protocol.ID = "/SimpleChain/CrossHub/1.0.0" // magic protocol
-	protocolID protocol.ID = "/SimpleChain/CrossHub/1.0.0"
+	// the major version is the hubnet.FrameVersion, hubs of other versions
+	// can't open streams
+	protocolID protocol.ID = "/SimpleChain/CrossHub/2.0.0"
 )
 
 type Swarm struct {
+	// seq is the sequence number of the last envelope sent, it starts at the
+	// time the swarm is created to keep increasing across restarts
+	seq            uint64
+	key            *stdecdsa.PrivateKey
+	states         *peerStates
 	repo           *repo.Repo
 	p2p            hubnet.Network
 	peers          map[uint64]*peer.AddrInfo
 	connectedPeers sync.Map
+	// liveness keeps the states of the peers by id, events the latest
+	// changes of them
+	liveness       map[uint64]*liveness
+	events         []core.PeerEvent
+	liveMu         sync.Mutex
+	peerFeed       event.Feed
 	eventCh        <-chan interface{}
 	messageCh      chan<- interface{}
 
@@ -43,66 +58,65 @@ func New(repo *repo.Repo,messageCh  chan<- interface{},eventCh <-chan interface{
 		hubnet.WithLocalAddr(repo.NetworkConfig.LocalAddr),
 		hubnet.WithPrivateKey(repo.Key.Libp2pPrivKey),
 		hubnet.WithProtocolID(protocolID),
+		hubnet.WithMaxMsgSize(repo.Config.P2P.MaxMsgSize),
+		hubnet.WithCompression(repo.Config.P2P.Compress),
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("create p2p: %w", err)
 	}
 
+	key, ok := repo.Key.PrivKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("node key is not an ecdsa key")
+	}
+	if pub, ok := repo.Certs.NodeCert.PublicKey.(*stdecdsa.PublicKey); !ok ||
+		pub.X.Cmp(key.K.X) != 0 || pub.Y.Cmp(key.K.Y) != 0 {
+		return nil, fmt.Errorf("node key does not match the node cert")
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Swarm{
+	swarm := &Swarm{
+		seq:            uint64(time.Now().UnixNano()),
+		key:            key.K,
+		states:         newPeerStates(),
 		repo:           repo,
 		p2p:            p2p,
 		peers:          repo.NetworkConfig.OtherNodes,
 		connectedPeers: sync.Map{},
+		liveness:       make(map[uint64]*liveness),
 		eventCh:        eventCh,
 		messageCh:      messageCh,
 		ctx:            ctx,
 		cancel:         cancel,
-	}, nil
+	}
+	now := time.Now()
+	for id := range swarm.peers {
+		swarm.liveness[id] = &liveness{
+			status: core.PeerStatus{State: core.PeerDisconnected, Since: now},
+			down:   make(chan struct{}, 1),
+		}
+	}
+	swarm.registerMetrics()
+	return swarm, nil
 }
 
 func (swarm *Swarm) Start() error {
 	swarm.p2p.SetMessageHandler(swarm.handleMessage)
+	swarm.p2p.RegisterHandler(GetCertMsg, swarm.handleGetCert)
+	swarm.RegisterHandler(PingMsg, swarm.handlePing)
+	swarm.p2p.SetDisconnectCallback(swarm.handleDisconnect)
 
 	if err := swarm.p2p.Start(); err != nil {
 		return err
 	}
 
 	log.Info("Start","peers",len(swarm.peers))
-	for id, addr := range swarm.peers {
-		if _,ok := swarm.connectedPeers.Load(addr.ID);!ok {
-			go func(id uint64, addr *peer.AddrInfo) {
-				log.Info("try connet","id",id,"addr",addr.String())
-				if err := retry.Retry(func(attempt uint) error {
-					if err := swarm.p2p.Connect(addr); err != nil {
-						//log.Info("Connect","err",err)
-						return err
-					}
-
-					if err := swarm.verifyCert(id); err != nil {
-						if attempt != 0 && attempt%5 == 0 {
-							log.Error("Verify cert","err",err)
-						}
-						log.Info("verifyCert","err",err)
-						return err
-					}
-
-					log.Info("Connect successfully","id",id)
-
-					swarm.connectedPeers.Store(addr.ID, addr)
-
-					return nil
-				},
-					strategy.Wait(1*time.Second),
-				); err != nil {
-					log.Error("retry.Retry","err",err)
-				}
-			}(id, addr)
-		}
-
+	for id := range swarm.peers {
+		go swarm.maintain(id)
 	}
+	go swarm.heartbeat()
 	log.Info("Start successfully")
 
 	go func() {
@@ -125,6 +139,22 @@ func (swarm *Swarm) Start() error {
 					swarm.Broadcast(mm)
 				}
 
+				if removed,ok := ev.(*core.RemovedCtx);ok {
+					mm,err := hubnet.NewMsg(CtxRemoveMsg,removed)
+					if err != nil {
+						log.Info("NewMsg",err)
+					}
+					swarm.Broadcast(mm)
+				}
+
+				if reverted,ok := ev.(*core.RevertedRtx);ok {
+					mm,err := hubnet.NewMsg(RtxRevertMsg,reverted)
+					if err != nil {
+						log.Info("NewMsg",err)
+					}
+					swarm.Broadcast(mm)
+				}
+
 			}
 		}
 	}()
@@ -142,26 +172,100 @@ func (swarm *Swarm) AsyncSend(id uint64, msg *hubnet.Msg) error {
 	if err := swarm.checkID(id); err != nil {
 		return fmt.Errorf("p2p send: %w", err)
 	}
-	return swarm.p2p.AsyncSend(swarm.peers[id], msg)
+	sealed, err := swarm.seal(msg)
+	if err != nil {
+		return err
+	}
+	if err := swarm.p2p.AsyncSend(swarm.peers[id], sealed); err != nil {
+		return err
+	}
+	msgCounter("sent", msg.Code).Inc(1)
+	return nil
 }
 
 func (swarm *Swarm) SendWithStream(s network.Stream, msg *hubnet.Msg) error {
-	return swarm.p2p.SendWithStream(s, msg)
+	sealed, err := swarm.seal(msg)
+	if err != nil {
+		return err
+	}
+	if err := swarm.p2p.SendWithStream(s, sealed); err != nil {
+		return err
+	}
+	msgCounter("sent", msg.Code).Inc(1)
+	return nil
 }
 
+// Send sends the request msg to the peer id and returns its response sealed in
+// an envelope, see Request.
 func (swarm *Swarm) Send(id uint64, msg *hubnet.Msg) (*hubnet.Msg, error) {
 	if err := swarm.checkID(id); err != nil {
 		return nil, fmt.Errorf("check id: %w", err)
 	}
 
-	ret, err := swarm.p2p.Send(swarm.peers[id], msg)
+	sealed, err := swarm.seal(msg)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := swarm.p2p.Send(swarm.peers[id], sealed)
 	if err != nil {
 		return nil, fmt.Errorf("sync send: %w", err)
 	}
+	msgCounter("sent", msg.Code).Inc(1)
 
 	return ret, nil
 }
 
+// Request sends the request msg to the verified peer id and returns its
+// response, which must be sealed by the peer. It fails if ctx is done first.
+func (swarm *Swarm) Request(ctx context.Context, id uint64, msg *hubnet.Msg) (*hubnet.Msg, error) {
+	if err := swarm.checkID(id); err != nil {
+		return nil, fmt.Errorf("check id: %w", err)
+	}
+
+	sealed, err := swarm.seal(msg)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := swarm.p2p.Request(ctx, swarm.peers[id], sealed)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+	msgCounter("sent", msg.Code).Inc(1)
+	msgCounter("received", ret.Code).Inc(1)
+
+	env, err := openMsg(ret)
+	if err == nil {
+		err = swarm.states.check(swarm.peers[id].ID, env, nil, time.Now())
+	}
+	if err != nil {
+		rejectCounter(err).Inc(1)
+		return nil, fmt.Errorf("response: %w", err)
+	}
+	return env.message(), nil
+}
+
+// RegisterHandler serves the requests of code of the verified peers by
+// handler, the requests and the responses are sealed in envelopes.
+func (swarm *Swarm) RegisterHandler(code uint16, handler hubnet.RequestHandler) {
+	swarm.p2p.RegisterHandler(code, func(s network.Stream, req *hubnet.Msg) (*hubnet.Msg, error) {
+		msgCounter("received", req.Code).Inc(1)
+		env, err := openMsg(req)
+		if err == nil {
+			err = swarm.states.check(s.Conn().RemotePeer(), env, nil, time.Now())
+		}
+		if err != nil {
+			swarm.reject(s, req.Code, err)
+			return nil, err
+		}
+		resp, err := handler(s, env.message())
+		if err != nil {
+			return nil, err
+		}
+		msgCounter("sent", resp.Code).Inc(1)
+		return swarm.seal(resp)
+	})
+}
+
 func (swarm *Swarm) Broadcast(msg *hubnet.Msg) error {
 	var addrs []*peer.AddrInfo
 	//for _, addr := range swarm.peers {
@@ -180,7 +284,21 @@ func (swarm *Swarm) Broadcast(msg *hubnet.Msg) error {
 	})
 	log.Info("Broadcast","len",len(addrs))
 
-	return swarm.p2p.Broadcast(addrs, msg)
+	sealed, err := swarm.seal(msg)
+	if err != nil {
+		return err
+	}
+
+	// peers failing are skipped, like hubnet Broadcast
+	for _, addr := range addrs {
+		if err := swarm.p2p.AsyncSend(addr, sealed); err != nil {
+			log.Info("Broadcast", "peer", addr.ID.String(), "err", err)
+			metrics.GetOrRegisterCounter(broadcastFailures, nil).Inc(1)
+			continue
+		}
+		msgCounter("sent", msg.Code).Inc(1)
+	}
+	return nil
 }
 
 func (swarm *Swarm) Peers() map[uint64]*peer.AddrInfo {
@@ -199,6 +317,16 @@ func (swarm *Swarm) OtherPeers() map[uint64]*peer.AddrInfo {
 	return m
 }
 
+// Connected reports whether the peer id is connected and its certs verified.
+func (swarm *Swarm) Connected(id uint64) bool {
+	addr, ok := swarm.peers[id]
+	if !ok {
+		return false
+	}
+	_, ok = swarm.connectedPeers.Load(addr.ID)
+	return ok
+}
+
 //func (swarm *Swarm) SubscribeOrderMessage(ch chan<- events.OrderMessageEvent) event.Subscription {
 //	return swarm.orderMessageFeed.Subscribe(ch)
 //}
@@ -207,13 +335,7 @@ func (swarm *Swarm) verifyCert(id uint64) error {
 	if err := swarm.checkID(id); err != nil {
 		return fmt.Errorf("check id: %w", err)
 	}
-	selfCerts := &CertsMessage{
-		Id:         swarm.repo.NetworkConfig.PeerId,
-		AgencyCert: swarm.repo.Certs.AgencyCertData,
-		NodeCert:   swarm.repo.Certs.NodeCertData,
-	}
-
-	msg,err := hubnet.NewMsg(GetCertMsg,selfCerts)
+	msg,err := hubnet.NewMsg(GetCertMsg,swarm.certsMessage())
 	if err != nil {
 		return err
 	}
@@ -221,28 +343,30 @@ func (swarm *Swarm) verifyCert(id uint64) error {
 	if err != nil {
 		return fmt.Errorf("sync send: %w", err)
 	}
-	var certs CertsMessage
-	ret.Decode(&certs)
-	nodeCert, err := cert.ParseCert(certs.NodeCert)
-	if err != nil {
-		return fmt.Errorf("parse node cert: %w", err)
+	env, err := openMsg(ret)
+	if err == nil && env.Code != CertMsg {
+		err = fmt.Errorf("%w: code %d in reply to the certs", errMalformed, env.Code)
 	}
-
-	agencyCert, err := cert.ParseCert(certs.AgencyCert)
-	if err != nil {
-		return fmt.Errorf("parse agency cert: %w", err)
+	if err == nil {
+		err = swarm.handshake(swarm.peers[id].ID, env)
 	}
-
-	if err := verifyCerts(nodeCert, agencyCert, swarm.repo.Certs.CACert); err != nil {
+	if err != nil {
+		rejectCounter(err).Inc(1)
+		if errors.Is(err, errPeerKey) {
+			swarm.disconnect(swarm.peers[id].ID, err)
+		}
 		return fmt.Errorf("verify certs: %w", err)
 	}
+	return nil
+}
 
-
-	err = swarm.p2p.Disconnect(swarm.peers[id])
+// seal wraps msg in an envelope signed by the node key.
+func (swarm *Swarm) seal(msg *hubnet.Msg) (*hubnet.Msg, error) {
+	sealed, err := sealMsg(swarm.key, swarm.repo.NetworkConfig.PeerId, atomic.AddUint64(&swarm.seq, 1), time.Now(), msg)
 	if err != nil {
-		return fmt.Errorf("disconnect peer: %w", err)
+		return nil, fmt.Errorf("seal msg: %w", err)
 	}
-	return nil
+	return sealed, nil
 }
 
 func (swarm *Swarm) checkID(id uint64) error {